@@ -0,0 +1,158 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"time"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/lelopez-io/moxli/internal/archive"
+	"github.com/lelopez-io/moxli/internal/bookmark"
+	"github.com/lelopez-io/moxli/internal/fetch"
+	"github.com/lelopez-io/moxli/internal/linkcheck"
+	"github.com/lelopez-io/moxli/internal/session"
+)
+
+func archiveCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "archive",
+		Usage: "Save offline snapshots of the bookmarked pages",
+		Description: `Fetches the page of every http(s) bookmark, or those matching QUERY, and
+saves a snapshot of it in the snapshots directory next to the bookmark file.
+HTML snapshots are a single file with stylesheets and images inlined; WARC
+snapshots keep the page and its resources as fetched. Each bookmark records
+the snapshot's path and hash, and bookmarks without article text get the
+page's article as Markdown. Bookmarks already archived are skipped unless
+--refresh is given; filter them with has:snapshot.`,
+		ArgsUsage: "[QUERY...]",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    "file",
+				Aliases: []string{"f"},
+				Usage:   "bookmark file to archive (default: the session's current file)",
+			},
+			&cli.StringFlag{
+				Name:  "format",
+				Usage: "snapshot format: html or warc",
+				Value: string(archive.FormatHTML),
+			},
+			&cli.IntFlag{
+				Name:  "workers",
+				Usage: "archive `N` pages at once",
+				Value: 4,
+			},
+			&cli.DurationFlag{
+				Name:  "timeout",
+				Usage: "give up on a page or resource after this long",
+				Value: 30 * time.Second,
+			},
+			&cli.DurationFlag{
+				Name:  "delay",
+				Usage: "minimum time between requests to the same host",
+				Value: 200 * time.Millisecond,
+			},
+			&cli.IntFlag{
+				Name:  "max-resources",
+				Usage: "fetch at most `N` stylesheets and images per page",
+				Value: archive.DefaultMaxResources,
+			},
+			&cli.BoolFlag{
+				Name:  "refresh",
+				Usage: "archive bookmarks that already have a snapshot again",
+			},
+			&cli.StringFlag{
+				Name:    "output",
+				Aliases: []string{"o"},
+				Usage:   "write the result to `PATH` instead of replacing the file",
+			},
+		},
+		Action: func(c *cli.Context) error {
+			format := archive.Format(c.String("format"))
+			if format != archive.FormatHTML && format != archive.FormatWARC {
+				return fmt.Errorf("unknown snapshot format %q (use html or warc)", format)
+			}
+
+			manager, err := session.NewManager()
+			if err != nil {
+				return err
+			}
+			path, collection, matching, err := loadMatching(c, manager)
+			if err != nil {
+				return err
+			}
+
+			var targets []*bookmark.Bookmark
+			for _, b := range matching {
+				if linkcheck.Checkable(b.URL) && (b.Snapshot == nil || c.Bool("refresh")) {
+					targets = append(targets, b)
+				}
+			}
+			if len(targets) == 0 {
+				fmt.Println("✅ No bookmarks left to archive")
+				return nil
+			}
+
+			// Snapshot paths are relative to the file the bookmarks are saved in
+			root := filepath.Dir(path)
+			if output := c.String("output"); output != "" {
+				root = filepath.Dir(output)
+			}
+			store := archive.NewStore(root)
+			fmt.Printf("🌐 Archiving %d page(s) to %s…\n", len(targets), store.Path(archive.StoreDir))
+
+			ctx, stop := signal.NotifyContext(c.Context, os.Interrupt)
+			defer stop()
+
+			// Snapshots need the current page, so the page cache is not used
+			client := fetch.New(fetch.Options{
+				Timeout:   c.Duration("timeout"),
+				HostDelay: c.Duration("delay"),
+			})
+			archiver := archive.New(client, store, archive.Options{
+				Format:       format,
+				Workers:      c.Int("workers"),
+				MaxResources: c.Int("max-resources"),
+			})
+			results := archiver.ArchiveAll(ctx, targets, func(done, total int) {
+				fmt.Fprintf(os.Stderr, "\r   %d/%d", done, total)
+			})
+			fmt.Fprintln(os.Stderr)
+			if ctx.Err() != nil {
+				fmt.Printf("⏹️  Stopped early after %d bookmark(s)\n", len(results))
+			}
+
+			fmt.Println()
+			archived, articles := 0, 0
+			var failed []error
+			for _, r := range results {
+				if r.Err != nil {
+					failed = append(failed, r.Err)
+					continue
+				}
+				r.Bookmark.Snapshot = r.Snapshot
+				archived++
+				if r.Bookmark.Article == "" && r.Text != "" {
+					r.Bookmark.Article = r.Text
+					articles++
+				}
+			}
+
+			if len(failed) > 0 {
+				fmt.Printf("⚠️  %d page(s) could not be archived:\n", len(failed))
+				for _, err := range failed {
+					fmt.Printf("   %v\n", err)
+				}
+				fmt.Println()
+			}
+			fmt.Printf("📦 Archived %d page(s), added article text to %d\n", archived, articles)
+
+			if archived == 0 {
+				return nil
+			}
+			return saveOutput(c, path, collection, "💾 Saved to %s\n")
+		},
+	}
+}
@@ -0,0 +1,219 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/lelopez-io/moxli/internal/bookmark"
+	"github.com/lelopez-io/moxli/internal/importer"
+	"github.com/lelopez-io/moxli/internal/linkcheck"
+	"github.com/lelopez-io/moxli/internal/query"
+	"github.com/lelopez-io/moxli/internal/session"
+)
+
+func checkCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "check",
+		Usage: "Check bookmark links for dead pages and redirects",
+		Description: `Requests every http(s) bookmark, or those matching QUERY, and records the
+status code, the redirects followed and the check time on each bookmark.
+Filter the results with is:dead, is:redirected and is:checked in the TUI or
+moxli search. Press ctrl+c to stop early and keep the links checked so far.`,
+		ArgsUsage: "[QUERY...]",
+		Flags:     linkCheckFlags("check"),
+		Action: func(c *cli.Context) error {
+			path, collection, results, err := runLinkCheck(c)
+			if err != nil || len(results) == 0 {
+				return err
+			}
+
+			var dead, redirected []linkcheck.Result
+			for _, r := range results {
+				if r.Status.Dead() {
+					dead = append(dead, r)
+				} else if r.Status.Redirected() {
+					redirected = append(redirected, r)
+				}
+			}
+
+			fmt.Println()
+			if len(dead) > 0 {
+				fmt.Println("💀 Dead links:")
+				for _, r := range dead {
+					printCheckResult(r, "")
+				}
+			}
+			if len(redirected) > 0 {
+				fmt.Println("↪️  Redirected links:")
+				for _, r := range redirected {
+					printCheckResult(r, "")
+				}
+			}
+			fmt.Printf("✅ %d ok  ↪️  %d redirected  💀 %d dead\n",
+				len(results)-len(dead)-len(redirected), len(redirected), len(dead))
+
+			return saveOutput(c, path, collection, "💾 Saved link status to %s\n")
+		},
+	}
+}
+
+// linkCheckFlags returns the flags shared by the commands that check links
+func linkCheckFlags(verb string) []cli.Flag {
+	defaults := linkcheck.DefaultOptions()
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:    "file",
+			Aliases: []string{"f"},
+			Usage:   "bookmark file to " + verb + " (default: the session's current file)",
+		},
+		&cli.IntFlag{
+			Name:  "workers",
+			Usage: "check `N` links at once",
+			Value: defaults.Workers,
+		},
+		&cli.DurationFlag{
+			Name:  "timeout",
+			Usage: "give up on a link after this long",
+			Value: defaults.Timeout,
+		},
+		&cli.IntFlag{
+			Name:  "retries",
+			Usage: "retry network errors, 429 and 5xx responses `N` times",
+			Value: defaults.Retries,
+		},
+		&cli.DurationFlag{
+			Name:  "delay",
+			Usage: "minimum time between requests to the same host",
+			Value: defaults.HostDelay,
+		},
+		&cli.IntFlag{
+			Name:  "max-redirects",
+			Usage: "stop following a redirect chain after `N` redirects",
+			Value: defaults.MaxRedirects,
+		},
+		&cli.BoolFlag{
+			Name:  "dry-run",
+			Usage: "report the results without writing them to the file",
+		},
+		&cli.StringFlag{
+			Name:    "output",
+			Aliases: []string{"o"},
+			Usage:   "write the result to `PATH` instead of replacing the file",
+		},
+	}
+}
+
+// runLinkCheck checks the links of the bookmarks matching the query
+// arguments and records the status on each of them
+func runLinkCheck(c *cli.Context) (string, *bookmark.Collection, []linkcheck.Result, error) {
+	manager, err := session.NewManager()
+	if err != nil {
+		return "", nil, nil, err
+	}
+	path, collection, targets, err := loadMatching(c, manager)
+	if err != nil {
+		return "", nil, nil, err
+	}
+
+	checkable := 0
+	for _, b := range targets {
+		if linkcheck.Checkable(b.URL) {
+			checkable++
+		}
+	}
+	if checkable == 0 {
+		fmt.Println("🤷 No http(s) bookmarks to check")
+		return path, collection, nil, nil
+	}
+	fmt.Printf("🔗 Checking %d link(s)…\n", checkable)
+
+	ctx, stop := signal.NotifyContext(c.Context, os.Interrupt)
+	defer stop()
+
+	checker := linkcheck.New(linkcheck.Options{
+		Workers:      c.Int("workers"),
+		Timeout:      c.Duration("timeout"),
+		Retries:      c.Int("retries"),
+		RetryDelay:   linkcheck.DefaultOptions().RetryDelay,
+		HostDelay:    c.Duration("delay"),
+		MaxRedirects: c.Int("max-redirects"),
+	})
+	results := checker.CheckAll(ctx, targets, func(done, total int) {
+		fmt.Fprintf(os.Stderr, "\r   %d/%d", done, total)
+	})
+	fmt.Fprintln(os.Stderr)
+	if ctx.Err() != nil {
+		fmt.Printf("⏹️  Stopped early after %d bookmark(s)\n", len(results))
+	}
+
+	for _, r := range results {
+		status := r.Status
+		r.Bookmark.LinkStatus = &status
+	}
+	return path, collection, results, nil
+}
+
+// loadMatching loads the --file collection, or the session's current file,
+// and returns the bookmarks matching the query arguments
+func loadMatching(c *cli.Context, manager *session.Manager) (string, *bookmark.Collection, []*bookmark.Bookmark, error) {
+	q, err := query.Parse(strings.Join(c.Args().Slice(), " "))
+	if err != nil {
+		return "", nil, nil, fmt.Errorf("invalid query: %w", err)
+	}
+
+	path, err := sessionFile(manager, c.String("file"))
+	if err != nil {
+		return "", nil, nil, err
+	}
+
+	collection, err := importer.LoadFile(path)
+	if err != nil {
+		return "", nil, nil, fmt.Errorf("failed to load %s: %w", path, err)
+	}
+	return path, collection, q.Filter(collection.Bookmarks), nil
+}
+
+// printCheckResult prints one dead or redirected bookmark of the check
+// report, followed by note when one is given
+func printCheckResult(r linkcheck.Result, note string) {
+	title := r.Bookmark.Title
+	if title == "" {
+		title = "(no title)"
+	}
+	fmt.Printf("  %s\n", title)
+	fmt.Printf("     %s\n", r.Bookmark.URL)
+	printRedirects(r.Status)
+	fmt.Printf("     %s\n", describeLinkStatus(r.Status))
+	if note != "" {
+		fmt.Printf("     %s\n", note)
+	}
+	fmt.Println()
+}
+
+// printRedirects prints where each redirect of a chain led
+func printRedirects(s bookmark.LinkStatus) {
+	for i, r := range s.Redirects {
+		target := s.FinalURL
+		if i+1 < len(s.Redirects) {
+			target = s.Redirects[i+1].URL
+		}
+		if target == "" {
+			// A chain cut short by a loop or the redirect limit
+			continue
+		}
+		fmt.Printf("     → %d %s\n", r.StatusCode, target)
+	}
+}
+
+// describeLinkStatus renders a status code with its text, or the error
+func describeLinkStatus(s bookmark.LinkStatus) string {
+	if s.StatusCode == 0 {
+		return s.Error
+	}
+	return fmt.Sprintf("%d %s", s.StatusCode, http.StatusText(s.StatusCode))
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/lelopez-io/moxli/internal/bookmark"
+	"github.com/lelopez-io/moxli/internal/importer"
+)
+
+func dedupeCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "dedupe",
+		Usage:     "Find and consolidate duplicate bookmarks",
+		ArgsUsage: "FILE",
+		Flags: []cli.Flag{
+			&cli.BoolFlag{
+				Name:  "dry-run",
+				Usage: "report duplicate groups without writing changes",
+			},
+			&cli.BoolFlag{
+				Name:  "domain-title",
+				Usage: "also group same-domain bookmarks with identical titles",
+			},
+			&cli.BoolFlag{
+				Name:  "fuzzy",
+				Usage: "also group bookmarks with similar titles",
+			},
+			&cli.Float64Flag{
+				Name:  "threshold",
+				Usage: "minimum title similarity for --fuzzy (0-1)",
+				Value: bookmark.DefaultTitleThreshold,
+			},
+			&cli.BoolFlag{
+				Name:  "all",
+				Usage: "consolidate title-based groups too (default: report them for review)",
+			},
+			&cli.StringFlag{
+				Name:    "output",
+				Aliases: []string{"o"},
+				Usage:   "write the result to `PATH` instead of replacing FILE",
+			},
+		},
+		Action: func(c *cli.Context) error {
+			if c.NArg() != 1 {
+				return fmt.Errorf("expected exactly one bookmark file")
+			}
+			path := c.Args().First()
+
+			collection, err := importer.LoadFile(path)
+			if err != nil {
+				return fmt.Errorf("failed to load %s: %w", path, err)
+			}
+
+			groups := bookmark.FindDuplicates(collection, bookmark.DuplicateOptions{
+				DomainTitle:    c.Bool("domain-title"),
+				FuzzyTitle:     c.Bool("fuzzy"),
+				TitleThreshold: c.Float64("threshold"),
+			})
+
+			if len(groups) == 0 {
+				fmt.Println("✅ No duplicates found")
+				return nil
+			}
+
+			duplicates := 0
+			for _, g := range groups {
+				duplicates += len(g.Bookmarks) - 1
+			}
+			fmt.Printf("🔍 Found %d duplicate group(s), %d redundant bookmark(s)\n\n", len(groups), duplicates)
+
+			consolidated, review := 0, 0
+			for i, g := range groups {
+				apply := g.AutoMergeable() || c.Bool("all")
+				printDuplicateGroup(i+1, g, apply)
+
+				if !apply {
+					review++
+					continue
+				}
+				if !c.Bool("dry-run") {
+					collection.ApplyConsolidation(g, g.Consolidate())
+				}
+				consolidated++
+			}
+
+			if review > 0 {
+				fmt.Printf("💡 %d group(s) need review (use --all or the TUI duplicate view)\n", review)
+			}
+
+			if c.Bool("dry-run") {
+				fmt.Printf("🧪 Dry run: %d group(s) would be consolidated\n", consolidated)
+				return nil
+			}
+			if consolidated == 0 {
+				return nil
+			}
+
+			fmt.Printf("✅ Consolidated %d group(s), %d bookmarks remain\n", consolidated, len(collection.Bookmarks))
+			return saveOutput(c, path, collection, "💾 Saved to %s\n")
+		},
+	}
+}
+
+// printDuplicateGroup prints one group of the dedupe report
+func printDuplicateGroup(n int, g *bookmark.DuplicateGroup, apply bool) {
+	status := "needs review"
+	if apply {
+		status = "consolidate"
+	}
+	fmt.Printf("Group %d (%s, %s): %s\n", n, g.Reason, status, g.Key)
+
+	for i, b := range g.Bookmarks {
+		marker := "   "
+		if i == g.Survivor {
+			marker = " ★ "
+		}
+
+		title := b.Title
+		if title == "" {
+			title = "(no title)"
+		}
+
+		added := "unknown date"
+		if !b.DateAdded.IsZero() {
+			added = b.DateAdded.Format("2006-01-02")
+		}
+
+		fmt.Printf("  %s%s [%s, %s]\n", marker, title, b.Source, added)
+		fmt.Printf("     %s\n", b.URL)
+	}
+	fmt.Println()
+}
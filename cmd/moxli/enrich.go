@@ -0,0 +1,148 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"time"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/lelopez-io/moxli/internal/bookmark"
+	"github.com/lelopez-io/moxli/internal/enrich"
+	"github.com/lelopez-io/moxli/internal/fetch"
+	"github.com/lelopez-io/moxli/internal/linkcheck"
+	"github.com/lelopez-io/moxli/internal/session"
+)
+
+func enrichCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "enrich",
+		Usage: "Fill in missing titles and descriptions from the bookmarked pages",
+		Description: `Fetches the page of every http(s) bookmark, or those matching QUERY, that
+lacks a title, description, canonical link or favicon, and fills only the
+empty fields from the page's <title>, og:title, meta description,
+og:description, canonical link and icon. Pages are cached in
+~/.moxli/cache/pages, so running again only fetches what expired.`,
+		ArgsUsage: "[QUERY...]",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    "file",
+				Aliases: []string{"f"},
+				Usage:   "bookmark file to enrich (default: the session's current file)",
+			},
+			&cli.IntFlag{
+				Name:  "workers",
+				Usage: "fetch `N` pages at once",
+				Value: 4,
+			},
+			&cli.DurationFlag{
+				Name:  "timeout",
+				Usage: "give up on a page after this long",
+				Value: 15 * time.Second,
+			},
+			&cli.DurationFlag{
+				Name:  "delay",
+				Usage: "minimum time between requests to the same host",
+				Value: time.Second,
+			},
+			&cli.DurationFlag{
+				Name:  "cache-ttl",
+				Usage: "refetch cached pages older than this (0 keeps them forever)",
+				Value: 7 * 24 * time.Hour,
+			},
+			&cli.BoolFlag{
+				Name:  "dry-run",
+				Usage: "report what would be filled without writing it to the file",
+			},
+			&cli.StringFlag{
+				Name:    "output",
+				Aliases: []string{"o"},
+				Usage:   "write the result to `PATH` instead of replacing the file",
+			},
+		},
+		Action: func(c *cli.Context) error {
+			manager, err := session.NewManager()
+			if err != nil {
+				return err
+			}
+			path, collection, matching, err := loadMatching(c, manager)
+			if err != nil {
+				return err
+			}
+
+			var targets []*bookmark.Bookmark
+			for _, b := range matching {
+				if linkcheck.Checkable(b.URL) && enrich.Missing(b) {
+					targets = append(targets, b)
+				}
+			}
+			if len(targets) == 0 {
+				fmt.Println("✅ No bookmarks are missing metadata")
+				return nil
+			}
+			fmt.Printf("🌐 Fetching %d page(s)…\n", len(targets))
+
+			ctx, stop := signal.NotifyContext(c.Context, os.Interrupt)
+			defer stop()
+
+			client := fetch.New(fetch.Options{
+				Timeout:   c.Duration("timeout"),
+				HostDelay: c.Duration("delay"),
+				Cache:     fetch.NewCache(filepath.Join(manager.ConfigDir(), "cache", "pages"), c.Duration("cache-ttl")),
+			})
+			results := enrich.New(client, c.Int("workers")).FetchAll(ctx, targets, func(done, total int) {
+				fmt.Fprintf(os.Stderr, "\r   %d/%d", done, total)
+			})
+			fmt.Fprintln(os.Stderr)
+			if ctx.Err() != nil {
+				fmt.Printf("⏹️  Stopped early after %d bookmark(s)\n", len(results))
+			}
+
+			fmt.Println()
+			enriched, fields := 0, 0
+			var failed []error
+			for _, r := range results {
+				if r.Err != nil {
+					failed = append(failed, r.Err)
+					continue
+				}
+				changes := enrich.Apply(r.Bookmark, r.Metadata)
+				if len(changes) == 0 {
+					continue
+				}
+				enriched++
+				fields += len(changes)
+				printEnriched(r.Bookmark, changes)
+			}
+
+			if len(failed) > 0 {
+				fmt.Printf("⚠️  %d page(s) could not be read:\n", len(failed))
+				for _, err := range failed {
+					fmt.Printf("   %v\n", err)
+				}
+				fmt.Println()
+			}
+			fmt.Printf("✨ Filled %d field(s) on %d bookmark(s)\n", fields, enriched)
+
+			if enriched == 0 {
+				return nil
+			}
+			return saveOutput(c, path, collection, "💾 Saved to %s\n")
+		},
+	}
+}
+
+// printEnriched prints the fields filled on one bookmark
+func printEnriched(b *bookmark.Bookmark, changes []enrich.Change) {
+	fmt.Printf("  %s\n", b.URL)
+	for _, change := range changes {
+		value := change.Value
+		if runes := []rune(value); len(runes) > 80 {
+			value = string(runes[:79]) + "…"
+		}
+		fmt.Printf("     + %s: %s\n", change.Field, value)
+	}
+	fmt.Println()
+}
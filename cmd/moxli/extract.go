@@ -0,0 +1,200 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"time"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/lelopez-io/moxli/internal/archive"
+	"github.com/lelopez-io/moxli/internal/bookmark"
+	"github.com/lelopez-io/moxli/internal/extract"
+	"github.com/lelopez-io/moxli/internal/fetch"
+	"github.com/lelopez-io/moxli/internal/linkcheck"
+	"github.com/lelopez-io/moxli/internal/session"
+)
+
+func extractCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "extract",
+		Usage: "Save the article text of the bookmarked pages",
+		Description: `Extracts the article from the page of every http(s) bookmark, or those
+matching QUERY, that has no article text yet, leaving navigation, sidebars
+and comments behind, and saves it on the bookmark as Markdown for full-text
+search. Archived bookmarks are read from their snapshot (see moxli archive);
+the others are fetched, using the page cache in ~/.moxli/cache/pages.`,
+		ArgsUsage: "[QUERY...]",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    "file",
+				Aliases: []string{"f"},
+				Usage:   "bookmark file to update (default: the session's current file)",
+			},
+			&cli.BoolFlag{
+				Name:  "overwrite",
+				Usage: "replace article text the bookmarks already have",
+			},
+			&cli.BoolFlag{
+				Name:  "offline",
+				Usage: "only read archived snapshots, never fetch pages",
+			},
+			&cli.IntFlag{
+				Name:  "workers",
+				Usage: "fetch `N` pages at once",
+				Value: 4,
+			},
+			&cli.DurationFlag{
+				Name:  "timeout",
+				Usage: "give up on a page after this long",
+				Value: 15 * time.Second,
+			},
+			&cli.DurationFlag{
+				Name:  "delay",
+				Usage: "minimum time between requests to the same host",
+				Value: time.Second,
+			},
+			&cli.DurationFlag{
+				Name:  "cache-ttl",
+				Usage: "refetch cached pages older than this (0 keeps them forever)",
+				Value: 7 * 24 * time.Hour,
+			},
+			&cli.BoolFlag{
+				Name:  "dry-run",
+				Usage: "report what would be extracted without writing it to the file",
+			},
+			&cli.StringFlag{
+				Name:    "output",
+				Aliases: []string{"o"},
+				Usage:   "write the result to `PATH` instead of replacing the file",
+			},
+		},
+		Action: func(c *cli.Context) error {
+			manager, err := session.NewManager()
+			if err != nil {
+				return err
+			}
+			path, collection, matching, err := loadMatching(c, manager)
+			if err != nil {
+				return err
+			}
+
+			offline := c.Bool("offline")
+			var targets []*bookmark.Bookmark
+			for _, b := range matching {
+				if !linkcheck.Checkable(b.URL) || (b.Article != "" && !c.Bool("overwrite")) {
+					continue
+				}
+				if offline && b.Snapshot == nil {
+					continue
+				}
+				targets = append(targets, b)
+			}
+			if len(targets) == 0 {
+				fmt.Println("✅ No bookmarks are missing article text")
+				return nil
+			}
+			fmt.Printf("🌐 Reading %d page(s)…\n", len(targets))
+
+			ctx, stop := signal.NotifyContext(c.Context, os.Interrupt)
+			defer stop()
+
+			// Snapshot paths are relative to the bookmark file
+			store := archive.NewStore(filepath.Dir(path))
+			client := fetch.New(fetch.Options{
+				Timeout:   c.Duration("timeout"),
+				HostDelay: c.Duration("delay"),
+				Cache:     fetch.NewCache(filepath.Join(manager.ConfigDir(), "cache", "pages"), c.Duration("cache-ttl")),
+			})
+
+			articles := make([]*extract.Article, len(targets))
+			sources := make([]string, len(targets))
+			errs := make([]error, len(targets))
+			finished := fetch.Parallel(ctx, c.Int("workers"), fetch.Sequence(len(targets)), func(i int) {
+				articles[i], sources[i], errs[i] = readArticle(ctx, store, client, targets[i], offline)
+			}, func(done, total int) {
+				fmt.Fprintf(os.Stderr, "\r   %d/%d", done, total)
+			})
+			fmt.Fprintln(os.Stderr)
+			if ctx.Err() != nil {
+				fmt.Println("⏹️  Stopped early")
+			}
+
+			fmt.Println()
+			extracted := 0
+			var failed []error
+			for i, b := range targets {
+				if !finished[i] {
+					continue
+				}
+				if errs[i] != nil {
+					failed = append(failed, errs[i])
+					continue
+				}
+				if articles[i].Markdown == "" {
+					failed = append(failed, fmt.Errorf("%s: no article text found", b.URL))
+					continue
+				}
+				b.Article = articles[i].Markdown
+				extracted++
+				printExtracted(b, articles[i], sources[i])
+			}
+
+			if len(failed) > 0 {
+				fmt.Printf("⚠️  %d page(s) could not be read:\n", len(failed))
+				for _, err := range failed {
+					fmt.Printf("   %v\n", err)
+				}
+				fmt.Println()
+			}
+			fmt.Printf("✨ Saved article text for %d bookmark(s)\n", extracted)
+
+			if extracted == 0 {
+				return nil
+			}
+			return saveOutput(c, path, collection, "💾 Saved to %s\n")
+		},
+	}
+}
+
+// readArticle extracts a bookmark's article from its snapshot or, when it
+// has none or it can't be read and offline is false, from the live page.
+// It also returns where the article came from.
+func readArticle(ctx context.Context, store *archive.Store, client *fetch.Client, b *bookmark.Bookmark, offline bool) (*extract.Article, string, error) {
+	if b.Snapshot != nil {
+		page, err := store.ReadPage(b.Snapshot)
+		if err == nil {
+			article, err := extract.Extract(bytes.NewReader(page), b.Snapshot.URL)
+			return article, "snapshot", err
+		}
+		if offline {
+			return nil, "", fmt.Errorf("%s: %w", b.URL, err)
+		}
+	}
+
+	// A page that failed to cache is still worth reading
+	page, err := client.Get(ctx, b.URL)
+	if page == nil {
+		return nil, "", err
+	}
+	if !page.IsHTML() {
+		return nil, "", fmt.Errorf("%s: not an HTML page (%s)", b.URL, page.ContentType)
+	}
+	article, err := extract.Extract(bytes.NewReader(page.Body), page.FinalURL)
+	return article, "page", err
+}
+
+// printExtracted prints the article saved on one bookmark
+func printExtracted(b *bookmark.Bookmark, article *extract.Article, source string) {
+	fmt.Printf("  %s\n", b.URL)
+	credit := ""
+	if article.Byline != "" {
+		credit = " by " + article.Byline
+	}
+	fmt.Printf("     + article: %d word(s)%s, from the %s\n", article.Words(), credit, source)
+	fmt.Println()
+}
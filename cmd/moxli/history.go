@@ -0,0 +1,188 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/lelopez-io/moxli/internal/bookmark"
+	"github.com/lelopez-io/moxli/internal/importer"
+	"github.com/lelopez-io/moxli/internal/session"
+)
+
+func historyCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "history",
+		Usage:     "Inspect, undo or replay the edit history of a collection",
+		ArgsUsage: "[FILE]",
+		Action: func(c *cli.Context) error {
+			manager, path, err := historyTarget(c)
+			if err != nil {
+				return err
+			}
+
+			history, err := manager.LoadHistory(path)
+			if err != nil {
+				return err
+			}
+			if len(history.Entries) == 0 {
+				fmt.Printf("📜 No edit history for %s\n", path)
+				return nil
+			}
+
+			fmt.Printf("📜 Edit history for %s\n\n", path)
+			for i, entry := range history.Entries {
+				marker := "  "
+				if i >= history.Cursor {
+					marker = "↷ " // Undone, can be redone
+				}
+				fmt.Printf("%s%3d  %s  %s (%d change(s))\n", marker, i+1,
+					entry.Time.Format("2006-01-02 15:04"), entry.Label, len(entry.Ops))
+			}
+			fmt.Printf("\n%d applied, %d undone\n", history.Cursor, len(history.Entries)-history.Cursor)
+			return nil
+		},
+		Subcommands: []*cli.Command{
+			{
+				Name:      "undo",
+				Usage:     "Undo the most recent edits and save the file",
+				ArgsUsage: "[FILE]",
+				Flags: []cli.Flag{
+					&cli.IntFlag{Name: "steps", Aliases: []string{"n"}, Value: 1, Usage: "number of actions to undo"},
+				},
+				Action: func(c *cli.Context) error {
+					return stepHistory(c, (*bookmark.History).Undo, "↩️  Undid")
+				},
+			},
+			{
+				Name:      "redo",
+				Usage:     "Redo the most recently undone edits and save the file",
+				ArgsUsage: "[FILE]",
+				Flags: []cli.Flag{
+					&cli.IntFlag{Name: "steps", Aliases: []string{"n"}, Value: 1, Usage: "number of actions to redo"},
+				},
+				Action: func(c *cli.Context) error {
+					return stepHistory(c, (*bookmark.History).Redo, "↪️  Redid")
+				},
+			},
+			{
+				Name:      "replay",
+				Usage:     "Apply the edits recorded for FILE to another bookmark file",
+				ArgsUsage: "[FILE]",
+				Flags: []cli.Flag{
+					&cli.StringFlag{Name: "onto", Required: true, Usage: "bookmark file to apply the edits to"},
+					&cli.StringFlag{
+						Name:    "output",
+						Aliases: []string{"o"},
+						Usage:   "write the result to `PATH` (required unless --onto is Anybox JSON)",
+					},
+				},
+				Action: func(c *cli.Context) error {
+					manager, path, err := historyTarget(c)
+					if err != nil {
+						return err
+					}
+					history, err := manager.LoadHistory(path)
+					if err != nil {
+						return err
+					}
+					if !history.CanUndo() {
+						return fmt.Errorf("no applied edits recorded for %s", path)
+					}
+
+					target := c.String("onto")
+					collection, err := importer.LoadFile(target)
+					if err != nil {
+						return fmt.Errorf("failed to load %s: %w", target, err)
+					}
+
+					output := c.String("output")
+					if output == "" {
+						if collection.Metadata.Source != "anybox" {
+							return fmt.Errorf("%s is not Anybox JSON; use --output to choose where to write", target)
+						}
+						output = target
+					}
+
+					applied, skipped := bookmark.Replay(collection, history.Applied())
+					fmt.Printf("🔁 Replayed %d change(s) from %d action(s)\n", applied, history.Cursor)
+					if skipped > 0 {
+						fmt.Printf("💡 Skipped %d change(s) for bookmarks missing from %s\n", skipped, target)
+					}
+
+					return writeCollection(output, collection)
+				},
+			},
+		},
+	}
+}
+
+// historyTarget returns the session manager and the collection file named by
+// the first argument, defaulting to the session's current file
+func historyTarget(c *cli.Context) (*session.Manager, string, error) {
+	manager, err := session.NewManager()
+	if err != nil {
+		return nil, "", err
+	}
+
+	path, err := sessionFile(manager, c.Args().First())
+	if err != nil {
+		return nil, "", err
+	}
+	return manager, path, nil
+}
+
+// sessionFile returns path, or the session's current file when path is empty
+func sessionFile(manager *session.Manager, path string) (string, error) {
+	if path != "" {
+		return path, nil
+	}
+
+	sess, err := manager.Load()
+	if err != nil {
+		return "", err
+	}
+	if sess == nil || sess.CurrentFile == "" {
+		return "", fmt.Errorf("no bookmark file given and no current file in session")
+	}
+	return sess.CurrentFile, nil
+}
+
+// stepHistory undoes or redoes up to --steps actions, then saves the file and
+// its history. action prefixes the line printed for each step.
+func stepHistory(c *cli.Context, step func(*bookmark.History, *bookmark.Collection) (*bookmark.HistoryEntry, error), action string) error {
+	manager, path, err := historyTarget(c)
+	if err != nil {
+		return err
+	}
+
+	collection, err := importer.LoadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to load %s: %w", path, err)
+	}
+	if collection.Metadata.Source != "anybox" {
+		return fmt.Errorf("%s is not Anybox JSON", path)
+	}
+	history, err := manager.LoadHistory(path)
+	if err != nil {
+		return err
+	}
+
+	done := 0
+	for done < c.Int("steps") {
+		entry, err := step(history, collection)
+		if err != nil {
+			if done == 0 {
+				return err
+			}
+			break
+		}
+		fmt.Printf("%s: %s\n", action, entry.Label)
+		done++
+	}
+
+	if err := writeCollection(path, collection); err != nil {
+		return err
+	}
+	return manager.SaveHistory(path, history)
+}
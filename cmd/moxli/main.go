@@ -26,6 +26,16 @@ func main() {
 		Commands: []*cli.Command{
 			versionCommand(),
 			sessionTestCommand(),
+			dedupeCommand(),
+			historyCommand(),
+			searchCommand(),
+			checkCommand(),
+			resolveCommand(),
+			enrichCommand(),
+			archiveCommand(),
+			extractCommand(),
+			serveCommand(),
+			siteCommand(),
 		},
 	}
 
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/lelopez-io/moxli/internal/bookmark"
+	"github.com/lelopez-io/moxli/internal/exporter"
+)
+
+// writeCollection saves a collection as Anybox JSON, keeping a backup
+func writeCollection(path string, collection *bookmark.Collection) error {
+	backup, err := exporter.WriteFile(path, &exporter.AnyboxExporter{Indent: true}, collection)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("💾 Saved to %s\n", path)
+	if backup != "" {
+		fmt.Printf("📦 Previous version kept at %s\n", backup)
+	}
+	return nil
+}
+
+// saveOutput writes a collection changed by a command unless --dry-run is
+// set: to --output, or back to path when it is Anybox JSON. message reports
+// the file written.
+func saveOutput(c *cli.Context, path string, collection *bookmark.Collection, message string) error {
+	if c.Bool("dry-run") {
+		return nil
+	}
+
+	output := c.String("output")
+	if output == "" {
+		if collection.Metadata.Source != "anybox" {
+			return fmt.Errorf("%s is not Anybox JSON; use --output to choose where to write", path)
+		}
+		output = path
+	}
+
+	backup, err := exporter.WriteFile(output, &exporter.AnyboxExporter{Indent: true}, collection)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf(message, output)
+	if backup != "" {
+		fmt.Printf("📦 Previous version kept at %s\n", backup)
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/lelopez-io/moxli/internal/bookmark"
+)
+
+func resolveCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "resolve",
+		Usage: "Follow bookmark redirects and move bookmarks to their new URLs",
+		Description: `Follows the redirects of every http(s) bookmark, or those matching QUERY,
+and lists each chain. Bookmarks whose first redirects are permanent (301 or
+308) can move to where those lead: with --rewrite the URL is replaced and the
+old one kept as an alias, so merges and lookups by it still find the bookmark.
+Link status is recorded as with moxli check.`,
+		ArgsUsage: "[QUERY...]",
+		Flags: append(linkCheckFlags("resolve"),
+			&cli.BoolFlag{
+				Name:  "rewrite",
+				Usage: "move bookmarks behind permanent redirects to their new URL",
+			},
+		),
+		Action: func(c *cli.Context) error {
+			path, collection, results, err := runLinkCheck(c)
+			if err != nil || len(results) == 0 {
+				return err
+			}
+
+			rewrite := c.Bool("rewrite")
+			redirected, movable, taken := 0, 0, 0
+			// Targets claimed by an earlier bookmark of this run, by the URL
+			// it moves from, so two bookmarks redirecting to the same page
+			// don't both move there
+			claimed := make(map[string]string)
+			fmt.Println()
+			for _, r := range results {
+				if !r.Status.Redirected() {
+					continue
+				}
+				redirected++
+
+				target := r.Status.PermanentURL()
+				other := bookmarkAt(collection, target)
+				key, _ := bookmark.NormalizeURL(target)
+				switch {
+				case target == "":
+					printCheckResult(r, "⏸️  Temporary redirect, URL kept")
+				case other != nil && other != r.Bookmark:
+					taken++
+					printCheckResult(r, fmt.Sprintf("👯 Already bookmarked as %q, URL kept", other.Title))
+				case claimed[key] != "":
+					taken++
+					printCheckResult(r, "👯 "+claimed[key]+" moves there too, URL kept")
+				case !rewrite:
+					movable++
+					claimed[key] = r.Bookmark.URL
+					printCheckResult(r, "✏️  Can move to "+target)
+				default:
+					movable++
+					claimed[key] = r.Bookmark.URL
+					printCheckResult(r, "✏️  Moved to "+target)
+					if err := r.Bookmark.MoveURL(target); err != nil {
+						return fmt.Errorf("failed to move %s: %w", r.Bookmark.URL, err)
+					}
+				}
+			}
+			collection.Reindex()
+
+			if redirected == 0 {
+				fmt.Println("✅ No redirects found")
+			} else {
+				verb := "can move"
+				if rewrite {
+					verb = "moved"
+				}
+				fmt.Printf("↪️  %d redirected  ✏️  %d %s  👯 %d already bookmarked\n", redirected, movable, verb, taken)
+				if !rewrite && movable > 0 {
+					fmt.Println("💡 Run again with --rewrite to move them")
+				}
+			}
+
+			message := "💾 Saved link status to %s\n"
+			if rewrite && movable > 0 {
+				message = "💾 Saved new URLs to %s\n"
+			}
+			return saveOutput(c, path, collection, message)
+		},
+	}
+}
+
+// bookmarkAt returns the bookmark whose current URL is rawURL, or nil when
+// none is
+func bookmarkAt(collection *bookmark.Collection, rawURL string) *bookmark.Bookmark {
+	normalized, err := bookmark.NormalizeURL(rawURL)
+	if err != nil {
+		return nil
+	}
+	if b, ok := collection.FindByURL(normalized); ok && b.NormalizedURL == normalized {
+		return b
+	}
+	return nil
+}
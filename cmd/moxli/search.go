@@ -0,0 +1,168 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/lelopez-io/moxli/internal/bookmark"
+	"github.com/lelopez-io/moxli/internal/fulltext"
+	"github.com/lelopez-io/moxli/internal/importer"
+	"github.com/lelopez-io/moxli/internal/query"
+	"github.com/lelopez-io/moxli/internal/session"
+)
+
+func searchCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "search",
+		Usage: "Search bookmarks with the query language",
+		Description: `Terms must all match. Supported filters: tag:, folder:, domain:, source:,
+title:, url:, is:starred, is:dead, is:redirected, has:FIELD,
+added:>YYYY-MM-DD, modified:<YYYY-MM.
+Negate with -term, combine with OR and group with parentheses.
+Put -- before a query that starts with "-".
+
+With --fulltext the words are instead ranked by relevance against titles,
+tags, descriptions, comments and saved article text, with a snippet of each
+result. The index is cached in ~/.moxli/index.`,
+		ArgsUsage: "QUERY...",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    "file",
+				Aliases: []string{"f"},
+				Usage:   "bookmark file to search (default: the session's current file)",
+			},
+			&cli.IntFlag{
+				Name:  "limit",
+				Usage: "show at most `N` results (0 for all)",
+			},
+			&cli.BoolFlag{
+				Name:  "urls",
+				Usage: "print only the URLs of matching bookmarks",
+			},
+			&cli.BoolFlag{
+				Name:  "fulltext",
+				Usage: "rank bookmarks by full-text relevance, including saved articles",
+			},
+		},
+		Action: func(c *cli.Context) error {
+			input := strings.Join(c.Args().Slice(), " ")
+			var q *query.Query
+			if !c.Bool("fulltext") {
+				parsed, err := query.Parse(input)
+				if err != nil {
+					return fmt.Errorf("invalid query: %w", err)
+				}
+				q = parsed
+			}
+
+			manager, err := session.NewManager()
+			if err != nil {
+				return err
+			}
+			path, err := sessionFile(manager, c.String("file"))
+			if err != nil {
+				return err
+			}
+
+			collection, err := importer.LoadFile(path)
+			if err != nil {
+				return fmt.Errorf("failed to load %s: %w", path, err)
+			}
+
+			if q == nil {
+				return fulltextSearch(c, manager, path, collection, input)
+			}
+
+			results := q.Filter(collection.Bookmarks)
+			total := len(results)
+			if limit := c.Int("limit"); limit > 0 && len(results) > limit {
+				results = results[:limit]
+			}
+
+			if c.Bool("urls") {
+				for _, b := range results {
+					fmt.Println(b.URL)
+				}
+				return nil
+			}
+
+			fmt.Printf("🔍 %d of %d bookmarks match %q\n\n", total, len(collection.Bookmarks), input)
+			for _, b := range results {
+				printSearchResult(b, "")
+			}
+			if len(results) < total {
+				fmt.Printf("… %d more (raise --limit to see them)\n", total-len(results))
+			}
+			return nil
+		},
+	}
+}
+
+// printSearchResult prints one bookmark of a search result list, followed by
+// a snippet of its text when one is given
+func printSearchResult(b *bookmark.Bookmark, snippet string) {
+	title := b.Title
+	if title == "" {
+		title = "(no title)"
+	}
+	if b.IsStarred {
+		title = "⭐ " + title
+	}
+	fmt.Printf("  %s\n", title)
+	fmt.Printf("     %s\n", b.URL)
+
+	details := []string{}
+	if len(b.Tags) > 0 {
+		details = append(details, "tags: "+bookmark.FormatTags(b.Tags))
+	}
+	if len(b.Folder) > 0 {
+		details = append(details, "folder: "+bookmark.FormatFolder(b.Folder))
+	}
+	if len(details) > 0 {
+		fmt.Printf("     %s\n", strings.Join(details, "  "))
+	}
+	if snippet != "" {
+		fmt.Printf("     %s\n", snippet)
+	}
+	fmt.Println()
+}
+
+// fulltextSearch prints the bookmarks ranked by full-text relevance to input
+func fulltextSearch(c *cli.Context, manager *session.Manager, path string, collection *bookmark.Collection, input string) error {
+	if len(fulltext.QueryTerms(input)) == 0 {
+		return fmt.Errorf("full-text query %q has no searchable words", input)
+	}
+
+	ix, err := fulltext.LoadOrBuild(filepath.Join(manager.ConfigDir(), "index"), path, collection.Bookmarks)
+	if err != nil {
+		fmt.Printf("⚠️  Could not cache the search index: %v\n", err)
+	}
+
+	hits := ix.Search(input)
+	total := len(hits)
+	if limit := c.Int("limit"); limit > 0 && len(hits) > limit {
+		hits = hits[:limit]
+	}
+
+	if c.Bool("urls") {
+		for _, hit := range hits {
+			fmt.Println(collection.Bookmarks[hit.Doc].URL)
+		}
+		return nil
+	}
+
+	terms := fulltext.QueryTerms(input)
+	fmt.Printf("🔍 %d of %d bookmarks match %q\n\n", total, len(collection.Bookmarks), input)
+	for _, hit := range hits {
+		b := collection.Bookmarks[hit.Doc]
+		snippet := fulltext.BookmarkSnippet(b, terms, 160)
+		printSearchResult(b, snippet.Mark("[", "]"))
+	}
+	if len(hits) < total {
+		fmt.Printf("… %d more (raise --limit to see them)\n", total-len(hits))
+	}
+	return nil
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/lelopez-io/moxli/internal/server"
+	"github.com/lelopez-io/moxli/internal/session"
+)
+
+func serveCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "serve",
+		Usage: "Serve a bookmark file over a local REST API",
+		Description: `Serves the bookmark file at http://ADDR/api for scripts and browser
+extensions:
+
+  GET    /api/bookmarks?q=&sort=&offset=&limit=   list or search bookmarks
+  POST   /api/bookmarks                           add a bookmark
+  GET    /api/bookmarks/{id}                      get a bookmark
+  PUT    /api/bookmarks/{id}                      replace a bookmark
+  PATCH  /api/bookmarks/{id}                      change some fields
+  DELETE /api/bookmarks/{id}                      delete a bookmark
+  POST   /api/bookmarks/{id}/tags                 add tags: {"tags": ["a/b"]}
+  DELETE /api/bookmarks/{id}/tags/{tag}           remove a tag
+  GET    /api/tags                                list tags with counts
+  POST   /api/tags/rename                         rename: {"from": "a", "to": "b"}
+  DELETE /api/tags/{tag}                          remove a tag from every bookmark
+  GET    /api/formats                             list export and import formats
+  GET    /api/export?format=&q=                   download an export
+  POST   /api/import                              add the bookmarks of a file
+
+q takes the same filters as the TUI. Changes are saved at once, keeping a
+.bak backup, and recorded in the edit history, so they can be undone with
+moxli history undo. Responses carry ETags; send one back in If-Match to
+refuse a change when the bookmark, or for tag and import requests the
+collection, changed in the meantime. The server only listens on loopback
+addresses, and browsers may only call it from the --allow-origin origins.
+
+Pages for the browser:
+
+  GET    /add?url=&title=&text=&tags=             quick-add form, saved on submit
+  GET    /bookmarklet                             bookmarklet opening the form
+
+Quick-adding a bookmarked URL updates it instead of adding a duplicate: the
+title is replaced, tags are added and the selected text is appended to the
+comment.`,
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    "file",
+				Aliases: []string{"f"},
+				Usage:   "Anybox JSON file to serve (default: the session's current file)",
+			},
+			&cli.StringFlag{
+				Name:  "addr",
+				Usage: "loopback `HOST:PORT` to listen on",
+				Value: "127.0.0.1:8899",
+			},
+			&cli.StringSliceFlag{
+				Name:  "allow-origin",
+				Usage: "browser `ORIGIN` allowed to call the API, such as moz-extension://ID (repeatable)",
+			},
+		},
+		Action: func(c *cli.Context) error {
+			addr := c.String("addr")
+			if !isLoopback(addr) {
+				return fmt.Errorf("refusing to listen on %s: the API has no authentication, use a loopback address", addr)
+			}
+
+			manager, err := session.NewManager()
+			if err != nil {
+				return err
+			}
+			path, err := sessionFile(manager, c.String("file"))
+			if err != nil {
+				return err
+			}
+
+			handler, err := server.New(server.Options{
+				Path:         path,
+				Manager:      manager,
+				AllowOrigins: c.StringSlice("allow-origin"),
+			})
+			if err != nil {
+				return err
+			}
+
+			listener, err := net.Listen("tcp", addr)
+			if err != nil {
+				return err
+			}
+			srv := &http.Server{Handler: handler, ReadHeaderTimeout: 10 * time.Second}
+
+			ctx, stop := signal.NotifyContext(c.Context, os.Interrupt)
+			defer stop()
+			go func() {
+				<-ctx.Done()
+				shutdown, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+				defer cancel()
+				_ = srv.Shutdown(shutdown)
+			}()
+
+			fmt.Printf("🌐 Serving %s at http://%s/api\n", path, listener.Addr())
+			fmt.Printf("🔖 Bookmarklet: http://%s/bookmarklet\n", listener.Addr())
+			if err := srv.Serve(listener); !errors.Is(err, http.ErrServerClosed) {
+				return err
+			}
+			fmt.Println("⏹️  Stopped")
+			return nil
+		},
+	}
+}
+
+// isLoopback reports whether a listen address only accepts connections
+// from this machine
+func isLoopback(addr string) bool {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return false
+	}
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/lelopez-io/moxli/internal/bookmark"
+	"github.com/lelopez-io/moxli/internal/session"
+	"github.com/lelopez-io/moxli/internal/site"
+)
+
+func siteCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "site",
+		Usage: "Render the collection into a static website",
+		Description: `Writes a static site of every bookmark, or those matching QUERY, into the
+--out directory: an index listing the bookmarks newest first with a search
+box, a page per tag and per folder, search.json for the search box and an
+Atom feed of the latest additions in feed.xml. Each bookmark has an anchor,
+#b-ID, so it can be linked to. Files from an earlier run are replaced; pages
+of tags and folders that no longer exist are left in place.
+
+The search box loads search.json, which browsers only allow once the site is
+served over HTTP rather than opened from disk.`,
+		ArgsUsage: "[QUERY...]",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    "file",
+				Aliases: []string{"f"},
+				Usage:   "bookmark file to publish (default: the session's current file)",
+			},
+			&cli.StringFlag{
+				Name:     "out",
+				Usage:    "write the site into `DIR`",
+				Required: true,
+			},
+			&cli.StringFlag{
+				Name:  "title",
+				Usage: "site title",
+				Value: site.DefaultTitle,
+			},
+			&cli.StringFlag{
+				Name:  "base-url",
+				Usage: "`URL` the site is published at, for links in the feed",
+			},
+			&cli.BoolFlag{
+				Name:  "exclude-private",
+				Usage: "leave out bookmarks tagged private, or a tag nested under it",
+			},
+			&cli.StringSliceFlag{
+				Name:  "exclude-tag",
+				Usage: "leave out bookmarks tagged `TAG`, or a tag nested under it (repeatable)",
+			},
+			&cli.IntFlag{
+				Name:  "feed-size",
+				Usage: "put the newest `N` bookmarks in the feed",
+				Value: site.DefaultFeedSize,
+			},
+		},
+		Action: func(c *cli.Context) error {
+			opts := site.Options{
+				Title:    c.String("title"),
+				BaseURL:  c.String("base-url"),
+				FeedSize: c.Int("feed-size"),
+			}
+			if c.Bool("exclude-private") {
+				opts.Exclude = append(opts.Exclude, site.PrivateTag)
+			}
+			for _, tag := range c.StringSlice("exclude-tag") {
+				opts.Exclude = append(opts.Exclude, bookmark.ParseTags(tag)...)
+			}
+
+			manager, err := session.NewManager()
+			if err != nil {
+				return err
+			}
+			path, _, matching, err := loadMatching(c, manager)
+			if err != nil {
+				return err
+			}
+
+			fmt.Printf("🌐 Rendering %d bookmark(s) from %s\n", len(matching), path)
+			result, err := site.Generate(c.String("out"), matching, opts)
+			if err != nil {
+				return err
+			}
+			if result.Excluded > 0 {
+				fmt.Printf("🙈 Left out %d bookmark(s) by tag\n", result.Excluded)
+			}
+			fmt.Printf("✨ Wrote %d page(s) for %d bookmark(s) to %s\n", result.Pages, result.Bookmarks, c.String("out"))
+			return nil
+		},
+	}
+}
@@ -0,0 +1,183 @@
+// Package archive saves offline snapshots of bookmarked pages. A snapshot
+// is either a self-contained HTML file, with stylesheets and images inlined
+// as data URIs, or a WARC file holding the page and its resources as
+// fetched. Snapshots are kept in a content-addressed Store next to the
+// collection file.
+package archive
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"time"
+
+	"golang.org/x/net/html"
+
+	"github.com/lelopez-io/moxli/internal/bookmark"
+	"github.com/lelopez-io/moxli/internal/extract"
+	"github.com/lelopez-io/moxli/internal/fetch"
+)
+
+// Format is the file format of a snapshot
+type Format string
+
+const (
+	FormatHTML Format = "html" // Single HTML file with inlined resources
+	FormatWARC Format = "warc" // Web ARChive of the page and its resources
+)
+
+// DefaultMaxResources is how many resources are fetched per page by default
+const DefaultMaxResources = 100
+
+// Options configures an Archiver
+type Options struct {
+	Format       Format // Snapshot format ("" uses FormatHTML)
+	Workers      int    // Pages archived at once
+	MaxResources int    // Stylesheets and images fetched per page (0 uses DefaultMaxResources)
+}
+
+// Result is the snapshot saved for one bookmark, or why none was
+type Result struct {
+	Bookmark *bookmark.Bookmark
+	Snapshot *bookmark.Snapshot
+	Text     string // Article text of the page, as Markdown
+	Err      error
+}
+
+// Archiver fetches pages and saves them to a Store
+type Archiver struct {
+	client *fetch.Client
+	store  *Store
+	opts   Options
+}
+
+// New creates an Archiver
+func New(client *fetch.Client, store *Store, opts Options) *Archiver {
+	if opts.Format == "" {
+		opts.Format = FormatHTML
+	}
+	opts.Workers = max(opts.Workers, 1)
+	if opts.MaxResources <= 0 {
+		opts.MaxResources = DefaultMaxResources
+	}
+	return &Archiver{client: client, store: store, opts: opts}
+}
+
+// ArchiveAll archives the bookmarks' pages on a bounded pool of workers,
+// calling progress (when not nil) from a single goroutine after each URL.
+// Bookmarks sharing a URL are archived once. Results follow the order of
+// bookmarks; when ctx is cancelled only the finished ones are returned. The
+// bookmarks are not modified.
+func (a *Archiver) ArchiveAll(ctx context.Context, bookmarks []*bookmark.Bookmark, progress func(done, total int)) []Result {
+	var urls []string
+	index := make(map[string]int)
+	for _, b := range bookmarks {
+		if _, seen := index[b.URL]; !seen {
+			index[b.URL] = len(urls)
+			urls = append(urls, b.URL)
+		}
+	}
+
+	found := make([]Result, len(urls))
+	finished := fetch.Parallel(ctx, a.opts.Workers, fetch.Sequence(len(urls)), func(i int) {
+		found[i].Snapshot, found[i].Text, found[i].Err = a.Archive(ctx, urls[i])
+	}, progress)
+
+	results := make([]Result, 0, len(bookmarks))
+	for _, b := range bookmarks {
+		if i := index[b.URL]; finished[i] {
+			r := found[i]
+			r.Bookmark = b
+			if r.Snapshot != nil {
+				snapshot := *r.Snapshot
+				r.Snapshot = &snapshot
+			}
+			results = append(results, r)
+		}
+	}
+	return results
+}
+
+// Archive saves a snapshot of the page at rawURL and returns it with the
+// page's article as Markdown
+func (a *Archiver) Archive(ctx context.Context, rawURL string) (*bookmark.Snapshot, string, error) {
+	// A page that failed to cache is still worth archiving
+	page, err := a.client.Get(ctx, rawURL)
+	if page == nil {
+		return nil, "", err
+	}
+	if !page.IsHTML() {
+		return nil, "", fmt.Errorf("%s: not an HTML page (%s)", rawURL, page.ContentType)
+	}
+	if page.Truncated {
+		return nil, "", fmt.Errorf("%s: page is larger than the size limit", rawURL)
+	}
+
+	doc, err := html.Parse(bytes.NewReader(page.Body))
+	if err != nil {
+		return nil, "", fmt.Errorf("%s: %w", rawURL, err)
+	}
+	base, err := url.Parse(page.FinalURL)
+	if err != nil {
+		return nil, "", err
+	}
+
+	// The article is taken from the page as served, before it is rewritten
+	article, err := extract.Extract(bytes.NewReader(page.Body), page.FinalURL)
+	if err != nil {
+		return nil, "", fmt.Errorf("%s: %w", rawURL, err)
+	}
+
+	// The WARC keeps the original page, but the rewrite finds its resources
+	in := newInliner(ctx, a.client, base, a.opts.MaxResources)
+	in.rewrite(doc)
+	if err := ctx.Err(); err != nil {
+		// Resources may be missing, so don't keep a partial snapshot
+		return nil, "", err
+	}
+
+	now := time.Now()
+	var data bytes.Buffer
+	switch a.opts.Format {
+	case FormatWARC:
+		err = writeWARC(&data, page, in.resources, now)
+	default:
+		err = renderSnapshot(&data, doc, page.FinalURL)
+	}
+	if err != nil {
+		return nil, "", fmt.Errorf("%s: %w", rawURL, err)
+	}
+
+	rel, hash, err := a.store.Put(data.Bytes(), string(a.opts.Format))
+	if err != nil {
+		return nil, "", err
+	}
+
+	snapshot := &bookmark.Snapshot{
+		Path:       rel,
+		Hash:       hash,
+		Format:     string(a.opts.Format),
+		URL:        page.FinalURL,
+		Size:       int64(data.Len()),
+		ArchivedAt: now,
+	}
+	return snapshot, article.Markdown, nil
+}
+
+// renderSnapshot writes the rewritten document, noting where it came from
+// in a comment ahead of the <html> element. The note leaves out the archive
+// time so an unchanged page renders the same bytes and the store dedupes it.
+func renderSnapshot(w io.Writer, doc *html.Node, pageURL string) error {
+	note := &html.Node{
+		Type: html.CommentNode,
+		Data: fmt.Sprintf(" Archived by moxli from %s ", pageURL),
+	}
+	if root := findElement(doc, "html"); root != nil && root.Parent == doc {
+		doc.InsertBefore(note, root)
+	} else {
+		doc.InsertBefore(note, doc.FirstChild)
+	}
+	return html.Render(w, doc)
+}
@@ -0,0 +1,156 @@
+package archive
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/lelopez-io/moxli/internal/bookmark"
+	"github.com/lelopez-io/moxli/internal/fetch"
+)
+
+func TestArchiver_ArchiveAll(t *testing.T) {
+	var hits atomic.Int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("/article", func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(`<!DOCTYPE html><html><head><link rel="stylesheet" href="/site.css"></head>
+<body><nav>Menu</nav><article><h1>Story</h1><p>Once upon a time.</p></article><img src="/a.png"></body></html>`))
+	})
+	mux.HandleFunc("/site.css", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/css")
+		w.Write([]byte(`h1 { color: red }`))
+	})
+	mux.HandleFunc("/a.png", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "image/png")
+		w.Write(pixel)
+	})
+	mux.HandleFunc("/missing", http.NotFound)
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	bookmarks := []*bookmark.Bookmark{
+		{ID: "article", URL: server.URL + "/article"},
+		{ID: "image", URL: server.URL + "/a.png"},
+		{ID: "missing", URL: server.URL + "/missing"},
+		{ID: "copy", URL: server.URL + "/article"},
+	}
+
+	store := NewStore(t.TempDir())
+	client := fetch.New(fetch.Options{Timeout: time.Second})
+	calls := 0
+	results := New(client, store, Options{Workers: 2}).ArchiveAll(context.Background(), bookmarks, func(done, total int) {
+		calls++
+	})
+
+	if len(results) != 4 || calls != 3 {
+		t.Fatalf("len(results) = %v with %v progress calls, want 4 and 3", len(results), calls)
+	}
+	for i, r := range results {
+		if r.Bookmark != bookmarks[i] {
+			t.Errorf("results[%d] is bookmark %s, want %s", i, r.Bookmark.ID, bookmarks[i].ID)
+		}
+	}
+	if results[1].Err == nil || results[2].Err == nil {
+		t.Error("non-HTML and missing pages should be errors")
+	}
+	if hits.Load() != 1 {
+		t.Errorf("/article fetched %v times, want once", hits.Load())
+	}
+
+	r := results[0]
+	if r.Err != nil || r.Snapshot == nil {
+		t.Fatalf("article snapshot = %v, error %v", r.Snapshot, r.Err)
+	}
+	if r.Text != "Once upon a time.\n\n![]("+server.URL+"/a.png)" {
+		t.Errorf("Text = %q, want the article text without its title", r.Text)
+	}
+	if r.Snapshot.Format != "html" || r.Snapshot.URL != server.URL+"/article" || r.Snapshot.ArchivedAt.IsZero() {
+		t.Errorf("Snapshot = %+v", r.Snapshot)
+	}
+	if results[3].Snapshot == r.Snapshot || *results[3].Snapshot != *r.Snapshot {
+		t.Error("bookmarks sharing a URL should get equal copies of the snapshot")
+	}
+
+	data, err := store.Read(r.Snapshot)
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	if int64(len(data)) != r.Snapshot.Size {
+		t.Errorf("Size = %v, want %v", r.Snapshot.Size, len(data))
+	}
+	page := string(data)
+	for _, want := range []string{"<!DOCTYPE html><!-- Archived by moxli from " + server.URL + "/article", "<style>h1 { color: red }</style>", `<img src="data:image/png;base64,`} {
+		if !strings.Contains(page, want) {
+			t.Errorf("snapshot lacks %s\n%s", want, page)
+		}
+	}
+	if bookmarks[0].Snapshot != nil || bookmarks[0].Article != "" {
+		t.Error("ArchiveAll should not modify the bookmarks")
+	}
+
+	again, _, err := New(client, store, Options{}).Archive(context.Background(), server.URL+"/article")
+	if err != nil {
+		t.Fatalf("Archive() error = %v", err)
+	}
+	if again.Path != r.Snapshot.Path || again.Hash != r.Snapshot.Hash {
+		t.Errorf("archiving an unchanged page again stored %s, want %s", again.Path, r.Snapshot.Path)
+	}
+}
+
+func TestArchiver_Archive_WARC(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(`<body><img src="/a.png"><p>Text</p></body>`))
+	})
+	mux.HandleFunc("/a.png", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "image/png")
+		w.Write(pixel)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	store := NewStore(t.TempDir())
+	a := New(fetch.New(fetch.Options{Timeout: time.Second}), store, Options{Format: FormatWARC})
+	snapshot, text, err := a.Archive(context.Background(), server.URL+"/")
+	if err != nil {
+		t.Fatalf("Archive() error = %v", err)
+	}
+	if snapshot.Format != "warc" || !strings.HasSuffix(snapshot.Path, ".warc") || !strings.HasSuffix(text, "Text") {
+		t.Errorf("Archive() = %+v, %q", snapshot, text)
+	}
+
+	data, err := store.Read(snapshot)
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	warc := string(data)
+	if !strings.Contains(warc, "WARC-Target-URI: "+server.URL+"/a.png") || !strings.Contains(warc, `<img src="/a.png">`) {
+		t.Errorf("WARC should hold the original page and its image\n%s", warc)
+	}
+
+	page, err := store.ReadPage(snapshot)
+	if err != nil || string(page) != `<body><img src="/a.png"><p>Text</p></body>` {
+		t.Errorf("ReadPage() = %q (error %v), want the page as served", page, err)
+	}
+}
+
+func TestArchiver_Archive_Truncated(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(`<p>` + strings.Repeat("long ", 100) + `</p>`))
+	}))
+	defer server.Close()
+
+	store := NewStore(t.TempDir())
+	a := New(fetch.New(fetch.Options{Timeout: time.Second, MaxBytes: 64}), store, Options{})
+	if _, _, err := a.Archive(context.Background(), server.URL); err == nil {
+		t.Error("Archive() of a page cut at MaxBytes should fail")
+	}
+}
@@ -0,0 +1,286 @@
+package archive
+
+import (
+	"context"
+	"encoding/base64"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+
+	"golang.org/x/net/html"
+
+	"github.com/lelopez-io/moxli/internal/fetch"
+)
+
+var (
+	// cssImport matches @import rules, with or without url()
+	cssImport = regexp.MustCompile(`@import\s+(?:url\(\s*)?(?:"([^"]*)"|'([^']*)'|([^"'\s);]+))\s*\)?`)
+	// cssURL matches url() references in stylesheets and style attributes
+	cssURL = regexp.MustCompile(`url\(\s*(?:"([^"]*)"|'([^']*)'|([^"'\s)]*))\s*\)`)
+)
+
+// maxImportDepth limits how deeply nested @import rules are inlined
+const maxImportDepth = 3
+
+// inliner rewrites a page into a self-contained document: stylesheets,
+// images and icons become inline styles and data URIs, scripts are dropped
+// and the remaining links are made absolute
+type inliner struct {
+	ctx          context.Context
+	client       *fetch.Client
+	base         *url.URL
+	maxResources int
+
+	fetched   map[string]*fetch.Page // Resource URL → page, nil when it couldn't be fetched
+	resources []*fetch.Page          // Fetched resources in order, for WARC records
+}
+
+func newInliner(ctx context.Context, client *fetch.Client, base *url.URL, maxResources int) *inliner {
+	return &inliner{ctx: ctx, client: client, base: base, maxResources: maxResources, fetched: make(map[string]*fetch.Page)}
+}
+
+// rewrite inlines the document's resources in place
+func (in *inliner) rewrite(doc *html.Node) {
+	// Links resolve against <base>, which then goes as they all become absolute
+	if b := findElement(doc, "base"); b != nil {
+		if u, err := in.base.Parse(getAttr(b, "href")); err == nil {
+			in.base = u
+		}
+		b.Parent.RemoveChild(b)
+	}
+	in.walk(doc)
+}
+
+func (in *inliner) walk(n *html.Node) {
+	for c := n.FirstChild; c != nil; {
+		next := c.NextSibling
+		if in.element(c) {
+			in.walk(c)
+		}
+		c = next
+	}
+}
+
+// element rewrites one node and reports whether its children should be
+// visited
+func (in *inliner) element(n *html.Node) bool {
+	if n.Type != html.ElementNode {
+		return true
+	}
+
+	if style := getAttr(n, "style"); style != "" {
+		setAttr(n, "style", in.inlineCSS(style, in.base, 0))
+	}
+
+	switch n.Data {
+	case "script":
+		n.Parent.RemoveChild(n)
+		return false
+	case "link":
+		return in.link(n)
+	case "style":
+		if text := n.FirstChild; text != nil && text.Type == html.TextNode {
+			text.Data = in.inlineCSS(text.Data, in.base, 0)
+		}
+		return false
+	case "img":
+		src := getAttr(n, "src")
+		if src == "" {
+			// Lazy-loading scripts are gone, so load the real image
+			src = getAttr(n, "data-src")
+		}
+		setAttr(n, "src", in.dataURI(src, in.base))
+		for _, key := range []string{"srcset", "sizes", "data-src", "data-srcset", "loading"} {
+			removeAttr(n, key)
+		}
+	case "source":
+		if n.Parent != nil && n.Parent.Data == "picture" {
+			// The <img> fallback of the picture is inlined instead
+			n.Parent.RemoveChild(n)
+			return false
+		}
+		in.absolutize(n, "src")
+	case "a", "area":
+		in.absolutize(n, "href")
+	case "form":
+		in.absolutize(n, "action")
+	case "iframe", "video", "audio", "embed", "track":
+		in.absolutize(n, "src")
+	}
+	return true
+}
+
+// link inlines stylesheets and icons and drops resource hints
+func (in *inliner) link(n *html.Node) bool {
+	rels := strings.Fields(strings.ToLower(getAttr(n, "rel")))
+	has := func(rel string) bool { return slices.Contains(rels, rel) }
+
+	switch {
+	case has("stylesheet"):
+		css, ok := in.stylesheet(getAttr(n, "href"), in.base, 0)
+		if !ok {
+			in.absolutize(n, "href")
+			return false
+		}
+		style := &html.Node{Type: html.ElementNode, Data: "style"}
+		style.AppendChild(&html.Node{Type: html.TextNode, Data: css})
+		if media := getAttr(n, "media"); media != "" {
+			setAttr(style, "media", media)
+		}
+		n.Parent.InsertBefore(style, n)
+		n.Parent.RemoveChild(n)
+	case has("icon") || has("apple-touch-icon"):
+		setAttr(n, "href", in.dataURI(getAttr(n, "href"), in.base))
+	case has("preload") || has("modulepreload") || has("prefetch") || has("preconnect") || has("dns-prefetch"):
+		n.Parent.RemoveChild(n)
+	default:
+		in.absolutize(n, "href")
+	}
+	return false
+}
+
+// stylesheet fetches a stylesheet and inlines its own resources
+func (in *inliner) stylesheet(href string, base *url.URL, depth int) (string, bool) {
+	page, ok := in.fetch(href, base)
+	if !ok {
+		return "", false
+	}
+	sheetURL, err := url.Parse(page.FinalURL)
+	if err != nil {
+		return "", false
+	}
+	return in.inlineCSS(string(page.Body), sheetURL, depth+1), true
+}
+
+// inlineCSS replaces the imports and url() references of css, resolved
+// against base, with data URIs
+func (in *inliner) inlineCSS(css string, base *url.URL, depth int) string {
+	css = cssImport.ReplaceAllStringFunc(css, func(rule string) string {
+		href := firstGroup(cssImport.FindStringSubmatch(rule))
+		if depth >= maxImportDepth || strings.HasPrefix(href, "data:") {
+			return rule
+		}
+		imported, ok := in.stylesheet(href, base, depth)
+		if !ok {
+			return rule
+		}
+		return `@import url("data:text/css;base64,` + base64.StdEncoding.EncodeToString([]byte(imported)) + `")`
+	})
+
+	return cssURL.ReplaceAllStringFunc(css, func(ref string) string {
+		href := firstGroup(cssURL.FindStringSubmatch(ref))
+		if href == "" || strings.HasPrefix(href, "data:") || strings.HasPrefix(href, "#") {
+			return ref
+		}
+		return `url("` + in.dataURI(href, base) + `")`
+	})
+}
+
+// dataURI returns a resource as a data URI, or its absolute URL when it
+// can't be fetched
+func (in *inliner) dataURI(href string, base *url.URL) string {
+	href = strings.TrimSpace(href)
+	if href == "" || strings.HasPrefix(href, "data:") {
+		return href
+	}
+	page, ok := in.fetch(href, base)
+	if !ok {
+		return resolve(base, href)
+	}
+	return "data:" + page.ContentType + ";base64," + base64.StdEncoding.EncodeToString(page.Body)
+}
+
+// fetch downloads a resource once, up to maxResources per page. Failed and
+// truncated resources are skipped.
+func (in *inliner) fetch(href string, base *url.URL) (*fetch.Page, bool) {
+	target := resolve(base, href)
+	if !strings.HasPrefix(target, "http://") && !strings.HasPrefix(target, "https://") {
+		return nil, false
+	}
+	if page, seen := in.fetched[target]; seen {
+		return page, page != nil
+	}
+	if len(in.fetched) >= in.maxResources {
+		return nil, false
+	}
+
+	// A resource that failed to cache is still usable
+	page, _ := in.client.Get(in.ctx, target)
+	if page != nil && page.Truncated {
+		page = nil
+	}
+	in.fetched[target] = page
+	if page != nil {
+		in.resources = append(in.resources, page)
+	}
+	return page, page != nil
+}
+
+// absolutize resolves a link attribute against the page
+func (in *inliner) absolutize(n *html.Node, key string) {
+	if value := getAttr(n, key); value != "" && !strings.HasPrefix(value, "#") {
+		setAttr(n, key, resolve(in.base, value))
+	}
+}
+
+// resolve makes href absolute, returning it unchanged when it can't be
+// parsed
+func resolve(base *url.URL, href string) string {
+	u, err := base.Parse(strings.TrimSpace(href))
+	if err != nil {
+		return href
+	}
+	return u.String()
+}
+
+// firstGroup returns the first non-empty submatch
+func firstGroup(match []string) string {
+	for _, group := range match[1:] {
+		if group != "" {
+			return group
+		}
+	}
+	return ""
+}
+
+// findElement returns the first element named tag in depth-first order
+func findElement(n *html.Node, tag string) *html.Node {
+	if n.Type == html.ElementNode && n.Data == tag {
+		return n
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if found := findElement(c, tag); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+func getAttr(n *html.Node, key string) string {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return a.Val
+		}
+	}
+	return ""
+}
+
+func setAttr(n *html.Node, key, value string) {
+	for i, a := range n.Attr {
+		if a.Key == key {
+			n.Attr[i].Val = value
+			return
+		}
+	}
+	n.Attr = append(n.Attr, html.Attribute{Key: key, Val: value})
+}
+
+func removeAttr(n *html.Node, key string) {
+	for i, a := range n.Attr {
+		if a.Key == key {
+			n.Attr = append(n.Attr[:i], n.Attr[i+1:]...)
+			return
+		}
+	}
+}
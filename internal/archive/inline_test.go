@@ -0,0 +1,132 @@
+package archive
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/net/html"
+
+	"github.com/lelopez-io/moxli/internal/fetch"
+)
+
+var pixel = []byte{0x89, 'P', 'N', 'G', '\r', '\n', 0x1a, '\n'}
+
+// resourceServer serves a stylesheet importing another, which uses an image
+func resourceServer(t *testing.T) *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/css/site.css", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/css")
+		w.Write([]byte(`@import "theme.css"; body { color: black }`))
+	})
+	mux.HandleFunc("/css/theme.css", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/css")
+		w.Write([]byte(`body { background: url(../img/bg.png) }`))
+	})
+	mux.HandleFunc("/img/", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "image/png")
+		w.Write(pixel)
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestInliner_Rewrite(t *testing.T) {
+	server := resourceServer(t)
+	page := `<html><head>
+<base href="/docs/">
+<link rel="stylesheet" href="/css/site.css" media="screen">
+<link rel="icon" href="/img/icon.png">
+<link rel="preload" href="/font.woff2">
+<script src="/app.js"></script>
+</head><body style="background: url('/img/body.png')">
+<img src="/img/photo.png" srcset="/img/photo-2x.png 2x" loading="lazy">
+<img data-src="/img/lazy.png">
+<picture><source srcset="/img/wide.png"><img src="/img/narrow.png"></picture>
+<img src="/missing.png">
+<a href="page.html">Page</a> <a href="#top">Top</a>
+</body></html>`
+
+	doc, err := html.Parse(strings.NewReader(page))
+	if err != nil {
+		t.Fatal(err)
+	}
+	base, _ := url.Parse(server.URL + "/")
+	client := fetch.New(fetch.Options{Timeout: time.Second})
+	in := newInliner(context.Background(), client, base, DefaultMaxResources)
+	in.rewrite(doc)
+
+	var out bytes.Buffer
+	html.Render(&out, doc)
+	got := out.String()
+
+	pngURI := "data:image/png;base64," + base64.StdEncoding.EncodeToString(pixel)
+	contains := []string{
+		`<style media="screen">@import url("data:text/css;base64,`,
+		`body { color: black }`,
+		`<link rel="icon" href="` + pngURI + `"/>`,
+		`<body style="background: url(&#34;` + pngURI + `&#34;)">`,
+		`<img src="` + pngURI + `"/>`,
+		`<picture><img src="` + pngURI + `"/></picture>`,
+		`<img src="` + server.URL + `/missing.png"/>`,
+		`<a href="` + server.URL + `/docs/page.html">`,
+		`<a href="#top">`,
+	}
+	for _, want := range contains {
+		if !strings.Contains(got, want) {
+			t.Errorf("rewritten page lacks %s\n%s", want, got)
+		}
+	}
+	for _, gone := range []string{"<base", "<script", "preload", "srcset", "loading", "data-src", "<source"} {
+		if strings.Contains(got, gone) {
+			t.Errorf("rewritten page still has %s\n%s", gone, got)
+		}
+	}
+
+	// The imported stylesheet's image is inlined too
+	theme := "body { background: url(\"" + pngURI + "\") }"
+	if !strings.Contains(got, base64.StdEncoding.EncodeToString([]byte(theme))) {
+		t.Errorf("imported stylesheet should be inlined with its image\n%s", got)
+	}
+
+	// Each resource is fetched once, failures included
+	urls := map[string]int{}
+	for _, r := range in.resources {
+		urls[r.URL]++
+	}
+	for u, n := range urls {
+		if n > 1 {
+			t.Errorf("%s recorded %v times, want once", u, n)
+		}
+	}
+	if _, ok := in.fetched[server.URL+"/missing.png"]; !ok {
+		t.Error("a failed resource should be remembered")
+	}
+}
+
+func TestInliner_MaxResources(t *testing.T) {
+	server := resourceServer(t)
+	doc, err := html.Parse(strings.NewReader(`<img src="/img/a.png"><img src="/img/b.png">`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	base, _ := url.Parse(server.URL + "/")
+	in := newInliner(context.Background(), fetch.New(fetch.Options{Timeout: time.Second}), base, 1)
+	in.rewrite(doc)
+
+	var out bytes.Buffer
+	html.Render(&out, doc)
+	if !strings.Contains(out.String(), `<img src="data:image/png`) || !strings.Contains(out.String(), `<img src="`+server.URL+`/img/b.png"/>`) {
+		t.Errorf("only the first image should be inlined\n%s", out.String())
+	}
+	if len(in.resources) != 1 {
+		t.Errorf("len(resources) = %v, want 1", len(in.resources))
+	}
+}
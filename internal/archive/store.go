@@ -0,0 +1,91 @@
+package archive
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/lelopez-io/moxli/internal/bookmark"
+)
+
+// StoreDir is the directory of the snapshot store, next to the collection
+// file
+const StoreDir = "snapshots"
+
+// Store is a content-addressed directory of snapshot files. Files are named
+// by the SHA-256 of their contents, so identical snapshots are kept once.
+type Store struct {
+	root string // Directory of the collection file
+}
+
+// NewStore creates the store of a collection file in directory root
+func NewStore(root string) *Store {
+	return &Store{root: root}
+}
+
+// Put saves data unless the store already has it and returns its path,
+// relative to the store's root and slash-separated, and its hash
+func (s *Store) Put(data []byte, ext string) (string, string, error) {
+	sum := sha256.Sum256(data)
+	digest := hex.EncodeToString(sum[:])
+	rel := path.Join(StoreDir, digest[:2], digest+"."+ext)
+	hash := "sha256:" + digest
+
+	target := s.Path(rel)
+	if _, err := os.Stat(target); err == nil {
+		return rel, hash, nil
+	}
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return "", "", fmt.Errorf("failed to create snapshot directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(target), ".snapshot-*")
+	if err != nil {
+		return "", "", fmt.Errorf("failed to write snapshot: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return "", "", fmt.Errorf("failed to write snapshot: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return "", "", fmt.Errorf("failed to write snapshot: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), target); err != nil {
+		return "", "", fmt.Errorf("failed to write snapshot: %w", err)
+	}
+	return rel, hash, nil
+}
+
+// Path returns the file of a snapshot path recorded by Put
+func (s *Store) Path(rel string) string {
+	return filepath.Join(s.root, filepath.FromSlash(rel))
+}
+
+// Read returns the contents of a snapshot, checking them against its hash
+func (s *Store) Read(snapshot *bookmark.Snapshot) ([]byte, error) {
+	data, err := os.ReadFile(s.Path(snapshot.Path))
+	if err != nil {
+		return nil, err
+	}
+
+	sum := sha256.Sum256(data)
+	if want := strings.TrimPrefix(snapshot.Hash, "sha256:"); hex.EncodeToString(sum[:]) != want {
+		return nil, fmt.Errorf("snapshot %s does not match its hash", snapshot.Path)
+	}
+	return data, nil
+}
+
+// ReadPage returns the HTML of the page a snapshot archived
+func (s *Store) ReadPage(snapshot *bookmark.Snapshot) ([]byte, error) {
+	data, err := s.Read(snapshot)
+	if err != nil || snapshot.Format != string(FormatWARC) {
+		return data, err
+	}
+	return readWARCPage(data)
+}
@@ -0,0 +1,56 @@
+package archive
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/lelopez-io/moxli/internal/bookmark"
+)
+
+func TestStore_Put(t *testing.T) {
+	store := NewStore(t.TempDir())
+
+	rel, hash, err := store.Put([]byte("<p>page</p>"), "html")
+	if err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+	if !strings.HasPrefix(rel, StoreDir+"/") || !strings.HasSuffix(rel, ".html") {
+		t.Errorf("Put() path = %v, want a .html file under %s/", rel, StoreDir)
+	}
+	if !strings.HasPrefix(hash, "sha256:") || !strings.Contains(rel, strings.TrimPrefix(hash, "sha256:")) {
+		t.Errorf("Put() hash = %v, want the sha256 the file is named by (%v)", hash, rel)
+	}
+
+	again, _, err := store.Put([]byte("<p>page</p>"), "html")
+	if err != nil || again != rel {
+		t.Errorf("Put() of the same data = %v (error %v), want %v", again, err, rel)
+	}
+	other, _, _ := store.Put([]byte("<p>other</p>"), "html")
+	if other == rel {
+		t.Error("different data should be stored under a different path")
+	}
+
+	data, err := store.Read(&bookmark.Snapshot{Path: rel, Hash: hash})
+	if err != nil || string(data) != "<p>page</p>" {
+		t.Errorf("Read() = %q (error %v), want the stored data", data, err)
+	}
+}
+
+func TestStore_Read_Tampered(t *testing.T) {
+	store := NewStore(t.TempDir())
+	rel, hash, err := store.Put([]byte("original"), "html")
+	if err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+	if err := os.WriteFile(store.Path(rel), []byte("changed"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := store.Read(&bookmark.Snapshot{Path: rel, Hash: hash}); err == nil {
+		t.Error("Read() of a changed file should fail the hash check")
+	}
+	if _, err := store.Read(&bookmark.Snapshot{Path: "snapshots/00/missing.html"}); err == nil {
+		t.Error("Read() of a missing file should fail")
+	}
+}
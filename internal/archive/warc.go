@@ -0,0 +1,109 @@
+package archive
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha1"
+	"encoding/base32"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/textproto"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/lelopez-io/moxli/internal/fetch"
+)
+
+// writeWARC writes a WARC 1.1 file holding a warcinfo record, then a
+// response record for the page and one for each resource. Response headers
+// are not kept by the fetcher, so each record carries a minimal status line
+// and Content-Type.
+func writeWARC(w io.Writer, page *fetch.Page, resources []*fetch.Page, now time.Time) error {
+	info := []byte("software: moxli\r\nformat: WARC File Format 1.1\r\n")
+	if err := writeRecord(w, "warcinfo", "", "application/warc-fields", info, now); err != nil {
+		return err
+	}
+
+	for _, p := range append([]*fetch.Page{page}, resources...) {
+		var block bytes.Buffer
+		fmt.Fprintf(&block, "HTTP/1.1 %d %s\r\n", p.StatusCode, http.StatusText(p.StatusCode))
+		fmt.Fprintf(&block, "Content-Type: %s\r\n", p.ContentType)
+		fmt.Fprintf(&block, "Content-Length: %d\r\n\r\n", len(p.Body))
+		block.Write(p.Body)
+
+		if err := writeRecord(w, "response", p.FinalURL, "application/http;msgtype=response", block.Bytes(), p.FetchedAt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeRecord writes one WARC record with its block digest
+func writeRecord(w io.Writer, kind, target, contentType string, block []byte, date time.Time) error {
+	digest := sha1.Sum(block)
+
+	var header bytes.Buffer
+	header.WriteString("WARC/1.1\r\n")
+	fmt.Fprintf(&header, "WARC-Type: %s\r\n", kind)
+	fmt.Fprintf(&header, "WARC-Record-ID: <urn:uuid:%s>\r\n", uuid.New())
+	fmt.Fprintf(&header, "WARC-Date: %s\r\n", date.UTC().Format(time.RFC3339))
+	if target != "" {
+		fmt.Fprintf(&header, "WARC-Target-URI: %s\r\n", target)
+	}
+	fmt.Fprintf(&header, "WARC-Block-Digest: sha1:%s\r\n", base32.StdEncoding.EncodeToString(digest[:]))
+	fmt.Fprintf(&header, "Content-Type: %s\r\n", contentType)
+	fmt.Fprintf(&header, "Content-Length: %d\r\n\r\n", len(block))
+
+	for _, part := range [][]byte{header.Bytes(), block, []byte("\r\n\r\n")} {
+		if _, err := w.Write(part); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// readWARCPage returns the body of the first response record in a WARC
+// file, which writeWARC makes the archived page
+func readWARCPage(data []byte) ([]byte, error) {
+	r := bufio.NewReader(bytes.NewReader(data))
+	for {
+		version, err := r.ReadString('\n')
+		if err == io.EOF {
+			return nil, errors.New("WARC file has no response record")
+		}
+		if err != nil {
+			return nil, err
+		}
+		if version == "\r\n" {
+			// Blank lines end the previous record
+			continue
+		}
+
+		header, err := textproto.NewReader(r).ReadMIMEHeader()
+		if err != nil {
+			return nil, fmt.Errorf("invalid WARC record: %w", err)
+		}
+		length, err := strconv.Atoi(header.Get("Content-Length"))
+		if err != nil {
+			return nil, fmt.Errorf("invalid WARC record length: %w", err)
+		}
+		block := make([]byte, length)
+		if _, err := io.ReadFull(r, block); err != nil {
+			return nil, fmt.Errorf("truncated WARC record: %w", err)
+		}
+		if header.Get("WARC-Type") != "response" {
+			continue
+		}
+
+		resp, err := http.ReadResponse(bufio.NewReader(bytes.NewReader(block)), nil)
+		if err != nil {
+			return nil, fmt.Errorf("invalid WARC response: %w", err)
+		}
+		defer resp.Body.Close()
+		return io.ReadAll(resp.Body)
+	}
+}
@@ -0,0 +1,85 @@
+package archive
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/base32"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lelopez-io/moxli/internal/fetch"
+)
+
+func TestWriteWARC(t *testing.T) {
+	fetched := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	page := &fetch.Page{
+		FinalURL: "https://example.com/", StatusCode: 200, ContentType: "text/html",
+		Body: []byte("<p>hi</p>"), FetchedAt: fetched,
+	}
+	image := &fetch.Page{
+		FinalURL: "https://example.com/a.png", StatusCode: 200, ContentType: "image/png",
+		Body: pixel, FetchedAt: fetched,
+	}
+
+	var out bytes.Buffer
+	if err := writeWARC(&out, page, []*fetch.Page{image}, fetched); err != nil {
+		t.Fatalf("writeWARC() error = %v", err)
+	}
+	warc := out.String()
+
+	records := strings.Split(warc, "WARC/1.1\r\n")[1:]
+	if len(records) != 3 {
+		t.Fatalf("WARC has %v records, want warcinfo and 2 responses\n%s", len(records), warc)
+	}
+	types := []string{"warcinfo", "response", "response"}
+	targets := []string{"", "https://example.com/", "https://example.com/a.png"}
+	for i, record := range records {
+		if !strings.Contains(record, "WARC-Type: "+types[i]+"\r\n") {
+			t.Errorf("record %d is not a %s record\n%s", i, types[i], record)
+		}
+		if targets[i] != "" && !strings.Contains(record, "WARC-Target-URI: "+targets[i]+"\r\n") {
+			t.Errorf("record %d lacks target %s\n%s", i, targets[i], record)
+		}
+		if !strings.Contains(record, "WARC-Record-ID: <urn:uuid:") {
+			t.Errorf("record %d lacks a record ID", i)
+		}
+	}
+
+	block := "HTTP/1.1 200 OK\r\nContent-Type: text/html\r\nContent-Length: 9\r\n\r\n<p>hi</p>"
+	digest := sha1.Sum([]byte(block))
+	wantDigest := "WARC-Block-Digest: sha1:" + base32.StdEncoding.EncodeToString(digest[:])
+	if !strings.Contains(records[1], block+"\r\n\r\n") || !strings.Contains(records[1], wantDigest) {
+		t.Errorf("page record should hold the response with its digest\n%s", records[1])
+	}
+	if !strings.Contains(records[1], "WARC-Date: 2024-03-01T12:00:00Z\r\n") {
+		t.Errorf("page record should be dated when it was fetched\n%s", records[1])
+	}
+	if !strings.Contains(records[1], "Content-Length: "+strconv.Itoa(len(block))+"\r\n") {
+		t.Errorf("page record should give its block length %v\n%s", len(block), records[1])
+	}
+}
+
+func TestReadWARCPage(t *testing.T) {
+	page := &fetch.Page{FinalURL: "https://example.com/", StatusCode: 200, ContentType: "text/html", Body: []byte("<p>page\r\n\r\nbody</p>")}
+	image := &fetch.Page{FinalURL: "https://example.com/a.png", StatusCode: 200, ContentType: "image/png", Body: pixel}
+
+	var out bytes.Buffer
+	if err := writeWARC(&out, page, []*fetch.Page{image}, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	got, err := readWARCPage(out.Bytes())
+	if err != nil || string(got) != string(page.Body) {
+		t.Errorf("readWARCPage() = %q (error %v), want %q", got, err, page.Body)
+	}
+
+	var info bytes.Buffer
+	writeRecord(&info, "warcinfo", "", "application/warc-fields", []byte("software: moxli\r\n"), time.Now())
+	if _, err := readWARCPage(info.Bytes()); err == nil {
+		t.Error("readWARCPage() of a WARC without responses should fail")
+	}
+	if _, err := readWARCPage(out.Bytes()[:100]); err == nil {
+		t.Error("readWARCPage() of a truncated WARC should fail")
+	}
+}
@@ -0,0 +1,66 @@
+package bookmark
+
+// MoveURL changes the bookmark's URL to newURL and keeps the old URL as an
+// alias, so the bookmark is still found by it. Aliases equal to the new
+// URL are dropped. A link status whose redirect chain passes newURL is
+// trimmed to start there; any other is cleared, as it no longer applies.
+// The collection must be reindexed afterwards.
+func (b *Bookmark) MoveURL(newURL string) error {
+	normalized, err := NormalizeURL(newURL)
+	if err != nil {
+		return err
+	}
+
+	aliases := make([]string, 0, len(b.Aliases)+1)
+	seen := map[string]bool{normalized: true}
+	for _, alias := range append(b.Aliases, b.URL) {
+		key := aliasKey(alias)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		aliases = append(aliases, alias)
+	}
+
+	b.URL = newURL
+	b.NormalizedURL = normalized
+	b.Aliases = aliases
+	b.LinkStatus = b.LinkStatus.from(newURL)
+	return nil
+}
+
+// NormalizedAliases returns the normalized forms of the bookmark's aliases,
+// skipping any that can't be normalized
+func (b *Bookmark) NormalizedAliases() []string {
+	var normalized []string
+	for _, alias := range b.Aliases {
+		if n, err := NormalizeURL(alias); err == nil {
+			normalized = append(normalized, n)
+		}
+	}
+	return normalized
+}
+
+// AddAlias records a former URL of the bookmark unless it is already one
+// of its URLs
+func (b *Bookmark) AddAlias(alias string) {
+	key := aliasKey(alias)
+	if key == aliasKey(b.URL) {
+		return
+	}
+	for _, existing := range b.Aliases {
+		if aliasKey(existing) == key {
+			return
+		}
+	}
+	b.Aliases = append(b.Aliases, alias)
+}
+
+// aliasKey compares URLs by their normalized form, falling back to the raw
+// URL when it can't be normalized
+func aliasKey(rawURL string) string {
+	if n, err := NormalizeURL(rawURL); err == nil {
+		return n
+	}
+	return rawURL
+}
@@ -0,0 +1,131 @@
+package bookmark
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestBookmark_MoveURL(t *testing.T) {
+	b := &Bookmark{URL: "http://old.example.com/page", Aliases: []string{"http://older.example.com/page"}}
+
+	if err := b.MoveURL("https://new.example.com/page"); err != nil {
+		t.Fatalf("MoveURL() error = %v", err)
+	}
+	if b.URL != "https://new.example.com/page" || b.NormalizedURL != "https://new.example.com/page" {
+		t.Errorf("URL = %q, NormalizedURL = %q, want the new URL", b.URL, b.NormalizedURL)
+	}
+	want := []string{"http://older.example.com/page", "http://old.example.com/page"}
+	if !slices.Equal(b.Aliases, want) {
+		t.Errorf("Aliases = %v, want %v", b.Aliases, want)
+	}
+
+	// Moving back to a former URL drops it from the aliases
+	if err := b.MoveURL("http://old.example.com/page/"); err != nil {
+		t.Fatalf("MoveURL() error = %v", err)
+	}
+	want = []string{"http://older.example.com/page", "https://new.example.com/page"}
+	if !slices.Equal(b.Aliases, want) {
+		t.Errorf("Aliases after moving back = %v, want %v", b.Aliases, want)
+	}
+
+	if err := b.MoveURL("://bad"); err == nil {
+		t.Error("MoveURL() with an invalid URL should fail")
+	}
+}
+
+func TestBookmark_MoveURL_LinkStatus(t *testing.T) {
+	status := func() *LinkStatus {
+		return &LinkStatus{
+			StatusCode: 200,
+			FinalURL:   "https://b.example.com/login",
+			Redirects:  []Redirect{{"http://b.example.com/", 301}, {"https://b.example.com/", 302}},
+		}
+	}
+
+	tests := []struct {
+		to        string
+		redirects int
+		final     string
+		cleared   bool
+	}{
+		{"https://b.example.com/", 1, "https://b.example.com/login", false},
+		{"https://b.example.com/login", 0, "", false},
+		{"https://c.example.com/", 0, "", true},
+	}
+
+	for _, tt := range tests {
+		b := &Bookmark{URL: "http://b.example.com/", LinkStatus: status()}
+		if err := b.MoveURL(tt.to); err != nil {
+			t.Fatal(err)
+		}
+		if tt.cleared {
+			if b.LinkStatus != nil {
+				t.Errorf("MoveURL(%s): LinkStatus = %+v, want nil", tt.to, b.LinkStatus)
+			}
+			continue
+		}
+		if b.LinkStatus == nil || len(b.LinkStatus.Redirects) != tt.redirects || b.LinkStatus.FinalURL != tt.final {
+			t.Errorf("MoveURL(%s): LinkStatus = %+v, want %d redirect(s) to %q", tt.to, b.LinkStatus, tt.redirects, tt.final)
+		}
+	}
+}
+
+func TestBookmark_AddAlias(t *testing.T) {
+	b := &Bookmark{URL: "https://example.com/a"}
+	b.AddAlias("https://example.com/a/")
+	b.AddAlias("http://example.com/a")
+	b.AddAlias("http://EXAMPLE.com/a")
+
+	if want := []string{"http://example.com/a"}; !slices.Equal(b.Aliases, want) {
+		t.Errorf("Aliases = %v, want %v", b.Aliases, want)
+	}
+}
+
+func TestCollection_FindByURL_Alias(t *testing.T) {
+	c := NewCollection()
+	moved := &Bookmark{URL: "https://new.example.com", NormalizedURL: "https://new.example.com", Aliases: []string{"http://old.example.com/"}}
+	c.Add(moved)
+
+	if found, ok := c.FindByURL("http://old.example.com/"); !ok || found != moved {
+		t.Error("FindByURL() should find a bookmark by its alias")
+	}
+
+	// A bookmark that has the URL wins over one that used to
+	current := &Bookmark{URL: "http://old.example.com/", NormalizedURL: "http://old.example.com/"}
+	c.Add(current)
+	if found, _ := c.FindByURL("http://old.example.com/"); found != current {
+		t.Error("FindByURL() should prefer the bookmark with the URL over the alias")
+	}
+	c.Reindex()
+	if found, _ := c.FindByURL("http://old.example.com/"); found != current {
+		t.Error("FindByURL() after Reindex() should prefer the bookmark with the URL over the alias")
+	}
+
+	c.Remove(current)
+	if found, _ := c.FindByURL("http://old.example.com/"); found != moved {
+		t.Error("FindByURL() should fall back to the alias once the URL is gone")
+	}
+}
+
+func TestHistory_UndoMoveURL(t *testing.T) {
+	c := newHistoryCollection()
+	h := NewHistory()
+
+	b := c.Bookmarks[0]
+	before := b.Clone()
+	if err := b.MoveURL("https://moved.example.com/a"); err != nil {
+		t.Fatal(err)
+	}
+	c.Reindex()
+	h.Record("Move", DiffOps(before, b))
+
+	if _, err := h.Undo(c); err != nil {
+		t.Fatalf("Undo() error = %v", err)
+	}
+	if b.URL != "https://example.com/a" || len(b.Aliases) != 0 {
+		t.Errorf("After Undo() URL = %q, Aliases = %v, want the original URL and no aliases", b.URL, b.Aliases)
+	}
+	if _, ok := c.FindByURL("https://moved.example.com/a"); ok {
+		t.Error("Undo() should remove the moved URL from the index")
+	}
+}
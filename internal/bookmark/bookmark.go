@@ -1,16 +1,20 @@
 package bookmark
 
-import "time"
+import (
+	"net/url"
+	"strings"
+	"time"
+)
 
 // Bookmark represents a single bookmark with all its metadata.
 // The structure matches Anybox JSON export format for compatibility.
 type Bookmark struct {
 	// Core fields
-	ID            string    `json:"id"`            // Internal UUID
-	URL           string    `json:"url"`           // Original URL
-	NormalizedURL string    `json:"-"`             // Normalized for deduplication (not exported)
-	Title         string    `json:"title"`         // Page title
-	Description   string    `json:"description"`   // Auto-extracted meta description
+	ID            string `json:"id"`          // Internal UUID
+	URL           string `json:"url"`         // Original URL
+	NormalizedURL string `json:"-"`           // Normalized for deduplication (not exported)
+	Title         string `json:"title"`       // Page title
+	Description   string `json:"description"` // Auto-extracted meta description
 
 	// Organization
 	Tags   [][]string `json:"tags"`   // Hierarchical: [["Security", "User Auth"], ["Infrastructure"]]
@@ -29,8 +33,13 @@ type Bookmark struct {
 	Article string `json:"article,omitempty"` // Full saved article text
 
 	// Metadata for moxli
-	Source     string    `json:"source,omitempty"`     // "anybox", "safari", "firefox"
-	ImportedAt time.Time `json:"importedAt,omitempty"` // When imported into moxli
+	Source     string      `json:"source,omitempty"`     // "anybox", "safari", "firefox"
+	ImportedAt time.Time   `json:"importedAt,omitempty"` // When imported into moxli
+	LinkStatus *LinkStatus `json:"linkStatus,omitempty"` // Result of the last `moxli check`
+	Aliases    []string    `json:"aliases,omitempty"`    // Former URLs, still found by Collection.FindByURL
+	Canonical  string      `json:"canonical,omitempty"`  // Canonical URL declared by the page
+	Favicon    string      `json:"favicon,omitempty"`    // Icon URL declared by the page
+	Snapshot   *Snapshot   `json:"snapshot,omitempty"`   // Archived copy from `moxli archive`
 }
 
 // Clone creates a deep copy of the bookmark
@@ -51,5 +60,74 @@ func (b *Bookmark) Clone() *Bookmark {
 		copy(clone.Folder, b.Folder)
 	}
 
+	if b.Aliases != nil {
+		clone.Aliases = make([]string, len(b.Aliases))
+		copy(clone.Aliases, b.Aliases)
+	}
+
+	if b.Snapshot != nil {
+		snapshot := *b.Snapshot
+		clone.Snapshot = &snapshot
+	}
+
+	if b.LinkStatus != nil {
+		status := *b.LinkStatus
+		status.Redirects = append([]Redirect(nil), b.LinkStatus.Redirects...)
+		clone.LinkStatus = &status
+	}
+
 	return &clone
 }
+
+// Domain returns the bookmark's lowercase host without a leading "www.".
+// The normalized URL is preferred when available.
+func (b *Bookmark) Domain() string {
+	raw := b.NormalizedURL
+	if raw == "" {
+		raw = b.URL
+	}
+
+	u, err := url.Parse(raw)
+	if err != nil {
+		return ""
+	}
+
+	return strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+}
+
+// ParseFolder parses a "/"-separated folder path, trimming each segment.
+// A slash escaped as "\/" belongs to the segment.
+// Example: "Bookmarks Bar / CI\/CD" → ["Bookmarks Bar", "CI/CD"]
+func ParseFolder(s string) []string {
+	var folder []string
+	var segment strings.Builder
+	flush := func() {
+		if name := strings.TrimSpace(segment.String()); name != "" {
+			folder = append(folder, name)
+		}
+		segment.Reset()
+	}
+	for i := 0; i < len(s); i++ {
+		switch {
+		case s[i] == '\\' && i+1 < len(s) && s[i+1] == '/':
+			segment.WriteByte('/')
+			i++
+		case s[i] == '/':
+			flush()
+		default:
+			segment.WriteByte(s[i])
+		}
+	}
+	flush()
+	return folder
+}
+
+// FormatFolder renders a folder path in the format accepted by ParseFolder,
+// escaping slashes inside segments
+func FormatFolder(folder []string) string {
+	segments := make([]string, len(folder))
+	for i, name := range folder {
+		segments[i] = strings.ReplaceAll(name, "/", `\/`)
+	}
+	return strings.Join(segments, " / ")
+}
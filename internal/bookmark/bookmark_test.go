@@ -0,0 +1,62 @@
+package bookmark
+
+import "testing"
+
+func TestBookmark_Domain(t *testing.T) {
+	tests := []struct {
+		name string
+		b    Bookmark
+		want string
+	}{
+		{
+			name: "prefers normalized URL",
+			b:    Bookmark{URL: "https://WWW.Example.com/a", NormalizedURL: "https://www.example.com/a"},
+			want: "example.com",
+		},
+		{
+			name: "falls back to URL",
+			b:    Bookmark{URL: "https://Docs.Example.com:8080/a"},
+			want: "docs.example.com",
+		},
+		{
+			name: "empty URL",
+			b:    Bookmark{},
+			want: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.b.Domain(); got != tt.want {
+				t.Errorf("Domain() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseFolder(t *testing.T) {
+	got := ParseFolder(" Bookmarks Bar / Research //Wagmo ")
+	want := []string{"Bookmarks Bar", "Research", "Wagmo"}
+
+	if len(got) != len(want) {
+		t.Fatalf("ParseFolder() = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("ParseFolder()[%d] = %v, want %v", i, got[i], want[i])
+		}
+	}
+
+	if FormatFolder(got) != "Bookmarks Bar / Research / Wagmo" {
+		t.Errorf("FormatFolder() = %q", FormatFolder(got))
+	}
+
+	// Slashes inside a segment survive a round trip
+	folder := []string{"Dev", "CI/CD"}
+	if formatted := FormatFolder(folder); formatted != `Dev / CI\/CD` {
+		t.Errorf("FormatFolder(%v) = %q", folder, formatted)
+	}
+	if parsed := ParseFolder(FormatFolder(folder)); len(parsed) != 2 || parsed[1] != "CI/CD" {
+		t.Errorf("ParseFolder(FormatFolder()) = %v, want %v", parsed, folder)
+	}
+}
@@ -10,7 +10,7 @@ type Collection struct {
 	Metadata  Metadata    `json:"metadata"`  // Collection-level metadata
 
 	// Internal index for deduplication and fast lookups (not exported)
-	urlIndex map[string]*Bookmark `json:"-"` // normalizedURL → first bookmark with that URL
+	urlIndex map[string]*Bookmark `json:"-"` // normalizedURL → first bookmark with that URL, or alias
 }
 
 // Metadata contains collection-level information
@@ -51,7 +51,8 @@ func (c *Collection) Clone() *Collection {
 	return clone
 }
 
-// buildURLIndex constructs the internal URL index for fast lookups
+// buildURLIndex constructs the internal URL index for fast lookups.
+// Current URLs take precedence over aliases.
 func (c *Collection) buildURLIndex() {
 	c.urlIndex = make(map[string]*Bookmark)
 	for _, b := range c.Bookmarks {
@@ -62,6 +63,19 @@ func (c *Collection) buildURLIndex() {
 			}
 		}
 	}
+	for _, b := range c.Bookmarks {
+		c.indexAliases(b)
+	}
+}
+
+// indexAliases adds the bookmark's aliases to the URL index where no other
+// bookmark claims them
+func (c *Collection) indexAliases(b *Bookmark) {
+	for _, alias := range b.NormalizedAliases() {
+		if _, exists := c.urlIndex[alias]; !exists {
+			c.urlIndex[alias] = b
+		}
+	}
 }
 
 // Add adds a bookmark to the collection and updates the index
@@ -69,15 +83,64 @@ func (c *Collection) Add(b *Bookmark) {
 	c.Bookmarks = append(c.Bookmarks, b)
 	c.Updated = time.Now()
 
-	// Update URL index if normalized URL is set
+	// Update the URL index if normalized URL is set, replacing a bookmark found
+	// there only by an alias
 	if b.NormalizedURL != "" {
-		if _, exists := c.urlIndex[b.NormalizedURL]; !exists {
+		if existing, exists := c.urlIndex[b.NormalizedURL]; !exists || existing.NormalizedURL != b.NormalizedURL {
 			c.urlIndex[b.NormalizedURL] = b
 		}
 	}
+	c.indexAliases(b)
+}
+
+// Remove deletes a bookmark from the collection and rebuilds the index.
+// Returns false if the bookmark is not part of the collection.
+func (c *Collection) Remove(b *Bookmark) bool {
+	for i, existing := range c.Bookmarks {
+		if existing == b {
+			c.Bookmarks = append(c.Bookmarks[:i], c.Bookmarks[i+1:]...)
+			c.Updated = time.Now()
+			c.buildURLIndex()
+			return true
+		}
+	}
+	return false
+}
+
+// Reindex rebuilds the URL index after bookmarks were modified in place
+func (c *Collection) Reindex() {
+	c.buildURLIndex()
+}
+
+// ApplyConsolidation replaces a duplicate group with its merged bookmark.
+// The merged bookmark takes the position of the group's first member and the
+// remaining members are removed.
+func (c *Collection) ApplyConsolidation(g *DuplicateGroup, merged *Bookmark) {
+	members := make(map[*Bookmark]bool, len(g.Bookmarks))
+	for _, b := range g.Bookmarks {
+		members[b] = true
+	}
+
+	kept := make([]*Bookmark, 0, len(c.Bookmarks)-len(g.Bookmarks)+1)
+	placed := false
+	for _, b := range c.Bookmarks {
+		if !members[b] {
+			kept = append(kept, b)
+			continue
+		}
+		if !placed {
+			kept = append(kept, merged)
+			placed = true
+		}
+	}
+
+	c.Bookmarks = kept
+	c.buildURLIndex()
+	c.UpdateMetadata()
 }
 
-// FindByURL looks up a bookmark by normalized URL
+// FindByURL looks up a bookmark by normalized URL. Bookmarks are also
+// found by their aliases, unless another bookmark has that URL.
 func (c *Collection) FindByURL(normalizedURL string) (*Bookmark, bool) {
 	// Build index if not already built
 	if c.urlIndex == nil {
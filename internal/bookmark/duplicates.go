@@ -0,0 +1,410 @@
+package bookmark
+
+import (
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+// DuplicateReason describes why a set of bookmarks was grouped together
+type DuplicateReason string
+
+const (
+	// ReasonSameURL groups bookmarks sharing a normalized URL
+	ReasonSameURL DuplicateReason = "same-url"
+	// ReasonDomainTitle groups bookmarks on the same domain with identical titles
+	ReasonDomainTitle DuplicateReason = "same-domain-title"
+	// ReasonFuzzyTitle groups bookmarks whose titles are nearly identical
+	ReasonFuzzyTitle DuplicateReason = "fuzzy-title"
+)
+
+// DefaultTitleThreshold is the minimum TitleSimilarity for fuzzy title matches
+const DefaultTitleThreshold = 0.9
+
+// reasonRank orders reasons from most to least certain
+var reasonRank = map[DuplicateReason]int{
+	ReasonSameURL:     0,
+	ReasonDomainTitle: 1,
+	ReasonFuzzyTitle:  2,
+}
+
+// DuplicateOptions controls which matching strategies FindDuplicates uses.
+// Normalized URL matching is always enabled.
+type DuplicateOptions struct {
+	DomainTitle    bool    // Group same-domain bookmarks with identical titles
+	FuzzyTitle     bool    // Group bookmarks with similar titles
+	TitleThreshold float64 // Minimum similarity for fuzzy titles (0 uses DefaultTitleThreshold)
+}
+
+// Field identifies a user-facing bookmark field
+type Field string
+
+const (
+	FieldURL         Field = "url"
+	FieldTitle       Field = "title"
+	FieldDescription Field = "description"
+	FieldComment     Field = "comment"
+	FieldKeyword     Field = "keyword"
+	FieldFolder      Field = "folder"
+
+	// Fields tracked by the edit history but not pickable during consolidation
+	FieldTags         Field = "tags"
+	FieldStarred      Field = "starred"
+	FieldLastModified Field = "lastModified"
+	FieldAliases      Field = "aliases"
+	FieldCanonical    Field = "canonical"
+	FieldFavicon      Field = "favicon"
+)
+
+// PickableFields lists the fields that can be taken from a specific group member
+var PickableFields = []Field{FieldURL, FieldTitle, FieldDescription, FieldComment, FieldKeyword, FieldFolder}
+
+// DuplicateGroup is a set of bookmarks that appear to refer to the same resource
+type DuplicateGroup struct {
+	Reason    DuplicateReason // Least certain reason that linked the members
+	Key       string          // Normalized URL or normalized title shared by the group
+	Bookmarks []*Bookmark     // Members in collection order
+	Survivor  int             // Index into Bookmarks of the bookmark kept on consolidation
+	Picks     map[Field]int   // Field → index of the member whose value is kept (overrides defaults)
+}
+
+// AutoMergeable reports whether the group can be consolidated without review.
+// Only groups linked purely by normalized URL are considered safe.
+func (g *DuplicateGroup) AutoMergeable() bool {
+	return g.Reason == ReasonSameURL
+}
+
+// FindDuplicates groups bookmarks in the collection that look like duplicates.
+// Groups are returned in the order their first member appears in the collection.
+// A bookmark belongs to at most one group; if different strategies link groups
+// together they are combined and the group takes the least certain reason.
+func FindDuplicates(c *Collection, opts DuplicateOptions) []*DuplicateGroup {
+	threshold := opts.TitleThreshold
+	if threshold <= 0 {
+		threshold = DefaultTitleThreshold
+	}
+
+	uf := newUnionFind(len(c.Bookmarks))
+
+	// Pass 1: identical normalized URLs
+	byURL := make(map[string]int)
+	for i, b := range c.Bookmarks {
+		if b.NormalizedURL == "" {
+			continue
+		}
+		if first, exists := byURL[b.NormalizedURL]; exists {
+			uf.union(first, i, ReasonSameURL)
+		} else {
+			byURL[b.NormalizedURL] = i
+		}
+	}
+
+	// Pass 2: same domain with identical normalized titles
+	if opts.DomainTitle {
+		byDomainTitle := make(map[string]int)
+		for i, b := range c.Bookmarks {
+			title := normalizeTitle(b.Title)
+			domain := b.Domain()
+			if title == "" || domain == "" {
+				continue
+			}
+			key := domain + "\x00" + title
+			if first, exists := byDomainTitle[key]; exists {
+				uf.union(first, i, ReasonDomainTitle)
+			} else {
+				byDomainTitle[key] = i
+			}
+		}
+	}
+
+	// Pass 3: fuzzy titles. Titles are blocked by their first significant
+	// word and sorted by length within a block, so only titles that could
+	// reach the threshold are compared.
+	if opts.FuzzyTitle {
+		type candidate struct {
+			index  int
+			length int
+		}
+		blocks := make(map[string][]candidate)
+		for i, b := range c.Bookmarks {
+			title := normalizeTitle(b.Title)
+			if title == "" {
+				continue
+			}
+			key := titleBlock(title)
+			blocks[key] = append(blocks[key], candidate{i, utf8.RuneCountInString(title)})
+		}
+
+		for _, block := range blocks {
+			slices.SortStableFunc(block, func(a, b candidate) int { return a.length - b.length })
+			for x := 0; x < len(block); x++ {
+				for y := x + 1; y < len(block); y++ {
+					// The edit distance is at least the difference in length,
+					// and every later title is longer still
+					if float64(block[x].length) < threshold*float64(block[y].length) {
+						break
+					}
+					i, j := block[x].index, block[y].index
+					if uf.find(i) == uf.find(j) {
+						continue
+					}
+					if TitleSimilarity(c.Bookmarks[i].Title, c.Bookmarks[j].Title) >= threshold {
+						uf.union(i, j, ReasonFuzzyTitle)
+					}
+				}
+			}
+		}
+	}
+
+	// Collect members per root in collection order
+	members := make(map[int][]int)
+	var roots []int
+	for i := range c.Bookmarks {
+		root := uf.find(i)
+		if _, seen := members[root]; !seen {
+			roots = append(roots, root)
+		}
+		members[root] = append(members[root], i)
+	}
+
+	var groups []*DuplicateGroup
+	for _, root := range roots {
+		idx := members[root]
+		if len(idx) < 2 {
+			continue
+		}
+
+		group := &DuplicateGroup{Reason: uf.reason[root]}
+		for _, i := range idx {
+			group.Bookmarks = append(group.Bookmarks, c.Bookmarks[i])
+		}
+
+		first := group.Bookmarks[0]
+		if group.Reason == ReasonSameURL {
+			group.Key = first.NormalizedURL
+		} else {
+			group.Key = normalizeTitle(first.Title)
+		}
+
+		groups = append(groups, group)
+	}
+
+	return groups
+}
+
+// Consolidate merges the group into a single new bookmark based on the survivor.
+// The survivor's fields are kept, enhanced with data from the other members:
+//   - DateAdded: oldest non-zero value
+//   - LastModified: newest value
+//   - Tags: union of all tag hierarchies
+//   - Description and Article: longest value
+//   - Comment: distinct comments concatenated in member order
+//   - Title, Keyword, Folder: first non-empty value when the survivor has none
+//   - IsStarred: starred if any member is starred
+//
+// Fields listed in Picks are then taken verbatim from the chosen member.
+// The group members are not modified.
+func (g *DuplicateGroup) Consolidate() *Bookmark {
+	survivor := g.Bookmarks[g.Survivor]
+	merged := survivor.Clone()
+
+	comments := []string{}
+	if c := strings.TrimSpace(survivor.Comment); c != "" {
+		comments = append(comments, c)
+	}
+
+	for i, b := range g.Bookmarks {
+		if i == g.Survivor {
+			continue
+		}
+
+		if !b.DateAdded.IsZero() && (merged.DateAdded.IsZero() || b.DateAdded.Before(merged.DateAdded)) {
+			merged.DateAdded = b.DateAdded
+		}
+		if b.LastModified.After(merged.LastModified) {
+			merged.LastModified = b.LastModified
+		}
+
+		for _, tag := range b.Tags {
+			merged.AddTag(tag)
+		}
+		for _, alias := range b.Aliases {
+			merged.AddAlias(alias)
+		}
+
+		if len(b.Description) > len(merged.Description) {
+			merged.Description = b.Description
+		}
+		if len(b.Article) > len(merged.Article) {
+			merged.Article = b.Article
+		}
+
+		if c := strings.TrimSpace(b.Comment); c != "" && !containsString(comments, c) {
+			comments = append(comments, c)
+		}
+
+		if merged.Title == "" {
+			merged.Title = b.Title
+		}
+		if merged.Keyword == "" {
+			merged.Keyword = b.Keyword
+		}
+		if merged.Canonical == "" {
+			merged.Canonical = b.Canonical
+		}
+		if merged.Favicon == "" {
+			merged.Favicon = b.Favicon
+		}
+		if merged.Snapshot == nil && b.Snapshot != nil {
+			snapshot := *b.Snapshot
+			merged.Snapshot = &snapshot
+		}
+		if len(merged.Folder) == 0 && len(b.Folder) > 0 {
+			merged.Folder = append([]string(nil), b.Folder...)
+		}
+
+		merged.IsStarred = merged.IsStarred || b.IsStarred
+	}
+
+	merged.Comment = strings.Join(comments, "\n\n")
+
+	for _, field := range PickableFields {
+		if i, picked := g.Picks[field]; picked && i >= 0 && i < len(g.Bookmarks) {
+			applyPick(merged, g.Bookmarks[i], field)
+		}
+	}
+
+	return merged
+}
+
+// Pick keeps the value of field from the member at index i when consolidating
+func (g *DuplicateGroup) Pick(field Field, i int) {
+	if g.Picks == nil {
+		g.Picks = make(map[Field]int)
+	}
+	g.Picks[field] = i
+}
+
+// Split removes the member at index i from the group, for example when it was
+// grouped by mistake. Survivor and picks are adjusted to the remaining members.
+// Returns the removed bookmark.
+func (g *DuplicateGroup) Split(i int) *Bookmark {
+	removed := g.Bookmarks[i]
+	g.Bookmarks = append(g.Bookmarks[:i:i], g.Bookmarks[i+1:]...)
+
+	switch {
+	case g.Survivor == i:
+		g.Survivor = 0
+	case g.Survivor > i:
+		g.Survivor--
+	}
+
+	for field, member := range g.Picks {
+		switch {
+		case member == i:
+			delete(g.Picks, field)
+		case member > i:
+			g.Picks[field] = member - 1
+		}
+	}
+
+	return removed
+}
+
+// applyPick copies field from source into the merged bookmark
+func applyPick(merged, source *Bookmark, field Field) {
+	switch field {
+	case FieldURL:
+		merged.URL = source.URL
+		merged.NormalizedURL = source.NormalizedURL
+	case FieldTitle:
+		merged.Title = source.Title
+	case FieldDescription:
+		merged.Description = source.Description
+	case FieldComment:
+		merged.Comment = source.Comment
+	case FieldKeyword:
+		merged.Keyword = source.Keyword
+	case FieldFolder:
+		merged.Folder = append([]string(nil), source.Folder...)
+	}
+}
+
+// containsString reports whether list contains s
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+// titleBlock returns the first word of a normalized title that isn't a
+// stopword, or its first word when all of them are
+func titleBlock(title string) string {
+	words := strings.Fields(title)
+	for _, w := range words {
+		if !titleStopwords[w] {
+			return w
+		}
+	}
+	return words[0]
+}
+
+// titleStopwords are words too common at the start of titles to block on
+var titleStopwords = map[string]bool{
+	"a": true, "an": true, "and": true, "are": true, "can": true,
+	"do": true, "for": true, "how": true, "i": true, "in": true,
+	"is": true, "it": true, "my": true, "of": true, "on": true,
+	"the": true, "this": true, "to": true, "what": true, "when": true,
+	"where": true, "which": true, "who": true, "why": true, "you": true,
+	"your": true,
+}
+
+// unionFind tracks duplicate groups by bookmark index
+type unionFind struct {
+	parent []int
+	reason map[int]DuplicateReason // root → least certain reason linking the group
+}
+
+func newUnionFind(n int) *unionFind {
+	parent := make([]int, n)
+	for i := range parent {
+		parent[i] = i
+	}
+	return &unionFind{parent: parent, reason: make(map[int]DuplicateReason)}
+}
+
+// find returns the root of i, compressing the path along the way
+func (u *unionFind) find(i int) int {
+	for u.parent[i] != i {
+		u.parent[i] = u.parent[u.parent[i]]
+		i = u.parent[i]
+	}
+	return i
+}
+
+// union joins the groups of a and b, keeping the lower index as root so
+// groups stay anchored to their first member
+func (u *unionFind) union(a, b int, reason DuplicateReason) {
+	ra, rb := u.find(a), u.find(b)
+	if ra == rb {
+		return
+	}
+	if rb < ra {
+		ra, rb = rb, ra
+	}
+
+	// The combined group is only as certain as its weakest link
+	combined := reason
+	for _, r := range []DuplicateReason{u.reason[ra], u.reason[rb]} {
+		if r != "" && reasonRank[r] > reasonRank[combined] {
+			combined = r
+		}
+	}
+
+	u.parent[rb] = ra
+	u.reason[ra] = combined
+	delete(u.reason, rb)
+}
@@ -0,0 +1,273 @@
+package bookmark
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFindDuplicates_SameURL(t *testing.T) {
+	c := NewCollection()
+	c.Add(&Bookmark{URL: "https://example.com", NormalizedURL: "https://example.com", Title: "Example"})
+	c.Add(&Bookmark{URL: "https://other.com", NormalizedURL: "https://other.com", Title: "Other"})
+	c.Add(&Bookmark{URL: "https://example.com/", NormalizedURL: "https://example.com", Title: "Example Again"})
+
+	groups := FindDuplicates(c, DuplicateOptions{})
+
+	if len(groups) != 1 {
+		t.Fatalf("len(groups) = %v, want 1", len(groups))
+	}
+
+	g := groups[0]
+	if g.Reason != ReasonSameURL {
+		t.Errorf("Reason = %v, want %v", g.Reason, ReasonSameURL)
+	}
+	if g.Key != "https://example.com" {
+		t.Errorf("Key = %v, want https://example.com", g.Key)
+	}
+	if len(g.Bookmarks) != 2 || g.Bookmarks[0] != c.Bookmarks[0] || g.Bookmarks[1] != c.Bookmarks[2] {
+		t.Error("Group should contain both example.com bookmarks in collection order")
+	}
+	if !g.AutoMergeable() {
+		t.Error("Same-URL group should be auto-mergeable")
+	}
+}
+
+func TestFindDuplicates_DomainTitle(t *testing.T) {
+	c := NewCollection()
+	c.Add(&Bookmark{URL: "https://example.com/a", NormalizedURL: "https://example.com/a", Title: "Docs"})
+	c.Add(&Bookmark{URL: "https://www.example.com/b", NormalizedURL: "https://www.example.com/b", Title: "docs!"})
+	c.Add(&Bookmark{URL: "https://other.com/a", NormalizedURL: "https://other.com/a", Title: "Docs"})
+
+	if groups := FindDuplicates(c, DuplicateOptions{}); len(groups) != 0 {
+		t.Errorf("len(groups) = %v, want 0 without DomainTitle", len(groups))
+	}
+
+	groups := FindDuplicates(c, DuplicateOptions{DomainTitle: true})
+	if len(groups) != 1 {
+		t.Fatalf("len(groups) = %v, want 1", len(groups))
+	}
+	if groups[0].Reason != ReasonDomainTitle {
+		t.Errorf("Reason = %v, want %v", groups[0].Reason, ReasonDomainTitle)
+	}
+	if len(groups[0].Bookmarks) != 2 {
+		t.Errorf("len(Bookmarks) = %v, want 2 (other.com is a different domain)", len(groups[0].Bookmarks))
+	}
+	if groups[0].AutoMergeable() {
+		t.Error("Domain-title group should require review")
+	}
+}
+
+func TestFindDuplicates_FuzzyTitle(t *testing.T) {
+	c := NewCollection()
+	c.Add(&Bookmark{URL: "https://a.com", NormalizedURL: "https://a.com", Title: "The Go Programming Language"})
+	c.Add(&Bookmark{URL: "https://b.com", NormalizedURL: "https://b.com", Title: "The Go Programming Languages"})
+	c.Add(&Bookmark{URL: "https://c.com", NormalizedURL: "https://c.com", Title: "The Rust Book"})
+
+	groups := FindDuplicates(c, DuplicateOptions{FuzzyTitle: true})
+	if len(groups) != 1 {
+		t.Fatalf("len(groups) = %v, want 1", len(groups))
+	}
+	if groups[0].Reason != ReasonFuzzyTitle {
+		t.Errorf("Reason = %v, want %v", groups[0].Reason, ReasonFuzzyTitle)
+	}
+	if len(groups[0].Bookmarks) != 2 {
+		t.Errorf("len(Bookmarks) = %v, want 2", len(groups[0].Bookmarks))
+	}
+}
+
+func TestTitleBlock(t *testing.T) {
+	tests := []struct {
+		title string
+		want  string
+	}{
+		{"the go programming language", "go"},
+		{"how to write go code", "write"},
+		{"go by example", "go"},
+		{"how to", "how"},
+	}
+	for _, tt := range tests {
+		if got := titleBlock(tt.title); got != tt.want {
+			t.Errorf("titleBlock(%q) = %q, want %q", tt.title, got, tt.want)
+		}
+	}
+}
+
+func TestFindDuplicates_CombinedGroupTakesWeakestReason(t *testing.T) {
+	c := NewCollection()
+	c.Add(&Bookmark{URL: "https://a.com/x", NormalizedURL: "https://a.com/x", Title: "Release Notes"})
+	c.Add(&Bookmark{URL: "https://a.com/x/", NormalizedURL: "https://a.com/x", Title: "Release Notes"})
+	c.Add(&Bookmark{URL: "https://b.com/y", NormalizedURL: "https://b.com/y", Title: "Release Notes!"})
+
+	groups := FindDuplicates(c, DuplicateOptions{FuzzyTitle: true})
+	if len(groups) != 1 {
+		t.Fatalf("len(groups) = %v, want 1", len(groups))
+	}
+	if len(groups[0].Bookmarks) != 3 {
+		t.Errorf("len(Bookmarks) = %v, want 3", len(groups[0].Bookmarks))
+	}
+	if groups[0].Reason != ReasonFuzzyTitle {
+		t.Errorf("Reason = %v, want %v", groups[0].Reason, ReasonFuzzyTitle)
+	}
+}
+
+func TestDuplicateGroup_Consolidate(t *testing.T) {
+	older := time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC)
+	newer := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	first := &Bookmark{
+		URL:         "https://example.com",
+		Title:       "Example",
+		Description: "Short",
+		Comment:     "first note",
+		Tags:        [][]string{{"security", "auth"}},
+		DateAdded:   newer,
+	}
+	second := &Bookmark{
+		URL:         "https://example.com/",
+		Description: "A much longer description",
+		Comment:     "second note",
+		Keyword:     "ex",
+		Tags:        [][]string{{"security", "auth"}, {"reading"}},
+		IsStarred:   true,
+		DateAdded:   older,
+		Aliases:     []string{"http://example.com"},
+	}
+
+	g := &DuplicateGroup{Bookmarks: []*Bookmark{first, second}}
+	merged := g.Consolidate()
+
+	if merged == first || merged == second {
+		t.Fatal("Consolidate should return a new bookmark")
+	}
+	if merged.Title != "Example" {
+		t.Errorf("Title = %v, want Example (from survivor)", merged.Title)
+	}
+	if !merged.DateAdded.Equal(older) {
+		t.Errorf("DateAdded = %v, want %v (oldest)", merged.DateAdded, older)
+	}
+	if merged.Description != "A much longer description" {
+		t.Errorf("Description = %v, want longest", merged.Description)
+	}
+	if merged.Comment != "first note\n\nsecond note" {
+		t.Errorf("Comment = %q, want concatenated comments", merged.Comment)
+	}
+	if len(merged.Tags) != 2 {
+		t.Errorf("len(Tags) = %v, want 2 (union without duplicates)", len(merged.Tags))
+	}
+	if merged.Keyword != "ex" {
+		t.Errorf("Keyword = %v, want ex (filled from member)", merged.Keyword)
+	}
+	if len(merged.Aliases) != 1 || merged.Aliases[0] != "http://example.com" {
+		t.Errorf("Aliases = %v, want the member's alias", merged.Aliases)
+	}
+	if !merged.IsStarred {
+		t.Error("IsStarred should be true when any member is starred")
+	}
+	if len(first.Tags) != 1 {
+		t.Error("Consolidate should not modify group members")
+	}
+}
+
+func TestCollection_ApplyConsolidation(t *testing.T) {
+	c := NewCollection()
+	a := &Bookmark{URL: "https://a.com", NormalizedURL: "https://a.com"}
+	dup1 := &Bookmark{URL: "https://dup.com", NormalizedURL: "https://dup.com"}
+	b := &Bookmark{URL: "https://b.com", NormalizedURL: "https://b.com"}
+	dup2 := &Bookmark{URL: "https://dup.com/", NormalizedURL: "https://dup.com"}
+	c.Add(a)
+	c.Add(dup1)
+	c.Add(b)
+	c.Add(dup2)
+
+	groups := FindDuplicates(c, DuplicateOptions{})
+	if len(groups) != 1 {
+		t.Fatalf("len(groups) = %v, want 1", len(groups))
+	}
+
+	merged := groups[0].Consolidate()
+	c.ApplyConsolidation(groups[0], merged)
+
+	if len(c.Bookmarks) != 3 {
+		t.Fatalf("len(Bookmarks) = %v, want 3", len(c.Bookmarks))
+	}
+	if c.Bookmarks[1] != merged {
+		t.Error("Merged bookmark should take the position of the first member")
+	}
+
+	found, exists := c.FindByURL("https://dup.com")
+	if !exists || found != merged {
+		t.Error("URL index should point at the merged bookmark")
+	}
+	if c.Metadata.TotalCount != 3 {
+		t.Errorf("TotalCount = %v, want 3", c.Metadata.TotalCount)
+	}
+}
+
+func TestCollection_Remove(t *testing.T) {
+	c := NewCollection()
+	b := &Bookmark{URL: "https://example.com", NormalizedURL: "https://example.com"}
+	c.Add(b)
+
+	if !c.Remove(b) {
+		t.Fatal("Remove() = false, want true")
+	}
+	if len(c.Bookmarks) != 0 {
+		t.Errorf("len(Bookmarks) = %v, want 0", len(c.Bookmarks))
+	}
+	if _, exists := c.FindByURL("https://example.com"); exists {
+		t.Error("Removed bookmark should not be in the index")
+	}
+	if c.Remove(b) {
+		t.Error("Remove() of missing bookmark should return false")
+	}
+}
+
+func TestDuplicateGroup_ConsolidateWithPicks(t *testing.T) {
+	first := &Bookmark{URL: "https://a.com/x", NormalizedURL: "https://a.com/x", Title: "First", Comment: "one"}
+	second := &Bookmark{URL: "https://a.com/y", NormalizedURL: "https://a.com/y", Title: "Second", Comment: "two"}
+
+	g := &DuplicateGroup{Bookmarks: []*Bookmark{first, second}}
+	g.Pick(FieldTitle, 1)
+	g.Pick(FieldURL, 1)
+	g.Pick(FieldComment, 0)
+
+	merged := g.Consolidate()
+
+	if merged.Title != "Second" {
+		t.Errorf("Title = %v, want Second (picked)", merged.Title)
+	}
+	if merged.URL != "https://a.com/y" || merged.NormalizedURL != "https://a.com/y" {
+		t.Errorf("URL = %v, want picked URL with its normalized form", merged.URL)
+	}
+	if merged.Comment != "one" {
+		t.Errorf("Comment = %q, want one (picked instead of concatenated)", merged.Comment)
+	}
+}
+
+func TestDuplicateGroup_Split(t *testing.T) {
+	a := &Bookmark{Title: "A"}
+	b := &Bookmark{Title: "B"}
+	c := &Bookmark{Title: "C"}
+
+	g := &DuplicateGroup{Bookmarks: []*Bookmark{a, b, c}, Survivor: 2}
+	g.Pick(FieldTitle, 1)
+	g.Pick(FieldComment, 2)
+
+	removed := g.Split(1)
+
+	if removed != b {
+		t.Error("Split should return the removed member")
+	}
+	if len(g.Bookmarks) != 2 || g.Bookmarks[0] != a || g.Bookmarks[1] != c {
+		t.Error("Split should keep remaining members in order")
+	}
+	if g.Survivor != 1 {
+		t.Errorf("Survivor = %v, want 1 (shifted)", g.Survivor)
+	}
+	if _, picked := g.Picks[FieldTitle]; picked {
+		t.Error("Pick referring to removed member should be dropped")
+	}
+	if g.Picks[FieldComment] != 1 {
+		t.Errorf("Picks[comment] = %v, want 1 (shifted)", g.Picks[FieldComment])
+	}
+}
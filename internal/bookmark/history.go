@@ -0,0 +1,463 @@
+package bookmark
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// OpKind identifies the type of change an Op records
+type OpKind string
+
+const (
+	OpAdd    OpKind = "add"    // Insert a bookmark
+	OpRemove OpKind = "remove" // Delete a bookmark
+	OpUpdate OpKind = "update" // Change a single field
+	OpRetag  OpKind = "retag"  // Replace a bookmark's tags
+)
+
+// MaxHistoryEntries caps the number of entries a History keeps
+const MaxHistoryEntries = 500
+
+// historyFields lists the fields DiffOps compares
+var historyFields = []Field{
+	FieldURL, FieldTitle, FieldDescription, FieldComment, FieldKeyword,
+	FieldFolder, FieldTags, FieldStarred, FieldLastModified, FieldAliases,
+	FieldCanonical, FieldFavicon,
+}
+
+// Op is a single reversible change to one bookmark, identified by ID.
+// Field values are stored as JSON so the log survives a round trip to disk.
+type Op struct {
+	Kind     OpKind          `json:"kind"`
+	ID       string          `json:"id"`
+	URL      string          `json:"url,omitempty"`      // Normalized URL, used by Replay when IDs differ
+	Index    int             `json:"index,omitempty"`    // Position for add/remove
+	Field    Field           `json:"field,omitempty"`    // Changed field for update/retag
+	Before   json.RawMessage `json:"before,omitempty"`   // Field value before the change
+	After    json.RawMessage `json:"after,omitempty"`    // Field value after the change
+	Bookmark *Bookmark       `json:"bookmark,omitempty"` // Full bookmark for add/remove
+}
+
+// HistoryEntry groups the ops of one user action, undone and redone together
+type HistoryEntry struct {
+	Label string    `json:"label"`
+	Time  time.Time `json:"time"`
+	Ops   []Op      `json:"ops"`
+}
+
+// History is an undo/redo log of collection edits.
+// Entries before Cursor are applied; entries from Cursor on can be redone.
+type History struct {
+	FileHash string         `json:"fileHash,omitempty"` // Hash of the file the log was saved with
+	Entries  []HistoryEntry `json:"entries"`
+	Cursor   int            `json:"cursor"`
+}
+
+// NewHistory creates an empty history
+func NewHistory() *History {
+	return &History{}
+}
+
+// AddOp returns an op inserting b at index
+func AddOp(index int, b *Bookmark) Op {
+	return Op{Kind: OpAdd, ID: b.ID, Index: index, Bookmark: b.Clone()}
+}
+
+// RemoveOp returns an op deleting b. Its position is recorded when applied.
+func RemoveOp(b *Bookmark) Op {
+	return Op{Kind: OpRemove, ID: b.ID, URL: b.NormalizedURL}
+}
+
+// DiffOps returns update and retag ops turning before into after.
+// Both must be versions of the same bookmark.
+func DiffOps(before, after *Bookmark) []Op {
+	var ops []Op
+	for _, field := range historyFields {
+		from, to := fieldValue(before, field), fieldValue(after, field)
+		if bytes.Equal(from, to) {
+			continue
+		}
+		kind := OpUpdate
+		if field == FieldTags {
+			kind = OpRetag
+		}
+		ops = append(ops, Op{Kind: kind, ID: after.ID, URL: before.NormalizedURL, Field: field, Before: from, After: to})
+	}
+	return ops
+}
+
+// ConsolidationOps returns the ops that replace a duplicate group with its
+// merged bookmark, matching Collection.ApplyConsolidation
+func ConsolidationOps(c *Collection, g *DuplicateGroup, merged *Bookmark) []Op {
+	position := len(c.Bookmarks)
+	for i, b := range c.Bookmarks {
+		if b == g.Bookmarks[0] {
+			position = i
+			break
+		}
+	}
+
+	ops := make([]Op, 0, len(g.Bookmarks)+1)
+	for _, b := range g.Bookmarks {
+		ops = append(ops, RemoveOp(b))
+	}
+	return append(ops, AddOp(position, merged))
+}
+
+// Record appends an entry for ops that were already applied to the collection,
+// discarding any entries that could have been redone
+func (h *History) Record(label string, ops []Op) {
+	if len(ops) == 0 {
+		return
+	}
+
+	h.Entries = append(h.Entries[:h.Cursor], HistoryEntry{Label: label, Time: time.Now(), Ops: ops})
+	if len(h.Entries) > MaxHistoryEntries {
+		h.Entries = h.Entries[len(h.Entries)-MaxHistoryEntries:]
+	}
+	h.Cursor = len(h.Entries)
+}
+
+// Do applies ops to the collection and records them as one entry
+func (h *History) Do(c *Collection, label string, ops []Op) error {
+	applied, err := ApplyOps(c, ops)
+	if err != nil {
+		return err
+	}
+	h.Record(label, applied)
+	return nil
+}
+
+// CanUndo reports whether there is an entry to undo
+func (h *History) CanUndo() bool {
+	return h.Cursor > 0
+}
+
+// CanRedo reports whether there is an entry to redo
+func (h *History) CanRedo() bool {
+	return h.Cursor < len(h.Entries)
+}
+
+// Applied returns the entries currently applied, oldest first
+func (h *History) Applied() []HistoryEntry {
+	return h.Entries[:h.Cursor]
+}
+
+// Undo reverts the most recent applied entry
+func (h *History) Undo(c *Collection) (*HistoryEntry, error) {
+	if !h.CanUndo() {
+		return nil, fmt.Errorf("nothing to undo")
+	}
+
+	entry := &h.Entries[h.Cursor-1]
+	inverse := make([]Op, len(entry.Ops))
+	for i, op := range entry.Ops {
+		inverse[len(entry.Ops)-1-i] = op.invert()
+	}
+
+	if _, err := ApplyOps(c, inverse); err != nil {
+		return nil, fmt.Errorf("failed to undo %q: %w", entry.Label, err)
+	}
+	h.Cursor--
+	return entry, nil
+}
+
+// Redo re-applies the most recently undone entry
+func (h *History) Redo(c *Collection) (*HistoryEntry, error) {
+	if !h.CanRedo() {
+		return nil, fmt.Errorf("nothing to redo")
+	}
+
+	entry := &h.Entries[h.Cursor]
+	if _, err := ApplyOps(c, entry.Ops); err != nil {
+		return nil, fmt.Errorf("failed to redo %q: %w", entry.Label, err)
+	}
+	h.Cursor++
+	return entry, nil
+}
+
+// Replay applies entries to another collection, for example a fresh import of
+// the same bookmarks. Bookmarks are found by ID, falling back to the
+// normalized URL they had when the op was recorded. Ops that don't fit the
+// collection are skipped.
+func Replay(c *Collection, entries []HistoryEntry) (applied, skipped int) {
+	a := newOpApplier(c)
+	a.byURL = make(map[string]*Bookmark, len(c.Bookmarks))
+	for _, b := range c.Bookmarks {
+		if _, exists := a.byURL[b.NormalizedURL]; !exists && b.NormalizedURL != "" {
+			a.byURL[b.NormalizedURL] = b
+		}
+	}
+	for _, entry := range entries {
+		for _, op := range entry.Ops {
+			if _, err := a.apply(op); err != nil {
+				skipped++
+				continue
+			}
+			applied++
+		}
+	}
+	a.finish()
+	return applied, skipped
+}
+
+// ApplyOps applies ops to the collection in order. It returns the ops with the
+// positions, bookmark copies and previous values needed to invert them.
+// Either every op is applied or none is: when one fails, the ops before it
+// are reverted and the error is returned.
+func ApplyOps(c *Collection, ops []Op) ([]Op, error) {
+	a := newOpApplier(c)
+	defer a.finish()
+
+	applied := make([]Op, 0, len(ops))
+	for _, op := range ops {
+		resolved, err := a.apply(op)
+		if err != nil {
+			for i := len(applied) - 1; i >= 0; i-- {
+				_, _ = a.apply(applied[i].invert())
+			}
+			return nil, err
+		}
+		applied = append(applied, resolved)
+	}
+	return applied, nil
+}
+
+// invert returns the op that undoes op
+func (op Op) invert() Op {
+	switch op.Kind {
+	case OpAdd:
+		return Op{Kind: OpRemove, ID: op.ID, URL: op.URL, Index: op.Index, Bookmark: op.Bookmark}
+	case OpRemove:
+		return Op{Kind: OpAdd, ID: op.ID, URL: op.URL, Index: op.Index, Bookmark: op.Bookmark}
+	default:
+		op.Before, op.After = op.After, op.Before
+		return op
+	}
+}
+
+// opApplier applies ops to a collection with an ID lookup shared across ops
+type opApplier struct {
+	c     *Collection
+	byID  map[string]*Bookmark
+	byURL map[string]*Bookmark // Fallback lookup, only set when replaying
+}
+
+func newOpApplier(c *Collection) *opApplier {
+	byID := make(map[string]*Bookmark, len(c.Bookmarks))
+	for _, b := range c.Bookmarks {
+		if _, exists := byID[b.ID]; !exists {
+			byID[b.ID] = b
+		}
+	}
+	return &opApplier{c: c, byID: byID}
+}
+
+// apply performs a single op and returns it with its position filled in
+func (a *opApplier) apply(op Op) (Op, error) {
+	switch op.Kind {
+	case OpAdd:
+		if op.Bookmark == nil {
+			return op, fmt.Errorf("add op for %s has no bookmark", op.ID)
+		}
+		b := op.Bookmark.Clone()
+		if b.NormalizedURL == "" {
+			_ = NormalizeBookmarkURL(b)
+		}
+		index := min(max(op.Index, 0), len(a.c.Bookmarks))
+		a.c.Bookmarks = append(a.c.Bookmarks[:index], append([]*Bookmark{b}, a.c.Bookmarks[index:]...)...)
+		a.byID[b.ID] = b
+		op.Index = index
+		return op, nil
+
+	case OpRemove:
+		target, ok := a.find(op)
+		if !ok {
+			return op, fmt.Errorf("bookmark %s not found", op.ID)
+		}
+		index := a.indexOf(target, op.Index)
+		if index < 0 {
+			return op, fmt.Errorf("bookmark %s not found", op.ID)
+		}
+		removed := a.c.Bookmarks[index]
+		a.c.Bookmarks = append(a.c.Bookmarks[:index], a.c.Bookmarks[index+1:]...)
+		delete(a.byID, op.ID)
+		delete(a.byID, removed.ID)
+		if a.byURL != nil && a.byURL[removed.NormalizedURL] == removed {
+			delete(a.byURL, removed.NormalizedURL)
+		}
+		op.Index = index
+		op.Bookmark = removed.Clone()
+		return op, nil
+
+	case OpUpdate, OpRetag:
+		b, ok := a.find(op)
+		if !ok {
+			return op, fmt.Errorf("bookmark %s not found", op.ID)
+		}
+		// Invert to the value the bookmark actually had
+		before := fieldValue(b, op.Field)
+		if err := setFieldValue(b, op.Field, op.After); err != nil {
+			_ = setFieldValue(b, op.Field, before)
+			return op, err
+		}
+		op.Before = before
+		return op, nil
+	}
+
+	return op, fmt.Errorf("unknown op kind %q", op.Kind)
+}
+
+// find returns the bookmark an op refers to. When replaying, a bookmark
+// found by URL is remembered under the op's ID for the ops that follow.
+func (a *opApplier) find(op Op) (*Bookmark, bool) {
+	if b, ok := a.byID[op.ID]; ok {
+		return b, true
+	}
+	if a.byURL == nil || op.URL == "" {
+		return nil, false
+	}
+	b, ok := a.byURL[op.URL]
+	if ok {
+		a.byID[op.ID] = b
+	}
+	return b, ok
+}
+
+// indexOf finds the position of b, checking the hinted position first
+func (a *opApplier) indexOf(b *Bookmark, hint int) int {
+	if hint >= 0 && hint < len(a.c.Bookmarks) && a.c.Bookmarks[hint] == b {
+		return hint
+	}
+	for i, existing := range a.c.Bookmarks {
+		if existing == b {
+			return i
+		}
+	}
+	return -1
+}
+
+// finish rebuilds the collection's index and metadata after applying ops
+func (a *opApplier) finish() {
+	a.c.Reindex()
+	a.c.UpdateMetadata()
+}
+
+// fieldValue returns the JSON encoding of a bookmark field
+func fieldValue(b *Bookmark, field Field) json.RawMessage {
+	var v any
+	switch field {
+	case FieldURL:
+		v = b.URL
+	case FieldTitle:
+		v = b.Title
+	case FieldDescription:
+		v = b.Description
+	case FieldComment:
+		v = b.Comment
+	case FieldKeyword:
+		v = b.Keyword
+	case FieldFolder:
+		v = b.Folder
+	case FieldTags:
+		v = b.Tags
+	case FieldStarred:
+		v = b.IsStarred
+	case FieldLastModified:
+		v = b.LastModified
+	case FieldAliases:
+		v = b.Aliases
+	case FieldCanonical:
+		v = b.Canonical
+	case FieldFavicon:
+		v = b.Favicon
+	}
+
+	data, _ := json.Marshal(v)
+	return data
+}
+
+// setFieldValue decodes a JSON field value into the bookmark
+func setFieldValue(b *Bookmark, field Field, value json.RawMessage) error {
+	var target any
+	switch field {
+	case FieldURL:
+		target = &b.URL
+	case FieldTitle:
+		target = &b.Title
+	case FieldDescription:
+		target = &b.Description
+	case FieldComment:
+		target = &b.Comment
+	case FieldKeyword:
+		target = &b.Keyword
+	case FieldFolder:
+		b.Folder = nil
+		target = &b.Folder
+	case FieldTags:
+		b.Tags = nil
+		target = &b.Tags
+	case FieldStarred:
+		target = &b.IsStarred
+	case FieldLastModified:
+		target = &b.LastModified
+	case FieldAliases:
+		b.Aliases = nil
+		target = &b.Aliases
+	case FieldCanonical:
+		target = &b.Canonical
+	case FieldFavicon:
+		target = &b.Favicon
+	default:
+		return fmt.Errorf("unknown field %q", field)
+	}
+
+	if err := json.Unmarshal(value, target); err != nil {
+		return fmt.Errorf("invalid value for %s: %w", field, err)
+	}
+	if field == FieldURL {
+		return NormalizeBookmarkURL(b)
+	}
+	return nil
+}
+
+// EnsureIDs gives every bookmark a unique ID so history ops can find it.
+// Bookmarks with a missing or repeated ID get a UUID derived from their
+// normalized URL, so a file that doesn't store IDs gets the same ones every
+// time it is loaded and a saved history still applies to it. Repeats of a URL
+// are numbered in collection order.
+// Returns the number of IDs assigned.
+func (c *Collection) EnsureIDs() int {
+	seen := make(map[string]bool, len(c.Bookmarks))
+	assigned := 0
+	for _, b := range c.Bookmarks {
+		if b.ID == "" || seen[b.ID] {
+			b.ID = derivedID(b, seen)
+			assigned++
+		}
+		seen[b.ID] = true
+	}
+	return assigned
+}
+
+// derivedID returns the first UUID named after the bookmark's URL that isn't
+// taken yet
+func derivedID(b *Bookmark, taken map[string]bool) string {
+	name := b.NormalizedURL
+	if name == "" {
+		name = b.URL
+	}
+	for n := 1; ; n++ {
+		key := name
+		if n > 1 {
+			key = fmt.Sprintf("%s#%d", name, n)
+		}
+		if id := uuid.NewSHA1(uuid.NameSpaceURL, []byte(key)).String(); !taken[id] {
+			return id
+		}
+	}
+}
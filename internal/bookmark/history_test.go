@@ -0,0 +1,320 @@
+package bookmark
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+// newHistoryCollection returns a collection of three bookmarks with IDs a, b, c
+func newHistoryCollection() *Collection {
+	c := NewCollection()
+	for _, id := range []string{"a", "b", "c"} {
+		c.Add(&Bookmark{
+			ID:            id,
+			URL:           "https://example.com/" + id,
+			NormalizedURL: "https://example.com/" + id,
+			Title:         "Title " + id,
+			Tags:          [][]string{{"tag-" + id}},
+		})
+	}
+	return c
+}
+
+func ids(c *Collection) string {
+	s := ""
+	for _, b := range c.Bookmarks {
+		s += b.ID
+	}
+	return s
+}
+
+func TestDiffOps(t *testing.T) {
+	before := &Bookmark{ID: "a", URL: "https://example.com", Title: "Old", Tags: [][]string{{"x"}}}
+	after := before.Clone()
+	after.Title = "New"
+	after.Tags = [][]string{{"x"}, {"y", "z"}}
+
+	ops := DiffOps(before, after)
+	if len(ops) != 2 {
+		t.Fatalf("len(ops) = %v, want 2", len(ops))
+	}
+	if ops[0].Kind != OpUpdate || ops[0].Field != FieldTitle {
+		t.Errorf("ops[0] = %v %v, want update title", ops[0].Kind, ops[0].Field)
+	}
+	if ops[1].Kind != OpRetag || ops[1].Field != FieldTags {
+		t.Errorf("ops[1] = %v %v, want retag tags", ops[1].Kind, ops[1].Field)
+	}
+
+	if len(DiffOps(before, before.Clone())) != 0 {
+		t.Error("DiffOps() of identical bookmarks should be empty")
+	}
+}
+
+func TestHistory_UndoRedoUpdate(t *testing.T) {
+	c := newHistoryCollection()
+	h := NewHistory()
+
+	b := c.Bookmarks[1]
+	before := b.Clone()
+	b.Title = "Edited"
+	b.URL = "https://example.com/edited"
+	b.NormalizedURL = "https://example.com/edited"
+	b.AddTag([]string{"new"})
+	h.Record("Edit", DiffOps(before, b))
+
+	if !h.CanUndo() || h.CanRedo() {
+		t.Fatal("History should be undoable and not redoable after Record()")
+	}
+
+	if _, err := h.Undo(c); err != nil {
+		t.Fatalf("Undo() error = %v", err)
+	}
+	if b.Title != "Title b" || b.URL != "https://example.com/b" || len(b.Tags) != 1 {
+		t.Errorf("After Undo() bookmark = %q %q %v, want original values", b.Title, b.URL, b.Tags)
+	}
+	if found, ok := c.FindByURL("https://example.com/b"); !ok || found != b {
+		t.Error("Undo() should reindex the restored URL")
+	}
+
+	if _, err := h.Redo(c); err != nil {
+		t.Fatalf("Redo() error = %v", err)
+	}
+	if b.Title != "Edited" || len(b.Tags) != 2 {
+		t.Errorf("After Redo() bookmark = %q %v, want edited values", b.Title, b.Tags)
+	}
+}
+
+func TestHistory_UndoRemove(t *testing.T) {
+	c := newHistoryCollection()
+	h := NewHistory()
+
+	if err := h.Do(c, "Delete", []Op{RemoveOp(c.Bookmarks[0]), RemoveOp(c.Bookmarks[2])}); err != nil {
+		t.Fatalf("Do() error = %v", err)
+	}
+	if got := ids(c); got != "b" {
+		t.Fatalf("After Do() ids = %v, want b", got)
+	}
+
+	if _, err := h.Undo(c); err != nil {
+		t.Fatalf("Undo() error = %v", err)
+	}
+	if got := ids(c); got != "abc" {
+		t.Errorf("After Undo() ids = %v, want abc", got)
+	}
+	if c.Metadata.TotalCount != 3 {
+		t.Errorf("TotalCount = %v, want 3", c.Metadata.TotalCount)
+	}
+
+	if _, err := h.Redo(c); err != nil {
+		t.Fatalf("Redo() error = %v", err)
+	}
+	if got := ids(c); got != "b" {
+		t.Errorf("After Redo() ids = %v, want b", got)
+	}
+}
+
+func TestHistory_Consolidation(t *testing.T) {
+	c := newHistoryCollection()
+	h := NewHistory()
+
+	g := &DuplicateGroup{Bookmarks: []*Bookmark{c.Bookmarks[1], c.Bookmarks[2]}}
+	merged := g.Consolidate()
+	if err := h.Do(c, "Merge", ConsolidationOps(c, g, merged)); err != nil {
+		t.Fatalf("Do() error = %v", err)
+	}
+	if len(c.Bookmarks) != 2 || c.Bookmarks[1].ID != "b" || len(c.Bookmarks[1].Tags) != 2 {
+		t.Fatalf("After merge bookmarks = %v, want a and merged b", ids(c))
+	}
+
+	if _, err := h.Undo(c); err != nil {
+		t.Fatalf("Undo() error = %v", err)
+	}
+	if got := ids(c); got != "abc" {
+		t.Errorf("After Undo() ids = %v, want abc", got)
+	}
+	if len(c.Bookmarks[1].Tags) != 1 {
+		t.Errorf("Undo() should restore the survivor's tags, got %v", c.Bookmarks[1].Tags)
+	}
+}
+
+func TestHistory_RecordDiscardsRedo(t *testing.T) {
+	c := newHistoryCollection()
+	h := NewHistory()
+
+	_ = h.Do(c, "first", []Op{RemoveOp(c.Bookmarks[0])})
+	_, _ = h.Undo(c)
+	_ = h.Do(c, "second", []Op{RemoveOp(c.Bookmarks[1])})
+
+	if len(h.Entries) != 1 || h.Entries[0].Label != "second" {
+		t.Errorf("Entries = %v, want only second", h.Entries)
+	}
+	if h.CanRedo() {
+		t.Error("Recording after undo should drop the redo entries")
+	}
+}
+
+func TestHistory_UndoEmpty(t *testing.T) {
+	h := NewHistory()
+	if _, err := h.Undo(NewCollection()); err == nil {
+		t.Error("Undo() on empty history should fail")
+	}
+	if _, err := h.Redo(NewCollection()); err == nil {
+		t.Error("Redo() on empty history should fail")
+	}
+}
+
+func TestHistory_JSONRoundTrip(t *testing.T) {
+	c := newHistoryCollection()
+	h := NewHistory()
+
+	b := c.Bookmarks[0]
+	before := b.Clone()
+	b.IsStarred = true
+	b.Folder = []string{"Bar", "Research"}
+	b.LastModified = time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	h.Record("Edit", DiffOps(before, b))
+	_ = h.Do(c, "Delete", []Op{RemoveOp(c.Bookmarks[2])})
+
+	data, err := json.Marshal(h)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	var loaded History
+	if err := json.Unmarshal(data, &loaded); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+
+	for loaded.CanUndo() {
+		if _, err := loaded.Undo(c); err != nil {
+			t.Fatalf("Undo() error = %v", err)
+		}
+	}
+	if got := ids(c); got != "abc" {
+		t.Errorf("ids = %v, want abc", got)
+	}
+	if b.IsStarred || len(b.Folder) != 0 || !b.LastModified.IsZero() {
+		t.Errorf("Bookmark not restored: starred=%v folder=%v modified=%v", b.IsStarred, b.Folder, b.LastModified)
+	}
+}
+
+func TestReplay(t *testing.T) {
+	c := newHistoryCollection()
+	h := NewHistory()
+
+	b := c.Bookmarks[0]
+	before := b.Clone()
+	b.Title = "Renamed"
+	h.Record("Edit", DiffOps(before, b))
+	_ = h.Do(c, "Delete", []Op{RemoveOp(c.Bookmarks[1])})
+
+	// A fresh copy without bookmark b
+	target := newHistoryCollection()
+	target.Remove(target.Bookmarks[1])
+
+	applied, skipped := Replay(target, h.Applied())
+	if applied != 1 || skipped != 1 {
+		t.Errorf("Replay() = %v, %v, want 1, 1", applied, skipped)
+	}
+	if target.Bookmarks[0].Title != "Renamed" {
+		t.Errorf("Title = %v, want Renamed", target.Bookmarks[0].Title)
+	}
+}
+
+func TestCollection_EnsureIDs(t *testing.T) {
+	c := NewCollection()
+	c.Add(&Bookmark{ID: "a"})
+	c.Add(&Bookmark{})
+	c.Add(&Bookmark{ID: "a"})
+
+	if got := c.EnsureIDs(); got != 2 {
+		t.Errorf("EnsureIDs() = %v, want 2", got)
+	}
+	if c.Bookmarks[0].ID != "a" {
+		t.Errorf("First ID = %v, want a", c.Bookmarks[0].ID)
+	}
+	if c.Bookmarks[1].ID == "" || c.Bookmarks[2].ID == "a" || c.Bookmarks[1].ID == c.Bookmarks[2].ID {
+		t.Error("EnsureIDs() should assign unique IDs")
+	}
+}
+
+func TestCollection_EnsureIDsStable(t *testing.T) {
+	load := func() *Collection {
+		c := NewCollection()
+		c.Add(&Bookmark{URL: "https://a.com", NormalizedURL: "https://a.com"})
+		c.Add(&Bookmark{URL: "https://b.com", NormalizedURL: "https://b.com"})
+		c.Add(&Bookmark{URL: "https://a.com/", NormalizedURL: "https://a.com"})
+		c.EnsureIDs()
+		return c
+	}
+
+	first, second := load(), load()
+	for i := range first.Bookmarks {
+		if first.Bookmarks[i].ID != second.Bookmarks[i].ID {
+			t.Errorf("Bookmarks[%d].ID = %v, then %v; want the same ID on every load", i, first.Bookmarks[i].ID, second.Bookmarks[i].ID)
+		}
+	}
+	if first.Bookmarks[0].ID == first.Bookmarks[2].ID {
+		t.Error("EnsureIDs() should give repeated URLs different IDs")
+	}
+}
+
+func TestApplyOps_AllOrNothing(t *testing.T) {
+	c := newHistoryCollection()
+	titles := make([]string, len(c.Bookmarks))
+	for i, b := range c.Bookmarks {
+		titles[i] = b.Title
+	}
+
+	edited := c.Bookmarks[1].Clone()
+	edited.Title = "Renamed"
+	ops := append(DiffOps(c.Bookmarks[1], edited),
+		RemoveOp(c.Bookmarks[0]),
+		Op{Kind: OpRemove, ID: "missing"},
+		AddOp(0, &Bookmark{ID: "new", URL: "https://new.example"}),
+	)
+
+	h := NewHistory()
+	if err := h.Do(c, "Broken", ops); err == nil {
+		t.Fatal("Do() should fail on the missing bookmark")
+	}
+	if h.CanUndo() {
+		t.Error("A failed Do() should not be recorded")
+	}
+	if got := ids(c); got != "abc" {
+		t.Fatalf("ids = %v, want abc", got)
+	}
+	for i, b := range c.Bookmarks {
+		if b.Title != titles[i] {
+			t.Errorf("Bookmarks[%d].Title = %q, want %q", i, b.Title, titles[i])
+		}
+	}
+}
+
+func TestReplay_FallsBackToURL(t *testing.T) {
+	c := newHistoryCollection()
+	h := NewHistory()
+
+	b := c.Bookmarks[2]
+	before := b.Clone()
+	b.Title = "Renamed"
+	h.Record("Edit", DiffOps(before, b))
+	_ = h.Do(c, "Delete", []Op{RemoveOp(c.Bookmarks[0])})
+
+	// A re-import assigns new IDs to the same URLs
+	target := newHistoryCollection()
+	for _, b := range target.Bookmarks {
+		b.ID = "new-" + b.ID
+	}
+
+	applied, skipped := Replay(target, h.Applied())
+	if applied != 2 || skipped != 0 {
+		t.Errorf("Replay() = %v, %v, want 2, 0", applied, skipped)
+	}
+	if got := ids(target); got != "new-bnew-c" {
+		t.Errorf("ids = %v, want new-bnew-c", got)
+	}
+	if target.Bookmarks[1].Title != "Renamed" {
+		t.Errorf("Title = %v, want Renamed", target.Bookmarks[1].Title)
+	}
+}
@@ -0,0 +1,94 @@
+package bookmark
+
+import (
+	"net/http"
+	"slices"
+	"time"
+)
+
+// LinkStatus is the outcome of the last link check of a bookmark's URL
+type LinkStatus struct {
+	StatusCode int        `json:"statusCode,omitempty"` // HTTP status of the final response, 0 if none was received
+	FinalURL   string     `json:"finalURL,omitempty"`   // Where redirects led, empty when there were none
+	Redirects  []Redirect `json:"redirects,omitempty"`  // Redirects followed, in order
+	Error      string     `json:"error,omitempty"`      // Why no response was received
+	CheckedAt  time.Time  `json:"checkedAt"`
+}
+
+// Redirect is one step of a redirect chain: a URL and the redirect status
+// it answered with
+type Redirect struct {
+	URL        string `json:"url"`
+	StatusCode int    `json:"statusCode"`
+}
+
+// Permanent reports whether the redirect status is permanent (301 or 308)
+func (r Redirect) Permanent() bool {
+	return r.StatusCode == http.StatusMovedPermanently || r.StatusCode == http.StatusPermanentRedirect
+}
+
+// Dead reports whether the link is broken: no response was received, or
+// the server answered with an error. 401, 403 and 429 are not counted, as
+// they usually mean the page exists but is behind a login or turns away
+// automated requests.
+func (s *LinkStatus) Dead() bool {
+	if s == nil {
+		return false
+	}
+	switch s.StatusCode {
+	case 0:
+		return s.Error != ""
+	case http.StatusUnauthorized, http.StatusForbidden, http.StatusTooManyRequests:
+		return false
+	}
+	return s.StatusCode >= 400
+}
+
+// Redirected reports whether the link redirected to another URL
+func (s *LinkStatus) Redirected() bool {
+	return s != nil && s.FinalURL != ""
+}
+
+// PermanentURL returns where the leading permanent redirects of the chain
+// lead, the URL a bookmark can safely move to. It is empty when the link
+// is dead or its first redirect is temporary.
+func (s *LinkStatus) PermanentURL() string {
+	if s.Dead() || !s.Redirected() {
+		return ""
+	}
+
+	target := ""
+	for i, r := range s.Redirects {
+		if !r.Permanent() {
+			break
+		}
+		if i+1 < len(s.Redirects) {
+			target = s.Redirects[i+1].URL
+		} else {
+			target = s.FinalURL
+		}
+	}
+	return target
+}
+
+// from returns the status as seen from a URL of its redirect chain, or nil
+// when the URL is not part of it
+func (s *LinkStatus) from(rawURL string) *LinkStatus {
+	if s == nil {
+		return nil
+	}
+
+	trimmed := *s
+	if rawURL == s.FinalURL {
+		trimmed.FinalURL = ""
+		trimmed.Redirects = nil
+		return &trimmed
+	}
+
+	i := slices.IndexFunc(s.Redirects, func(r Redirect) bool { return r.URL == rawURL })
+	if i < 0 {
+		return nil
+	}
+	trimmed.Redirects = slices.Clone(s.Redirects[i:])
+	return &trimmed
+}
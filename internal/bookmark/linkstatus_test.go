@@ -0,0 +1,96 @@
+package bookmark
+
+import (
+	"testing"
+)
+
+func TestLinkStatus(t *testing.T) {
+	tests := []struct {
+		name           string
+		status         *LinkStatus
+		dead, redirect bool
+	}{
+		{"unchecked", nil, false, false},
+		{"ok", &LinkStatus{StatusCode: 200}, false, false},
+		{"redirected", &LinkStatus{StatusCode: 200, FinalURL: "https://new.example.com/"}, false, true},
+		{"not found", &LinkStatus{StatusCode: 404}, true, false},
+		{"gone after redirect", &LinkStatus{StatusCode: 410, FinalURL: "https://new.example.com/"}, true, true},
+		{"server error", &LinkStatus{StatusCode: 503}, true, false},
+		{"forbidden", &LinkStatus{StatusCode: 403}, false, false},
+		{"rate limited", &LinkStatus{StatusCode: 429}, false, false},
+		{"no response", &LinkStatus{Error: "no such host"}, true, false},
+	}
+
+	for _, tt := range tests {
+		if got := tt.status.Dead(); got != tt.dead {
+			t.Errorf("%s: Dead() = %v, want %v", tt.name, got, tt.dead)
+		}
+		if got := tt.status.Redirected(); got != tt.redirect {
+			t.Errorf("%s: Redirected() = %v, want %v", tt.name, got, tt.redirect)
+		}
+	}
+}
+
+func TestBookmark_Clone_LinkStatus(t *testing.T) {
+	b := &Bookmark{
+		URL:        "https://example.com",
+		LinkStatus: &LinkStatus{StatusCode: 200, Redirects: []Redirect{{"http://example.com", 301}}},
+		Aliases:    []string{"http://example.com"},
+	}
+	clone := b.Clone()
+	clone.LinkStatus.StatusCode = 404
+	clone.LinkStatus.Redirects[0].StatusCode = 302
+	clone.Aliases[0] = "changed"
+
+	if b.LinkStatus.StatusCode != 200 {
+		t.Errorf("LinkStatus.StatusCode = %v after changing the clone, want 200", b.LinkStatus.StatusCode)
+	}
+	if b.LinkStatus.Redirects[0].StatusCode != 301 {
+		t.Errorf("Redirects[0].StatusCode = %v after changing the clone, want 301", b.LinkStatus.Redirects[0].StatusCode)
+	}
+	if b.Aliases[0] != "http://example.com" {
+		t.Errorf("Aliases[0] = %q after changing the clone, want the original", b.Aliases[0])
+	}
+}
+
+func TestLinkStatus_PermanentURL(t *testing.T) {
+	tests := []struct {
+		name   string
+		status *LinkStatus
+		want   string
+	}{
+		{"unchecked", nil, ""},
+		{"not redirected", &LinkStatus{StatusCode: 200}, ""},
+		{"permanent", &LinkStatus{
+			StatusCode: 200,
+			FinalURL:   "https://b.example.com/",
+			Redirects:  []Redirect{{"http://a.example.com/", 301}},
+		}, "https://b.example.com/"},
+		{"permanent chain", &LinkStatus{
+			StatusCode: 200,
+			FinalURL:   "https://c.example.com/",
+			Redirects:  []Redirect{{"http://a.example.com/", 301}, {"https://b.example.com/", 308}},
+		}, "https://c.example.com/"},
+		{"temporary", &LinkStatus{
+			StatusCode: 200,
+			FinalURL:   "https://b.example.com/login",
+			Redirects:  []Redirect{{"https://b.example.com/", 302}},
+		}, ""},
+		{"permanent then temporary", &LinkStatus{
+			StatusCode: 200,
+			FinalURL:   "https://b.example.com/login",
+			Redirects:  []Redirect{{"http://b.example.com/", 301}, {"https://b.example.com/", 307}},
+		}, "https://b.example.com/"},
+		{"dead", &LinkStatus{
+			StatusCode: 404,
+			FinalURL:   "https://b.example.com/",
+			Redirects:  []Redirect{{"http://a.example.com/", 301}},
+		}, ""},
+	}
+
+	for _, tt := range tests {
+		if got := tt.status.PermanentURL(); got != tt.want {
+			t.Errorf("%s: PermanentURL() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package bookmark
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Similarity returns a score between 0 and 1 describing how alike two strings are.
+// The score is the Levenshtein edit distance relative to the longer string, so
+// identical strings score 1 and completely different strings score 0.
+func Similarity(a, b string) float64 {
+	if a == b {
+		return 1
+	}
+
+	ra, rb := []rune(a), []rune(b)
+	longest := max(len(ra), len(rb))
+	if longest == 0 {
+		return 1
+	}
+
+	return 1 - float64(levenshtein(ra, rb))/float64(longest)
+}
+
+// TitleSimilarity compares two titles ignoring case, punctuation and spacing.
+// Empty titles never match anything.
+func TitleSimilarity(a, b string) float64 {
+	na, nb := normalizeTitle(a), normalizeTitle(b)
+	if na == "" || nb == "" {
+		return 0
+	}
+	return Similarity(na, nb)
+}
+
+// normalizeTitle lowercases a title and collapses punctuation and whitespace into single spaces
+// Example: "Go: The  Language!" → "go the language"
+func normalizeTitle(s string) string {
+	words := strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	return strings.Join(words, " ")
+}
+
+// levenshtein computes the edit distance between two rune slices using two rolling rows
+func levenshtein(a, b []rune) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+
+	return prev[len(b)]
+}
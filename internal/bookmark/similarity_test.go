@@ -0,0 +1,51 @@
+package bookmark
+
+import (
+	"math"
+	"testing"
+)
+
+func TestSimilarity(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+		want float64
+	}{
+		{name: "identical", a: "kitten", b: "kitten", want: 1},
+		{name: "both empty", a: "", b: "", want: 1},
+		{name: "one empty", a: "abc", b: "", want: 0},
+		{name: "classic edit distance", a: "kitten", b: "sitting", want: 1 - 3.0/7.0},
+		{name: "completely different", a: "abc", b: "xyz", want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Similarity(tt.a, tt.b)
+			if math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("Similarity(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTitleSimilarity(t *testing.T) {
+	if got := TitleSimilarity("Go: The Language!", "go the  language"); got != 1 {
+		t.Errorf("TitleSimilarity() = %v, want 1 (punctuation and case ignored)", got)
+	}
+
+	if got := TitleSimilarity("", "anything"); got != 0 {
+		t.Errorf("TitleSimilarity() = %v, want 0 for empty title", got)
+	}
+
+	if got := TitleSimilarity("Effective Go", "Effective Go - The Go Programming Language"); got >= 0.9 {
+		t.Errorf("TitleSimilarity() = %v, want < 0.9 for loosely related titles", got)
+	}
+}
+
+func TestNormalizeTitle(t *testing.T) {
+	got := normalizeTitle("  Go: The  Language! ")
+	want := "go the language"
+	if got != want {
+		t.Errorf("normalizeTitle() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,13 @@
+package bookmark
+
+import "time"
+
+// Snapshot records an archived copy of a bookmarked page
+type Snapshot struct {
+	Path       string    `json:"path"`   // File in the snapshot store, relative to the collection's directory
+	Hash       string    `json:"hash"`   // "sha256:" and the hex digest of the file
+	Format     string    `json:"format"` // "html" or "warc"
+	URL        string    `json:"url"`    // Page URL after redirects
+	Size       int64     `json:"size"`   // File size in bytes
+	ArchivedAt time.Time `json:"archivedAt"`
+}
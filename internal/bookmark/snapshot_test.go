@@ -0,0 +1,13 @@
+package bookmark
+
+import "testing"
+
+func TestBookmark_Clone_Snapshot(t *testing.T) {
+	b := &Bookmark{URL: "https://example.com", Snapshot: &Snapshot{Path: "snapshots/ab/abc.html", Format: "html"}}
+	clone := b.Clone()
+	clone.Snapshot.Path = "changed"
+
+	if b.Snapshot.Path != "snapshots/ab/abc.html" {
+		t.Errorf("Snapshot.Path = %q after changing the clone, want the original", b.Snapshot.Path)
+	}
+}
@@ -0,0 +1,153 @@
+package bookmark
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+	"time"
+)
+
+// SortMode is an order for listing bookmarks
+type SortMode int
+
+const (
+	SortFileOrder    SortMode = iota // As stored in the collection
+	SortAddedDesc                    // Newest first
+	SortAddedAsc                     // Oldest first
+	SortModified                     // Most recently modified first
+	SortTitle                        // Title A-Z
+	SortDomain                       // Domain A-Z
+	SortFolder                       // Folder path A-Z
+	SortTagCount                     // Most tags first
+	SortStarredFirst                 // Starred bookmarks first
+)
+
+// sortModeNames are the labels shown for each mode, in cycling order
+var sortModeNames = []string{
+	SortFileOrder:    "file order",
+	SortAddedDesc:    "newest added",
+	SortAddedAsc:     "oldest added",
+	SortModified:     "last modified",
+	SortTitle:        "title",
+	SortDomain:       "domain",
+	SortFolder:       "folder",
+	SortTagCount:     "most tags",
+	SortStarredFirst: "starred first",
+}
+
+// String returns the label of the sort mode
+func (s SortMode) String() string {
+	if s < 0 || int(s) >= len(sortModeNames) {
+		return "unknown"
+	}
+	return sortModeNames[s]
+}
+
+// ParseSortMode returns the mode with the given label. Dashes may stand in
+// for spaces, as in "newest-added".
+func ParseSortMode(name string) (SortMode, bool) {
+	name = strings.ReplaceAll(strings.ToLower(strings.TrimSpace(name)), "-", " ")
+	for mode, label := range sortModeNames {
+		if label == name {
+			return SortMode(mode), true
+		}
+	}
+	return SortFileOrder, false
+}
+
+// Next returns the mode after s, wrapping back to file order
+func (s SortMode) Next() SortMode {
+	return (s + 1) % SortMode(len(sortModeNames))
+}
+
+// sortKey holds the values a bookmark is compared by, computed once per sort
+type sortKey struct {
+	bookmark *Bookmark
+	text     string    // Title, domain or folder, lowercased
+	time     time.Time // Date added or last modified
+	count    int       // Number of tags, or 1 for starred
+}
+
+// Sort returns the bookmarks ordered by mode. The sort is stable, so
+// bookmarks that compare equal keep their relative order, and bookmarks
+// missing the sorted value (no title, no date, no folder) go last.
+// The input slice is not modified.
+func Sort(bookmarks []*Bookmark, mode SortMode) []*Bookmark {
+	sorted := slices.Clone(bookmarks)
+	if mode == SortFileOrder {
+		return sorted
+	}
+
+	keys := make([]sortKey, len(bookmarks))
+	for i, b := range bookmarks {
+		keys[i] = newSortKey(b, mode)
+	}
+
+	slices.SortStableFunc(keys, func(a, b sortKey) int {
+		switch mode {
+		case SortAddedDesc, SortModified:
+			return compareMissingLast(a.time.IsZero(), b.time.IsZero(), func() int {
+				return b.time.Compare(a.time)
+			})
+		case SortAddedAsc:
+			return compareMissingLast(a.time.IsZero(), b.time.IsZero(), func() int {
+				return a.time.Compare(b.time)
+			})
+		case SortTitle, SortDomain, SortFolder:
+			return compareMissingLast(a.text == "", b.text == "", func() int {
+				return strings.Compare(a.text, b.text)
+			})
+		case SortTagCount, SortStarredFirst:
+			return cmp.Compare(b.count, a.count)
+		}
+		return 0
+	})
+
+	for i, k := range keys {
+		sorted[i] = k.bookmark
+	}
+	return sorted
+}
+
+// newSortKey computes the comparison value of b for mode
+func newSortKey(b *Bookmark, mode SortMode) sortKey {
+	k := sortKey{bookmark: b}
+	switch mode {
+	case SortAddedDesc, SortAddedAsc:
+		k.time = b.DateAdded
+	case SortModified:
+		k.time = b.LastModified
+		if k.time.IsZero() {
+			k.time = b.DateAdded
+		}
+	case SortTitle:
+		k.text = strings.ToLower(strings.TrimSpace(b.Title))
+	case SortDomain:
+		k.text = b.Domain()
+	case SortFolder:
+		// Separate segments with a byte below any printable character so
+		// parents sort before their children
+		k.text = strings.ToLower(strings.Join(b.Folder, "\x00"))
+	case SortTagCount:
+		k.count = len(b.Tags)
+	case SortStarredFirst:
+		if b.IsStarred {
+			k.count = 1
+		}
+	}
+	return k
+}
+
+// compareMissingLast orders values that are present before missing ones,
+// comparing present values with compare
+func compareMissingLast(aMissing, bMissing bool, compare func() int) int {
+	switch {
+	case aMissing && bMissing:
+		return 0
+	case aMissing:
+		return 1
+	case bMissing:
+		return -1
+	}
+	return compare()
+}
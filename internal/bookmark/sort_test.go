@@ -0,0 +1,88 @@
+package bookmark
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestSort(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2024, 1, d, 0, 0, 0, 0, time.UTC) }
+	a := &Bookmark{Title: "beta", URL: "https://www.zeta.example/a", DateAdded: day(2), Folder: []string{"Work", "Go"}, Tags: [][]string{{"x"}}}
+	b := &Bookmark{Title: "Alpha", URL: "https://alpha.example", DateAdded: day(3), LastModified: day(10), IsStarred: true}
+	c := &Bookmark{Title: "", URL: "https://mid.example", DateAdded: day(1), Folder: []string{"Work"}, Tags: [][]string{{"x"}, {"y"}}}
+	d := &Bookmark{Title: "gamma", URL: "https://alpha.example/2", Folder: []string{"Home"}, IsStarred: true}
+	bookmarks := []*Bookmark{a, b, c, d}
+
+	tests := []struct {
+		mode SortMode
+		want []*Bookmark
+	}{
+		{SortFileOrder, []*Bookmark{a, b, c, d}},
+		{SortAddedDesc, []*Bookmark{b, a, c, d}},
+		{SortAddedAsc, []*Bookmark{c, a, b, d}},
+		{SortModified, []*Bookmark{b, a, c, d}},
+		{SortTitle, []*Bookmark{b, a, d, c}},
+		{SortDomain, []*Bookmark{b, d, c, a}},
+		{SortFolder, []*Bookmark{d, c, a, b}},
+		{SortTagCount, []*Bookmark{c, a, b, d}},
+		{SortStarredFirst, []*Bookmark{b, d, a, c}},
+	}
+
+	for _, tt := range tests {
+		got := Sort(bookmarks, tt.mode)
+		if !slices.Equal(got, tt.want) {
+			urls := func(bs []*Bookmark) []string {
+				out := []string{}
+				for _, b := range bs {
+					out = append(out, b.URL)
+				}
+				return out
+			}
+			t.Errorf("Sort(%v) = %v, want %v", tt.mode, urls(got), urls(tt.want))
+		}
+	}
+
+	if !slices.Equal(bookmarks, []*Bookmark{a, b, c, d}) {
+		t.Error("Sort() modified its input")
+	}
+}
+
+func TestSortMode_Next(t *testing.T) {
+	seen := map[SortMode]bool{}
+	mode := SortFileOrder
+	for {
+		if seen[mode] {
+			break
+		}
+		seen[mode] = true
+		if mode.String() == "unknown" {
+			t.Errorf("SortMode(%d) has no name", mode)
+		}
+		mode = mode.Next()
+	}
+	if mode != SortFileOrder || len(seen) != 9 {
+		t.Errorf("Next() cycled through %v modes back to %v, want 9 back to file order", len(seen), mode)
+	}
+}
+
+func TestParseSortMode(t *testing.T) {
+	tests := []struct {
+		name   string
+		want   SortMode
+		wantOK bool
+	}{
+		{"title", SortTitle, true},
+		{"newest-added", SortAddedDesc, true},
+		{"Starred First", SortStarredFirst, true},
+		{"file order", SortFileOrder, true},
+		{"popularity", SortFileOrder, false},
+	}
+
+	for _, tt := range tests {
+		got, ok := ParseSortMode(tt.name)
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("ParseSortMode(%q) = %v, %v, want %v, %v", tt.name, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
@@ -86,3 +86,134 @@ func collapseRepeatedDashes(s string) string {
 	re := regexp.MustCompile(`-+`)
 	return re.ReplaceAllString(s, "-")
 }
+
+// HasTag reports whether the bookmark has the exact tag hierarchy
+func (b *Bookmark) HasTag(path []string) bool {
+	return containsPath(b.Tags, path)
+}
+
+// HasTagUnder reports whether the bookmark has the tag hierarchy or one
+// nested under it, so ["security"] matches ["security", "auth"]
+func (b *Bookmark) HasTagUnder(path []string) bool {
+	for _, tag := range b.Tags {
+		if hasPathPrefix(tag, path) {
+			return true
+		}
+	}
+	return false
+}
+
+// AddTag appends a tag hierarchy unless the bookmark already has it.
+// Returns true if the tag was added.
+func (b *Bookmark) AddTag(path []string) bool {
+	if len(path) == 0 || b.HasTag(path) {
+		return false
+	}
+	b.Tags = append(b.Tags, append([]string(nil), path...))
+	return true
+}
+
+// RemoveTag removes the tag hierarchy and any hierarchies nested under it.
+// Removing ["security"] also removes ["security", "auth"].
+// Returns true if any tag was removed.
+func (b *Bookmark) RemoveTag(path []string) bool {
+	if len(path) == 0 {
+		return false
+	}
+
+	kept := b.Tags[:0:0]
+	for _, tag := range b.Tags {
+		if !hasPathPrefix(tag, path) {
+			kept = append(kept, tag)
+		}
+	}
+
+	removed := len(kept) != len(b.Tags)
+	if removed {
+		b.Tags = kept
+	}
+	return removed
+}
+
+// RenameTag replaces the tag hierarchy from, and the start of hierarchies
+// nested under it, with to. Renaming ["js"] to ["lang", "javascript"] turns
+// ["js", "react"] into ["lang", "javascript", "react"]. A renamed tag the
+// bookmark already has is dropped. Returns true if any tag was renamed.
+func (b *Bookmark) RenameTag(from, to []string) bool {
+	if len(from) == 0 || len(to) == 0 || equalPath(from, to) {
+		return false
+	}
+
+	renamed := false
+	tags := b.Tags[:0:0]
+	for _, tag := range b.Tags {
+		if hasPathPrefix(tag, from) {
+			tag = append(append([]string(nil), to...), tag[len(from):]...)
+			renamed = true
+		}
+		if !containsPath(tags, tag) {
+			tags = append(tags, tag)
+		}
+	}
+
+	if renamed {
+		b.Tags = tags
+	}
+	return renamed
+}
+
+// hasPathPrefix reports whether path starts with every element of prefix
+func hasPathPrefix(path, prefix []string) bool {
+	return len(path) >= len(prefix) && equalPath(path[:len(prefix)], prefix)
+}
+
+// equalPath reports whether two tag or folder paths are identical
+func equalPath(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// ParseTags parses a comma-separated tag list where "/" separates hierarchy levels.
+// Each level is normalized and empty levels are dropped.
+// Example: "Security/User Auth, DevOps" → [["security", "user-auth"], ["dev-ops"]]
+func ParseTags(s string) [][]string {
+	var tags [][]string
+	for _, group := range strings.Split(s, ",") {
+		var path []string
+		for _, level := range strings.Split(group, "/") {
+			if tag := NormalizeTag(level); tag != "" {
+				path = append(path, tag)
+			}
+		}
+		if len(path) > 0 && !containsPath(tags, path) {
+			tags = append(tags, path)
+		}
+	}
+	return tags
+}
+
+// FormatTags renders tag hierarchies in the format accepted by ParseTags
+func FormatTags(tags [][]string) string {
+	parts := make([]string, len(tags))
+	for i, path := range tags {
+		parts[i] = strings.Join(path, "/")
+	}
+	return strings.Join(parts, ", ")
+}
+
+// containsPath reports whether paths contains an identical path
+func containsPath(paths [][]string, path []string) bool {
+	for _, p := range paths {
+		if equalPath(p, path) {
+			return true
+		}
+	}
+	return false
+}
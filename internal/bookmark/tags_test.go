@@ -115,3 +115,145 @@ func TestNormalizeTags_Nil(t *testing.T) {
 		t.Errorf("Tags should remain nil")
 	}
 }
+
+func TestBookmark_AddTag(t *testing.T) {
+	b := &Bookmark{Tags: [][]string{{"security", "auth"}}}
+
+	if b.AddTag([]string{"security", "auth"}) {
+		t.Error("AddTag() should not add an existing hierarchy")
+	}
+	if !b.AddTag([]string{"security"}) {
+		t.Error("AddTag() should add a new hierarchy")
+	}
+	if b.AddTag(nil) {
+		t.Error("AddTag() should ignore empty paths")
+	}
+
+	if len(b.Tags) != 2 {
+		t.Errorf("len(Tags) = %v, want 2", len(b.Tags))
+	}
+	if !b.HasTag([]string{"security"}) {
+		t.Error("HasTag() should find the added tag")
+	}
+}
+
+func TestBookmark_HasTagUnder(t *testing.T) {
+	b := &Bookmark{Tags: [][]string{{"security", "auth"}, {"go"}}}
+
+	tests := []struct {
+		path []string
+		want bool
+	}{
+		{[]string{"security"}, true},
+		{[]string{"security", "auth"}, true},
+		{[]string{"security", "auth", "oauth"}, false},
+		{[]string{"go"}, true},
+		{[]string{"sec"}, false},
+	}
+	for _, tt := range tests {
+		if got := b.HasTagUnder(tt.path); got != tt.want {
+			t.Errorf("HasTagUnder(%v) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestParseTags(t *testing.T) {
+	got := ParseTags("Security/User Auth, DevOps ,, security/user-auth, /reading/")
+	want := [][]string{{"security", "user-auth"}, {"dev-ops"}, {"reading"}}
+
+	if len(got) != len(want) {
+		t.Fatalf("ParseTags() = %v, want %v", got, want)
+	}
+	for i := range want {
+		if !equalPath(got[i], want[i]) {
+			t.Errorf("ParseTags()[%d] = %v, want %v", i, got[i], want[i])
+		}
+	}
+
+	if tags := ParseTags("  "); tags != nil {
+		t.Errorf("ParseTags(blank) = %v, want nil", tags)
+	}
+}
+
+func TestFormatTags(t *testing.T) {
+	tags := [][]string{{"security", "auth"}, {"reading"}}
+
+	got := FormatTags(tags)
+	if got != "security/auth, reading" {
+		t.Errorf("FormatTags() = %q, want %q", got, "security/auth, reading")
+	}
+
+	// Round trip
+	parsed := ParseTags(got)
+	if len(parsed) != 2 || !equalPath(parsed[0], tags[0]) || !equalPath(parsed[1], tags[1]) {
+		t.Errorf("ParseTags(FormatTags()) = %v, want %v", parsed, tags)
+	}
+}
+
+func TestBookmark_RemoveTag(t *testing.T) {
+	b := &Bookmark{Tags: [][]string{{"security", "auth"}, {"security"}, {"reading"}, {"securityish"}}}
+
+	if !b.RemoveTag([]string{"security"}) {
+		t.Fatal("RemoveTag() = false, want true")
+	}
+
+	want := [][]string{{"reading"}, {"securityish"}}
+	if len(b.Tags) != len(want) {
+		t.Fatalf("Tags = %v, want %v", b.Tags, want)
+	}
+	for i := range want {
+		if !equalPath(b.Tags[i], want[i]) {
+			t.Errorf("Tags[%d] = %v, want %v", i, b.Tags[i], want[i])
+		}
+	}
+
+	if b.RemoveTag([]string{"missing"}) {
+		t.Error("RemoveTag() of missing tag should return false")
+	}
+}
+
+func TestBookmark_RenameTag(t *testing.T) {
+	tests := []struct {
+		name     string
+		tags     [][]string
+		from, to []string
+		want     [][]string
+		renamed  bool
+	}{
+		{
+			name:    "nested tags follow",
+			tags:    [][]string{{"js", "react"}, {"js"}, {"jsx"}},
+			from:    []string{"js"},
+			to:      []string{"lang", "javascript"},
+			want:    [][]string{{"lang", "javascript", "react"}, {"lang", "javascript"}, {"jsx"}},
+			renamed: true,
+		},
+		{
+			name:    "merges into an existing tag",
+			tags:    [][]string{{"golang"}, {"go"}},
+			from:    []string{"golang"},
+			to:      []string{"go"},
+			want:    [][]string{{"go"}},
+			renamed: true,
+		},
+		{
+			name: "missing tag",
+			tags: [][]string{{"go"}},
+			from: []string{"rust"},
+			to:   []string{"lang", "rust"},
+			want: [][]string{{"go"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := &Bookmark{Tags: tt.tags}
+			if got := b.RenameTag(tt.from, tt.to); got != tt.renamed {
+				t.Errorf("RenameTag() = %v, want %v", got, tt.renamed)
+			}
+			if FormatTags(b.Tags) != FormatTags(tt.want) {
+				t.Errorf("Tags = %v, want %v", b.Tags, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,207 @@
+package bookmark
+
+import (
+	"slices"
+	"strings"
+)
+
+// FolderNode is a folder in the hierarchy built by FolderTree
+type FolderNode struct {
+	Name     string
+	Path     []string
+	Count    int // Bookmarks directly in this folder
+	Total    int // Bookmarks in this folder and all its subfolders
+	Children []*FolderNode
+}
+
+// FolderTree builds the folder hierarchy of bookmarks from their folder paths.
+// The root node has no name or path; its Count is the number of bookmarks
+// without a folder and its Total the number of bookmarks. Children are
+// sorted by name, ignoring case.
+func FolderTree(bookmarks []*Bookmark) *FolderNode {
+	root := &FolderNode{}
+	for _, b := range bookmarks {
+		node := root
+		node.Total++
+		for _, segment := range b.Folder {
+			node = node.child(segment)
+			node.Total++
+		}
+		node.Count++
+	}
+	root.sortChildren()
+	return root
+}
+
+// child returns the direct child named name, adding it when missing
+func (n *FolderNode) child(name string) *FolderNode {
+	for _, c := range n.Children {
+		if c.Name == name {
+			return c
+		}
+	}
+	path := make([]string, len(n.Path), len(n.Path)+1)
+	copy(path, n.Path)
+	c := &FolderNode{Name: name, Path: append(path, name)}
+	n.Children = append(n.Children, c)
+	return c
+}
+
+// sortChildren orders the subtree's children by name
+func (n *FolderNode) sortChildren() {
+	slices.SortFunc(n.Children, func(a, b *FolderNode) int {
+		return compareNames(a.Name, b.Name)
+	})
+	for _, c := range n.Children {
+		c.sortChildren()
+	}
+}
+
+// Find returns the node at path below n, or nil when there is none
+func (n *FolderNode) Find(path []string) *FolderNode {
+	node := n
+	for _, segment := range path {
+		var next *FolderNode
+		for _, c := range node.Children {
+			if c.Name == segment {
+				next = c
+				break
+			}
+		}
+		if next == nil {
+			return nil
+		}
+		node = next
+	}
+	return node
+}
+
+// InFolder reports whether b is in the folder at path or one of its
+// subfolders. Every bookmark is in the empty path.
+func (b *Bookmark) InFolder(path []string) bool {
+	return hasPathPrefix(b.Folder, path)
+}
+
+// TagNode is a tag in the hierarchy built by Collection.TagTree
+type TagNode struct {
+	Name     string
+	Path     []string
+	Count    int  // Bookmarks tagged with exactly this path
+	Total    int  // Bookmarks tagged with this path or one nested under it
+	Orphan   bool // Top-level tag whose name also appears nested under another tag
+	Children []*TagNode
+}
+
+// TagTree builds the tag hierarchy of the collection. The root node has no
+// name or path and its Total is the number of tagged bookmarks. A bookmark
+// is counted once per node even when several of its tags fall under it.
+// Children are sorted by name, ignoring case.
+func (c *Collection) TagTree() *TagNode {
+	root := &TagNode{}
+	seen := map[*TagNode]int{} // Last bookmark counted in each node's Total
+
+	for i, b := range c.Bookmarks {
+		for _, tag := range b.Tags {
+			if len(tag) == 0 {
+				continue
+			}
+			node := root
+			if seen[node] != i+1 {
+				seen[node] = i + 1
+				node.Total++
+			}
+			for _, segment := range tag {
+				node = node.child(segment)
+				if seen[node] != i+1 {
+					seen[node] = i + 1
+					node.Total++
+				}
+			}
+			node.Count++
+		}
+	}
+
+	nested := map[string]bool{}
+	root.Walk(func(n *TagNode, depth int) {
+		if depth > 1 {
+			nested[n.Name] = true
+		}
+	})
+	for _, n := range root.Children {
+		n.Orphan = nested[n.Name]
+	}
+
+	root.sortChildren()
+	return root
+}
+
+// child returns the direct child named name, adding it when missing
+func (n *TagNode) child(name string) *TagNode {
+	for _, c := range n.Children {
+		if c.Name == name {
+			return c
+		}
+	}
+	path := make([]string, len(n.Path), len(n.Path)+1)
+	copy(path, n.Path)
+	c := &TagNode{Name: name, Path: append(path, name)}
+	n.Children = append(n.Children, c)
+	return c
+}
+
+// sortChildren orders the subtree's children by name
+func (n *TagNode) sortChildren() {
+	slices.SortFunc(n.Children, func(a, b *TagNode) int {
+		return compareNames(a.Name, b.Name)
+	})
+	for _, c := range n.Children {
+		c.sortChildren()
+	}
+}
+
+// Find returns the node at path below n, or nil when there is none
+func (n *TagNode) Find(path []string) *TagNode {
+	node := n
+	for _, segment := range path {
+		var next *TagNode
+		for _, c := range node.Children {
+			if c.Name == segment {
+				next = c
+				break
+			}
+		}
+		if next == nil {
+			return nil
+		}
+		node = next
+	}
+	return node
+}
+
+// Walk calls fn for n and every node below it in depth-first order, with
+// the depth below n
+func (n *TagNode) Walk(fn func(node *TagNode, depth int)) {
+	var walk func(node *TagNode, depth int)
+	walk = func(node *TagNode, depth int) {
+		fn(node, depth)
+		for _, c := range node.Children {
+			walk(c, depth+1)
+		}
+	}
+	walk(n, 0)
+}
+
+// SingleUse reports whether only one bookmark carries the tag or one nested
+// under it
+func (n *TagNode) SingleUse() bool {
+	return n.Total == 1
+}
+
+// compareNames orders folder and tag names ignoring case, falling back to
+// byte order so the result is deterministic
+func compareNames(a, b string) int {
+	if c := strings.Compare(strings.ToLower(a), strings.ToLower(b)); c != 0 {
+		return c
+	}
+	return strings.Compare(a, b)
+}
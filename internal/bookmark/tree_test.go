@@ -0,0 +1,122 @@
+package bookmark
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestFolderTree(t *testing.T) {
+	bookmarks := []*Bookmark{
+		{Folder: []string{"Research", "Go"}},
+		{Folder: []string{"Research", "Go"}},
+		{Folder: []string{"Research"}},
+		{Folder: []string{"research"}},
+		{Folder: []string{"Bookmarks Bar"}},
+		{},
+	}
+	root := FolderTree(bookmarks)
+
+	if root.Total != 6 || root.Count != 1 {
+		t.Errorf("root Total, Count = %v, %v, want 6, 1", root.Total, root.Count)
+	}
+
+	names := []string{}
+	for _, c := range root.Children {
+		names = append(names, c.Name)
+	}
+	if !slices.Equal(names, []string{"Bookmarks Bar", "Research", "research"}) {
+		t.Errorf("root children = %q, want sorted by name ignoring case", names)
+	}
+
+	research := root.Find([]string{"Research"})
+	if research == nil {
+		t.Fatal("Find(Research) = nil")
+	}
+	if research.Total != 3 || research.Count != 1 {
+		t.Errorf("Research Total, Count = %v, %v, want 3, 1", research.Total, research.Count)
+	}
+
+	golang := root.Find([]string{"Research", "Go"})
+	if golang == nil || golang.Count != 2 || !slices.Equal(golang.Path, []string{"Research", "Go"}) {
+		t.Errorf("Find(Research/Go) = %+v, want the Go folder with 2 bookmarks", golang)
+	}
+	if root.Find([]string{"Research", "Rust"}) != nil {
+		t.Error("Find() of a missing folder should return nil")
+	}
+	if root.Find(nil) != root {
+		t.Error("Find(nil) should return the root")
+	}
+}
+
+func TestBookmark_InFolder(t *testing.T) {
+	b := &Bookmark{Folder: []string{"Research", "Go"}}
+
+	tests := []struct {
+		path []string
+		want bool
+	}{
+		{nil, true},
+		{[]string{"Research"}, true},
+		{[]string{"Research", "Go"}, true},
+		{[]string{"Research", "Go", "Deep"}, false},
+		{[]string{"research"}, false},
+		{[]string{"Go"}, false},
+	}
+
+	for _, tt := range tests {
+		if got := b.InFolder(tt.path); got != tt.want {
+			t.Errorf("InFolder(%q) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestCollection_TagTree(t *testing.T) {
+	c := NewCollection()
+	c.Add(&Bookmark{URL: "https://a.example", Tags: [][]string{{"security", "auth"}, {"security", "crypto"}}})
+	c.Add(&Bookmark{URL: "https://b.example", Tags: [][]string{{"security"}, {"go"}}})
+	c.Add(&Bookmark{URL: "https://c.example", Tags: [][]string{{"auth"}}})
+	c.Add(&Bookmark{URL: "https://d.example"})
+	root := c.TagTree()
+
+	if root.Total != 3 {
+		t.Errorf("root.Total = %v, want 3 tagged bookmarks", root.Total)
+	}
+
+	names := []string{}
+	for _, n := range root.Children {
+		names = append(names, n.Name)
+	}
+	if !slices.Equal(names, []string{"auth", "go", "security"}) {
+		t.Errorf("root children = %q, want [auth go security]", names)
+	}
+
+	security := root.Find([]string{"security"})
+	if security == nil {
+		t.Fatal("Find(security) = nil")
+	}
+	// Two tags of the first bookmark fall under security but it counts once
+	if security.Total != 2 || security.Count != 1 {
+		t.Errorf("security Total, Count = %v, %v, want 2, 1", security.Total, security.Count)
+	}
+
+	auth := root.Find([]string{"security", "auth"})
+	if auth == nil || auth.Count != 1 || !auth.SingleUse() || auth.Orphan {
+		t.Errorf("Find(security/auth) = %+v, want a single-use nested tag", auth)
+	}
+
+	if top := root.Find([]string{"auth"}); top == nil || !top.Orphan {
+		t.Error("top-level auth should be an orphan of security/auth")
+	}
+	if root.Find([]string{"go"}).Orphan || security.Orphan {
+		t.Error("go and security should not be orphans")
+	}
+
+	depths := map[string]int{}
+	root.Walk(func(n *TagNode, depth int) {
+		depths[strings.Join(n.Path, "/")] = depth
+	})
+	if len(depths) != 6 || depths["security/crypto"] != 2 {
+		t.Errorf("Walk() depths = %v, want 6 nodes with security/crypto at 2", depths)
+	}
+}
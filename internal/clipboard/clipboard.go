@@ -0,0 +1,67 @@
+// Package clipboard copies text to the system clipboard, falling back to the
+// terminal's OSC52 escape sequence over SSH or when no clipboard tool exists.
+package clipboard
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/atotto/clipboard"
+	"github.com/aymanbagabas/go-osc52/v2"
+)
+
+// Method is how text reached the clipboard
+type Method string
+
+const (
+	System   Method = "system clipboard"
+	Terminal Method = "terminal (OSC52)"
+)
+
+// copier holds the environment a copy depends on
+type copier struct {
+	getenv      func(string) string
+	system      func(string) error
+	unsupported bool // No system clipboard tool was found
+	terminal    io.Writer
+}
+
+// Write copies text to the clipboard and reports how. Over SSH the system
+// clipboard belongs to the remote machine, so the terminal is asked to copy
+// instead.
+func Write(text string) (Method, error) {
+	c := copier{
+		getenv:      os.Getenv,
+		system:      clipboard.WriteAll,
+		unsupported: clipboard.Unsupported,
+		terminal:    os.Stderr,
+	}
+	return c.write(text)
+}
+
+func (c copier) write(text string) (Method, error) {
+	if !c.unsupported && !c.remote() {
+		if err := c.system(text); err == nil {
+			return System, nil
+		}
+	}
+
+	seq := osc52.New(text)
+	switch {
+	case c.getenv("TMUX") != "":
+		seq = seq.Tmux()
+	case strings.HasPrefix(c.getenv("TERM"), "screen"):
+		seq = seq.Screen()
+	}
+	if _, err := seq.WriteTo(c.terminal); err != nil {
+		return "", fmt.Errorf("failed to copy to clipboard: %w", err)
+	}
+	return Terminal, nil
+}
+
+// remote reports whether moxli runs in an SSH session
+func (c copier) remote() bool {
+	return c.getenv("SSH_TTY") != "" || c.getenv("SSH_CONNECTION") != ""
+}
@@ -0,0 +1,68 @@
+package clipboard
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestCopier_Write(t *testing.T) {
+	tests := []struct {
+		name        string
+		env         map[string]string
+		unsupported bool
+		systemErr   error
+		want        Method
+	}{
+		{name: "local", want: System},
+		{name: "ssh", env: map[string]string{"SSH_TTY": "/dev/pts/1"}, want: Terminal},
+		{name: "no clipboard tool", unsupported: true, want: Terminal},
+		{name: "clipboard tool fails", systemErr: errors.New("no display"), want: Terminal},
+	}
+
+	for _, tt := range tests {
+		var copied string
+		var out bytes.Buffer
+		c := copier{
+			getenv:      func(key string) string { return tt.env[key] },
+			system:      func(text string) error { copied = text; return tt.systemErr },
+			unsupported: tt.unsupported,
+			terminal:    &out,
+		}
+
+		got, err := c.write("https://example.com")
+		if err != nil {
+			t.Errorf("%s: write() error = %v", tt.name, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("%s: write() = %v, want %v", tt.name, got, tt.want)
+		}
+
+		encoded := base64.StdEncoding.EncodeToString([]byte("https://example.com"))
+		if tt.want == System && (copied != "https://example.com" || out.Len() != 0) {
+			t.Errorf("%s: expected only the system clipboard to be used", tt.name)
+		}
+		if tt.want == Terminal && !strings.Contains(out.String(), "\x1b]52;c;"+encoded) {
+			t.Errorf("%s: terminal output = %q, want an OSC52 sequence", tt.name, out.String())
+		}
+	}
+}
+
+func TestCopier_Write_Tmux(t *testing.T) {
+	var out bytes.Buffer
+	c := copier{
+		getenv:   func(key string) string { return map[string]string{"TMUX": "1", "SSH_TTY": "x"}[key] },
+		system:   func(string) error { return nil },
+		terminal: &out,
+	}
+
+	if _, err := c.write("x"); err != nil {
+		t.Fatalf("write() error = %v", err)
+	}
+	if !strings.HasPrefix(out.String(), "\x1bPtmux;") {
+		t.Errorf("terminal output = %q, want a tmux passthrough sequence", out.String())
+	}
+}
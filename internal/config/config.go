@@ -0,0 +1,87 @@
+// Package config loads user preferences from ~/.moxli/config.yaml.
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// FileName is the name of the config file inside moxli's config directory
+const FileName = "config.yaml"
+
+// Config holds user preferences. Every field is optional.
+type Config struct {
+	// Opener is the command used to open URLs, with {url} standing for the
+	// URL, e.g. "firefox --new-tab {url}". Empty uses $BROWSER or the
+	// platform default (open, xdg-open, ...).
+	Opener string `yaml:"opener"`
+
+	// Keys rebinds keys per scope ("global", "browser", "detail"), mapping
+	// action names to one or more keys, e.g. keys.browser.open: [o, enter].
+	// An empty list unbinds the action.
+	Keys map[string]map[string]KeyList `yaml:"keys"`
+
+	// Theme picks the colour palette
+	Theme Theme `yaml:"theme"`
+}
+
+// Theme selects a named palette and overrides some of its colours
+type Theme struct {
+	// Name is "dark", "light" or "high-contrast"; empty means dark
+	Name string `yaml:"name"`
+
+	// Colors overrides palette roles (title, accent, selected, muted,
+	// highlight, mark, snippet) with ANSI colour numbers or #rrggbb values
+	Colors map[string]string `yaml:"colors"`
+}
+
+// KeyList is the keys bound to an action. The config file may give a single
+// key or a list.
+type KeyList []string
+
+// UnmarshalYAML accepts a scalar as a one-key list
+func (k *KeyList) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		*k = KeyList{node.Value}
+		return nil
+	}
+	var keys []string
+	if err := node.Decode(&keys); err != nil {
+		return err
+	}
+	*k = keys
+	return nil
+}
+
+// Default returns the configuration used when no config file exists
+func Default() *Config {
+	return &Config{}
+}
+
+// Load reads the config file in dir. A missing file yields the defaults;
+// unknown keys are reported as errors so typos don't go unnoticed.
+func Load(dir string) (*Config, error) {
+	path := filepath.Join(dir, FileName)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return Default(), nil
+		}
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	cfg := Default()
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(cfg); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+
+	return cfg, nil
+}
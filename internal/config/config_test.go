@@ -0,0 +1,102 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, FileName), []byte("opener: firefox --new-tab {url}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := Load(dir)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.Opener != "firefox --new-tab {url}" {
+		t.Errorf("Opener = %q, want the configured template", cfg.Opener)
+	}
+}
+
+func TestLoad_KeysAndTheme(t *testing.T) {
+	dir := t.TempDir()
+	content := `keys:
+  global:
+    quit: ctrl+q
+  browser:
+    open: [o, enter]
+    tags: []
+theme:
+  name: light
+  colors:
+    title: "#ff5f87"
+`
+	if err := os.WriteFile(filepath.Join(dir, FileName), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := Load(dir)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	tests := []struct {
+		scope, action string
+		want          KeyList
+	}{
+		{"global", "quit", KeyList{"ctrl+q"}},
+		{"browser", "open", KeyList{"o", "enter"}},
+		{"browser", "tags", KeyList{}},
+	}
+	for _, tt := range tests {
+		if got := cfg.Keys[tt.scope][tt.action]; !slices.Equal(got, tt.want) || got == nil {
+			t.Errorf("Keys[%s][%s] = %#v, want %#v", tt.scope, tt.action, got, tt.want)
+		}
+	}
+	if cfg.Theme.Name != "light" || cfg.Theme.Colors["title"] != "#ff5f87" {
+		t.Errorf("Theme = %+v, want light with a title override", cfg.Theme)
+	}
+}
+
+func TestLoad_Missing(t *testing.T) {
+	cfg, err := Load(t.TempDir())
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.Opener != "" {
+		t.Errorf("Opener = %q, want the default", cfg.Opener)
+	}
+}
+
+func TestLoad_Invalid(t *testing.T) {
+	tests := map[string]string{
+		"unknown key": "opnr: firefox\n",
+		"bad yaml":    "opener: [\n",
+		"bad keys":    "keys:\n  browser:\n    open: {o: 1}\n",
+	}
+
+	for name, content := range tests {
+		dir := t.TempDir()
+		if err := os.WriteFile(filepath.Join(dir, FileName), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := Load(dir); err == nil || !strings.Contains(err.Error(), FileName) {
+			t.Errorf("%s: Load() error = %v, want a parse error naming the file", name, err)
+		}
+	}
+}
+
+func TestLoad_Empty(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, FileName), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(dir); err != nil {
+		t.Errorf("Load() of an empty file error = %v, want nil", err)
+	}
+}
@@ -0,0 +1,72 @@
+package enrich
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	"github.com/lelopez-io/moxli/internal/bookmark"
+	"github.com/lelopez-io/moxli/internal/fetch"
+)
+
+// Result is the metadata found for one bookmark, or why none was
+type Result struct {
+	Bookmark *bookmark.Bookmark
+	Metadata Metadata
+	Err      error
+}
+
+// Enricher fetches bookmarked pages on a bounded pool of workers
+type Enricher struct {
+	client  *fetch.Client
+	workers int
+}
+
+// New creates an Enricher fetching at most workers pages at once
+func New(client *fetch.Client, workers int) *Enricher {
+	return &Enricher{client: client, workers: max(workers, 1)}
+}
+
+// FetchAll fetches and parses the bookmarks' pages, calling progress (when
+// not nil) from a single goroutine after each URL. Bookmarks sharing a URL
+// are fetched once. Results follow the order of bookmarks; when ctx is
+// cancelled only the finished ones are returned. The bookmarks are not
+// modified.
+func (e *Enricher) FetchAll(ctx context.Context, bookmarks []*bookmark.Bookmark, progress func(done, total int)) []Result {
+	var urls []string
+	index := make(map[string]int)
+	for _, b := range bookmarks {
+		if _, seen := index[b.URL]; !seen {
+			index[b.URL] = len(urls)
+			urls = append(urls, b.URL)
+		}
+	}
+
+	found := make([]Result, len(urls))
+	finished := fetch.Parallel(ctx, e.workers, fetch.Sequence(len(urls)), func(i int) {
+		found[i].Metadata, found[i].Err = e.Fetch(ctx, urls[i])
+	}, progress)
+
+	results := make([]Result, 0, len(bookmarks))
+	for _, b := range bookmarks {
+		if i := index[b.URL]; finished[i] {
+			r := found[i]
+			r.Bookmark = b
+			results = append(results, r)
+		}
+	}
+	return results
+}
+
+// Fetch returns the metadata of the page at rawURL
+func (e *Enricher) Fetch(ctx context.Context, rawURL string) (Metadata, error) {
+	// A page that failed to cache is still worth parsing
+	page, err := e.client.Get(ctx, rawURL)
+	if page == nil {
+		return Metadata{}, err
+	}
+	if !page.IsHTML() {
+		return Metadata{}, fmt.Errorf("%s: not an HTML page (%s)", rawURL, page.ContentType)
+	}
+	return Parse(bytes.NewReader(page.Body), page.FinalURL)
+}
@@ -0,0 +1,88 @@
+package enrich
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/lelopez-io/moxli/internal/bookmark"
+	"github.com/lelopez-io/moxli/internal/fetch"
+)
+
+func TestEnricher_FetchAll(t *testing.T) {
+	var hits atomic.Int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("/article", func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(`<head><title>Article</title><link rel="canonical" href="/a"></head>`))
+	})
+	mux.HandleFunc("/image.png", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "image/png")
+		w.Write([]byte{0x89, 'P', 'N', 'G'})
+	})
+	mux.HandleFunc("/missing", http.NotFound)
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	bookmarks := []*bookmark.Bookmark{
+		{ID: "article", URL: server.URL + "/article"},
+		{ID: "image", URL: server.URL + "/image.png"},
+		{ID: "missing", URL: server.URL + "/missing"},
+		{ID: "copy", URL: server.URL + "/article"},
+	}
+
+	client := fetch.New(fetch.Options{Timeout: time.Second, Cache: fetch.NewCache(t.TempDir(), 0)})
+	calls := 0
+	results := New(client, 2).FetchAll(context.Background(), bookmarks, func(done, total int) {
+		calls++
+		if total != 3 {
+			t.Errorf("progress total = %v, want 3 unique URLs", total)
+		}
+	})
+
+	if len(results) != 4 || calls != 3 {
+		t.Fatalf("len(results) = %v with %v progress calls, want 4 and 3", len(results), calls)
+	}
+	for i, r := range results {
+		if r.Bookmark != bookmarks[i] {
+			t.Errorf("results[%d] is bookmark %s, want %s", i, r.Bookmark.ID, bookmarks[i].ID)
+		}
+	}
+
+	want := Metadata{Title: "Article", Canonical: server.URL + "/a"}
+	if results[0].Err != nil || results[0].Metadata != want || results[3].Metadata != want {
+		t.Errorf("article metadata = %+v (error %v), want %+v for both copies", results[0].Metadata, results[0].Err, want)
+	}
+	if results[1].Err == nil {
+		t.Error("a non-HTML page should be an error")
+	}
+	if results[2].Err == nil {
+		t.Error("a missing page should be an error")
+	}
+	if hits.Load() != 1 {
+		t.Errorf("/article fetched %v times, want once", hits.Load())
+	}
+	if bookmarks[0].Title != "" {
+		t.Error("FetchAll should not modify the bookmarks")
+	}
+
+	// A second run is served from the cache
+	New(client, 2).FetchAll(context.Background(), bookmarks[:1], nil)
+	if hits.Load() != 1 {
+		t.Errorf("/article fetched %v times after a cached run, want once", hits.Load())
+	}
+}
+
+func TestEnricher_FetchAll_Cancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	bookmarks := []*bookmark.Bookmark{{URL: "http://127.0.0.1:1/a"}, {URL: "http://127.0.0.1:1/b"}}
+	if results := New(fetch.New(fetch.Options{}), 2).FetchAll(ctx, bookmarks, nil); len(results) != 0 {
+		t.Errorf("FetchAll() after cancel = %v results, want none", len(results))
+	}
+}
@@ -0,0 +1,221 @@
+// Package enrich fills in missing bookmark metadata from the bookmarked
+// pages: titles, descriptions, canonical links and favicons. Only empty
+// fields are filled, so nothing a user wrote is replaced.
+package enrich
+
+import (
+	"io"
+	"net/url"
+	"strings"
+
+	"golang.org/x/net/html"
+
+	"github.com/lelopez-io/moxli/internal/bookmark"
+)
+
+// Metadata is what a page says about itself in its <head>
+type Metadata struct {
+	Title       string
+	Description string
+	Canonical   string // Absolute URL of the canonical link
+	Favicon     string // Absolute URL of the declared icon
+}
+
+// Parse extracts metadata from the <head> of an HTML page. <title> is
+// preferred over og:title and the meta description over og:description.
+// Relative links are resolved against pageURL, or the document's <base>.
+func Parse(r io.Reader, pageURL string) (Metadata, error) {
+	doc, err := html.Parse(r)
+	if err != nil {
+		return Metadata{}, err
+	}
+	base, err := url.Parse(pageURL)
+	if err != nil {
+		return Metadata{}, err
+	}
+
+	var p headParser
+	if head := findElement(doc, "head"); head != nil {
+		p.walk(head)
+	}
+	if p.base != "" {
+		if u, err := base.Parse(p.base); err == nil {
+			base = u
+		}
+	}
+
+	m := Metadata{
+		Title:       firstNonEmpty(p.title, p.ogTitle),
+		Description: firstNonEmpty(p.description, p.ogDescription),
+		Canonical:   resolve(base, p.canonical),
+		Favicon:     resolve(base, firstNonEmpty(p.icon, p.touchIcon)),
+	}
+	return m, nil
+}
+
+// headParser collects the candidate values found in <head>
+type headParser struct {
+	title, ogTitle             string
+	description, ogDescription string
+	canonical, base            string
+	icon, touchIcon            string
+}
+
+func (p *headParser) walk(n *html.Node) {
+	if n.Type == html.ElementNode {
+		switch n.Data {
+		case "title":
+			if p.title == "" {
+				p.title = collapseSpace(textContent(n))
+			}
+		case "meta":
+			p.meta(n)
+		case "link":
+			p.link(n)
+		case "base":
+			if p.base == "" {
+				p.base = attr(n, "href")
+			}
+		}
+	}
+
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		p.walk(c)
+	}
+}
+
+// meta records description and Open Graph tags. Open Graph uses property=,
+// though many sites put it in name=.
+func (p *headParser) meta(n *html.Node) {
+	content := collapseSpace(attr(n, "content"))
+	if content == "" {
+		return
+	}
+
+	key := strings.ToLower(attr(n, "property"))
+	if key == "" {
+		key = strings.ToLower(attr(n, "name"))
+	}
+	switch key {
+	case "description":
+		setOnce(&p.description, content)
+	case "og:title":
+		setOnce(&p.ogTitle, content)
+	case "og:description":
+		setOnce(&p.ogDescription, content)
+	}
+}
+
+// link records canonical and icon links. rel holds space-separated tokens,
+// as in "shortcut icon".
+func (p *headParser) link(n *html.Node) {
+	href := strings.TrimSpace(attr(n, "href"))
+	if href == "" {
+		return
+	}
+
+	for _, rel := range strings.Fields(strings.ToLower(attr(n, "rel"))) {
+		switch rel {
+		case "canonical":
+			setOnce(&p.canonical, href)
+		case "icon":
+			setOnce(&p.icon, href)
+		case "apple-touch-icon":
+			setOnce(&p.touchIcon, href)
+		}
+	}
+}
+
+// Missing reports whether the bookmark has an empty field Apply can fill
+func Missing(b *bookmark.Bookmark) bool {
+	return b.Title == "" || b.Description == "" || b.Canonical == "" || b.Favicon == ""
+}
+
+// Change is a field filled in on a bookmark
+type Change struct {
+	Field bookmark.Field
+	Value string
+}
+
+// Apply fills the bookmark's empty fields from m and returns the changes
+func Apply(b *bookmark.Bookmark, m Metadata) []Change {
+	var changes []Change
+	fill := func(field bookmark.Field, target *string, value string) {
+		if *target == "" && value != "" {
+			*target = value
+			changes = append(changes, Change{Field: field, Value: value})
+		}
+	}
+
+	fill(bookmark.FieldTitle, &b.Title, m.Title)
+	fill(bookmark.FieldDescription, &b.Description, m.Description)
+	fill(bookmark.FieldCanonical, &b.Canonical, m.Canonical)
+	fill(bookmark.FieldFavicon, &b.Favicon, m.Favicon)
+	return changes
+}
+
+// findElement returns the first element named tag in depth-first order
+func findElement(n *html.Node, tag string) *html.Node {
+	if n.Type == html.ElementNode && n.Data == tag {
+		return n
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if found := findElement(c, tag); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+// textContent concatenates the text below a node
+func textContent(n *html.Node) string {
+	if n.Type == html.TextNode {
+		return n.Data
+	}
+	var s strings.Builder
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		s.WriteString(textContent(c))
+	}
+	return s.String()
+}
+
+// attr returns the value of an element's attribute, or "" when it has none
+func attr(n *html.Node, key string) string {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return a.Val
+		}
+	}
+	return ""
+}
+
+// resolve makes href absolute against base. Only http(s) results are kept.
+func resolve(base *url.URL, href string) string {
+	if href == "" {
+		return ""
+	}
+	u, err := base.Parse(href)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return ""
+	}
+	return u.String()
+}
+
+func collapseSpace(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+func setOnce(target *string, value string) {
+	if *target == "" {
+		*target = value
+	}
+}
@@ -0,0 +1,97 @@
+package enrich
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/lelopez-io/moxli/internal/bookmark"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name string
+		html string
+		want Metadata
+	}{
+		{
+			name: "title and description",
+			html: `<html><head>
+				<title>
+					Example   Page
+				</title>
+				<meta name="description" content="An example page">
+				<meta property="og:title" content="OG Title">
+				<meta property="og:description" content="OG description">
+			</head><body></body></html>`,
+			want: Metadata{Title: "Example Page", Description: "An example page"},
+		},
+		{
+			name: "open graph fallback",
+			html: `<head><meta name="og:title" content="OG Title"><meta property="og:description" content="OG &amp; more"></head>`,
+			want: Metadata{Title: "OG Title", Description: "OG & more"},
+		},
+		{
+			name: "links resolved against the page",
+			html: `<head><link rel="canonical" href="/post"><link rel="Shortcut Icon" href="img/fav.ico"></head>`,
+			want: Metadata{Canonical: "https://example.com/post", Favicon: "https://example.com/blog/img/fav.ico"},
+		},
+		{
+			name: "links resolved against base",
+			html: `<head><base href="https://cdn.example.net/"><link rel="apple-touch-icon" href="touch.png"></head>`,
+			want: Metadata{Favicon: "https://cdn.example.net/touch.png"},
+		},
+		{
+			name: "icon preferred over touch icon",
+			html: `<head><link rel="apple-touch-icon" href="/touch.png"><link rel="icon" href="/icon.png"></head>`,
+			want: Metadata{Favicon: "https://example.com/icon.png"},
+		},
+		{
+			name: "inline icon skipped",
+			html: `<head><link rel="icon" href="data:image/png;base64,AAAA"></head>`,
+			want: Metadata{},
+		},
+		{
+			name: "body titles ignored",
+			html: `<html><body><svg><title>Chart</title></svg></body></html>`,
+			want: Metadata{},
+		},
+	}
+
+	for _, tt := range tests {
+		got, err := Parse(strings.NewReader(tt.html), "https://example.com/blog/index.html")
+		if err != nil {
+			t.Errorf("%s: Parse() error = %v", tt.name, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("%s: Parse() = %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestApply(t *testing.T) {
+	b := &bookmark.Bookmark{URL: "https://example.com", Title: "Mine"}
+	m := Metadata{Title: "Theirs", Description: "Desc", Favicon: "https://example.com/favicon.ico"}
+
+	changes := Apply(b, m)
+	if len(changes) != 2 || changes[0].Field != bookmark.FieldDescription || changes[1].Field != bookmark.FieldFavicon {
+		t.Errorf("Apply() changes = %+v, want description and favicon", changes)
+	}
+	if b.Title != "Mine" {
+		t.Errorf("Title = %q, want the existing title kept", b.Title)
+	}
+	if b.Description != "Desc" || b.Favicon != m.Favicon {
+		t.Errorf("Description, Favicon = %q, %q, want them filled", b.Description, b.Favicon)
+	}
+	if !Missing(b) {
+		t.Error("Missing() should be true while Canonical is empty")
+	}
+
+	b.Canonical = "https://example.com/"
+	if Missing(b) {
+		t.Error("Missing() should be false once every field is set")
+	}
+	if changes := Apply(b, m); len(changes) != 0 {
+		t.Errorf("Apply() on a complete bookmark = %+v, want no changes", changes)
+	}
+}
@@ -0,0 +1,292 @@
+package exporter
+
+import (
+	"encoding/xml"
+	"io"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/lelopez-io/moxli/internal/bookmark"
+)
+
+// FeedFormat selects the syntax FeedExporter writes
+type FeedFormat string
+
+const (
+	FeedAtom FeedFormat = "atom" // Atom 1.0
+	FeedRSS  FeedFormat = "rss"  // RSS 2.0
+)
+
+// DefaultFeedTitle names a feed when FeedExporter.Title is empty
+const DefaultFeedTitle = "Bookmarks"
+
+// FeedExporter writes bookmarks as an Atom or RSS feed, newest first. Each
+// entry links to the bookmarked page, is published when the bookmark was
+// added, carries the comment and description as content and the tags as
+// categories.
+type FeedExporter struct {
+	Format  FeedFormat // Feed syntax (empty uses FeedAtom)
+	Title   string     // Feed title (empty uses DefaultFeedTitle)
+	Link    string     // Page the feed belongs to, may be empty
+	FeedURL string     // Address the feed itself is published at, may be empty
+
+	Tag   []string  // Only bookmarks with this tag or one nested under it (nil for all)
+	Since time.Time // Only bookmarks added at or after this time (zero for no limit)
+	Until time.Time // Only bookmarks added before this time (zero for no limit)
+	Limit int       // Newest bookmarks included (0 for all)
+
+	// Permalink returns a page about the bookmark, such as its anchor on a
+	// published site, linked from its entry. Nil for none.
+	Permalink func(b *bookmark.Bookmark) string
+}
+
+// Export writes the collection's bookmarks as a feed
+func (f *FeedExporter) Export(w io.Writer, c *bookmark.Collection) error {
+	var doc any
+	if f.Format == FeedRSS {
+		doc = f.rss(f.Select(c.Bookmarks))
+	} else {
+		doc = f.atom(f.Select(c.Bookmarks))
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// Select returns the bookmarks in the feed: those matching the tag and date
+// filters, newest first, up to Limit
+func (f *FeedExporter) Select(bookmarks []*bookmark.Bookmark) []*bookmark.Bookmark {
+	var selected []*bookmark.Bookmark
+	for _, b := range bookmarks {
+		if f.Tag != nil && !b.HasTagUnder(f.Tag) {
+			continue
+		}
+		if !f.Since.IsZero() && b.DateAdded.Before(f.Since) {
+			continue
+		}
+		if !f.Until.IsZero() && !b.DateAdded.Before(f.Until) {
+			continue
+		}
+		selected = append(selected, b)
+	}
+
+	selected = bookmark.Sort(selected, bookmark.SortAddedDesc)
+	if f.Limit > 0 && len(selected) > f.Limit {
+		selected = selected[:f.Limit]
+	}
+	return selected
+}
+
+func (f *FeedExporter) title() string {
+	if f.Title == "" {
+		return DefaultFeedTitle
+	}
+	return f.Title
+}
+
+// atomFeed is an Atom 1.0 feed document
+type atomFeed struct {
+	XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	Title   string      `xml:"title"`
+	ID      string      `xml:"id"`
+	Updated string      `xml:"updated"`
+	Links   []atomLink  `xml:"link"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Rel  string `xml:"rel,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
+	Href string `xml:"href,attr"`
+}
+
+type atomEntry struct {
+	Title      string         `xml:"title"`
+	ID         string         `xml:"id"`
+	Links      []atomLink     `xml:"link"`
+	Published  string         `xml:"published,omitempty"`
+	Updated    string         `xml:"updated"`
+	Content    *atomText      `xml:"content"`
+	Categories []atomCategory `xml:"category"`
+}
+
+type atomText struct {
+	Type string `xml:"type,attr"`
+	Body string `xml:",chardata"`
+}
+
+type atomCategory struct {
+	Term string `xml:"term,attr"`
+}
+
+func (f *FeedExporter) atom(bookmarks []*bookmark.Bookmark) *atomFeed {
+	feed := &atomFeed{Title: f.title(), ID: f.Link}
+	if feed.ID == "" {
+		// Atom requires an ID; the title keeps it stable between exports
+		feed.ID = "urn:moxli:feed:" + url.PathEscape(feed.Title)
+	}
+	if f.FeedURL != "" {
+		feed.Links = append(feed.Links, atomLink{Rel: "self", Type: "application/atom+xml", Href: f.FeedURL})
+	}
+	if f.Link != "" {
+		feed.Links = append(feed.Links, atomLink{Rel: "alternate", Type: "text/html", Href: f.Link})
+	}
+
+	var updated time.Time
+	for _, b := range bookmarks {
+		changed := lastChanged(b)
+		if changed.After(updated) {
+			updated = changed
+		}
+
+		entry := atomEntry{
+			Title:   entryTitle(b),
+			ID:      entryID(b),
+			Links:   []atomLink{{Rel: "alternate", Href: b.URL}},
+			Updated: atomTime(changed),
+		}
+		if f.Permalink != nil {
+			entry.Links = append(entry.Links, atomLink{Rel: "related", Type: "text/html", Href: f.Permalink(b)})
+		}
+		if !b.DateAdded.IsZero() {
+			entry.Published = atomTime(b.DateAdded)
+		}
+		if text := entryText(b); text != "" {
+			entry.Content = &atomText{Type: "text", Body: text}
+		}
+		for _, tag := range b.Tags {
+			entry.Categories = append(entry.Categories, atomCategory{Term: strings.Join(tag, "/")})
+		}
+		feed.Entries = append(feed.Entries, entry)
+	}
+	feed.Updated = atomTime(updated)
+	return feed
+}
+
+// rssFeed is an RSS 2.0 document
+type rssFeed struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Description   string    `xml:"description"`
+	LastBuildDate string    `xml:"lastBuildDate,omitempty"`
+	Generator     string    `xml:"generator"`
+	Items         []rssItem `xml:"item"`
+}
+
+type rssItem struct {
+	Title       string   `xml:"title"`
+	Link        string   `xml:"link"`
+	GUID        rssGUID  `xml:"guid"`
+	PubDate     string   `xml:"pubDate,omitempty"`
+	Description string   `xml:"description,omitempty"`
+	Comments    string   `xml:"comments,omitempty"` // Permalink, the closest RSS has
+	Categories  []string `xml:"category"`
+}
+
+type rssGUID struct {
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+func (f *FeedExporter) rss(bookmarks []*bookmark.Bookmark) *rssFeed {
+	channel := rssChannel{
+		Title:       f.title(),
+		Link:        f.Link,
+		Description: f.title() + " from moxli",
+		Generator:   "moxli",
+	}
+
+	var updated time.Time
+	for _, b := range bookmarks {
+		if changed := lastChanged(b); changed.After(updated) {
+			updated = changed
+		}
+
+		item := rssItem{
+			Title:       entryTitle(b),
+			Link:        b.URL,
+			GUID:        rssGUID{Value: entryID(b)},
+			Description: entryText(b),
+		}
+		if f.Permalink != nil {
+			item.Comments = f.Permalink(b)
+		}
+		if !b.DateAdded.IsZero() {
+			item.PubDate = b.DateAdded.UTC().Format(time.RFC1123Z)
+		}
+		for _, tag := range b.Tags {
+			item.Categories = append(item.Categories, strings.Join(tag, "/"))
+		}
+		channel.Items = append(channel.Items, item)
+	}
+	if !updated.IsZero() {
+		channel.LastBuildDate = updated.UTC().Format(time.RFC1123Z)
+	}
+	return &rssFeed{Version: "2.0", Channel: channel}
+}
+
+// lastChanged returns when a bookmark was added or last modified, whichever
+// is later
+func lastChanged(b *bookmark.Bookmark) time.Time {
+	if b.LastModified.After(b.DateAdded) {
+		return b.LastModified
+	}
+	return b.DateAdded
+}
+
+func entryTitle(b *bookmark.Bookmark) string {
+	if b.Title != "" {
+		return b.Title
+	}
+	return b.URL
+}
+
+// entryID returns a permanent identifier for a bookmark's entry: its UUID,
+// its other ID, or failing that its URL
+func entryID(b *bookmark.Bookmark) string {
+	switch {
+	case b.ID == "":
+		return b.URL
+	case uuid.Validate(b.ID) == nil:
+		return "urn:uuid:" + b.ID
+	default:
+		return "urn:moxli:bookmark:" + url.PathEscape(b.ID)
+	}
+}
+
+// entryText is the content of an entry: the comment, then the description
+func entryText(b *bookmark.Bookmark) string {
+	var parts []string
+	for _, text := range []string{b.Comment, b.Description} {
+		if text = strings.TrimSpace(text); text != "" {
+			parts = append(parts, text)
+		}
+	}
+	return strings.Join(parts, "\n\n")
+}
+
+// atomTime formats a time as RFC 3339 in UTC, using the Unix epoch for a
+// missing time since Atom requires one
+func atomTime(t time.Time) string {
+	if t.IsZero() {
+		t = time.Unix(0, 0)
+	}
+	return t.UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,153 @@
+package exporter
+
+import (
+	"bytes"
+	"encoding/xml"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lelopez-io/moxli/internal/bookmark"
+)
+
+func feedCollection() *bookmark.Collection {
+	day := func(d int) time.Time { return time.Date(2025, 1, d, 0, 0, 0, 0, time.UTC) }
+	c := bookmark.NewCollection()
+	c.Add(&bookmark.Bookmark{ID: "9b2f0c1e-1d2a-4c59-9d8e-1f2a3b4c5d6e", URL: "https://go.dev/", Title: "Go",
+		Tags: [][]string{{"lang", "go"}}, Comment: "Start here", Description: "The Go site", DateAdded: day(1), LastModified: day(5)})
+	c.Add(&bookmark.Bookmark{ID: "rust", URL: "https://rust-lang.org/", Title: "Rust & friends",
+		Tags: [][]string{{"lang", "rust"}}, DateAdded: day(3)})
+	c.Add(&bookmark.Bookmark{URL: "https://example.com/news", DateAdded: day(2)})
+	return c
+}
+
+func TestFeedExporter_Select(t *testing.T) {
+	c := feedCollection()
+	tests := []struct {
+		name string
+		f    FeedExporter
+		want []string // URLs in order
+	}{
+		{"all, newest first", FeedExporter{}, []string{"https://rust-lang.org/", "https://example.com/news", "https://go.dev/"}},
+		{"tag", FeedExporter{Tag: []string{"lang"}}, []string{"https://rust-lang.org/", "https://go.dev/"}},
+		{"nested tag", FeedExporter{Tag: []string{"lang", "go"}}, []string{"https://go.dev/"}},
+		{"since", FeedExporter{Since: time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC)}, []string{"https://rust-lang.org/", "https://example.com/news"}},
+		{"until", FeedExporter{Until: time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC)}, []string{"https://go.dev/"}},
+		{"limit", FeedExporter{Limit: 1}, []string{"https://rust-lang.org/"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.f.Select(c.Bookmarks)
+			if len(got) != len(tt.want) {
+				t.Fatalf("Select() returned %v bookmarks, want %v", len(got), len(tt.want))
+			}
+			for i, b := range got {
+				if b.URL != tt.want[i] {
+					t.Errorf("Select()[%d] = %v, want %v", i, b.URL, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestFeedExporter_Atom(t *testing.T) {
+	f := &FeedExporter{
+		Title:     "Team links",
+		Link:      "https://links.example/",
+		FeedURL:   "https://links.example/feed.xml",
+		Permalink: func(b *bookmark.Bookmark) string { return "https://links.example/#" + b.ID },
+	}
+	var buf bytes.Buffer
+	if err := f.Export(&buf, feedCollection()); err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+	if !strings.HasPrefix(buf.String(), xml.Header) {
+		t.Errorf("feed doesn't start with an XML declaration")
+	}
+
+	var feed atomFeed
+	if err := xml.Unmarshal(buf.Bytes(), &feed); err != nil {
+		t.Fatalf("invalid Atom: %v", err)
+	}
+	if feed.Title != "Team links" || feed.ID != "https://links.example/" || len(feed.Links) != 2 {
+		t.Errorf("feed = %q %q %+v, want the title, link as ID, and self and alternate links", feed.Title, feed.ID, feed.Links)
+	}
+	if feed.Updated != "2025-01-05T00:00:00Z" {
+		t.Errorf("Updated = %v, want the latest change", feed.Updated)
+	}
+	if len(feed.Entries) != 3 {
+		t.Fatalf("len(Entries) = %v, want 3", len(feed.Entries))
+	}
+
+	untitled := feed.Entries[1]
+	if untitled.Title != "https://example.com/news" || untitled.ID != "https://example.com/news" || untitled.Content != nil {
+		t.Errorf("untitled entry = %+v, want the URL as title and ID and no content", untitled)
+	}
+
+	goEntry := feed.Entries[2]
+	if goEntry.ID != "urn:uuid:9b2f0c1e-1d2a-4c59-9d8e-1f2a3b4c5d6e" {
+		t.Errorf("ID = %v, want a urn:uuid", goEntry.ID)
+	}
+	if goEntry.Published != "2025-01-01T00:00:00Z" || goEntry.Updated != "2025-01-05T00:00:00Z" {
+		t.Errorf("Published, Updated = %v, %v, want the dates added and modified", goEntry.Published, goEntry.Updated)
+	}
+	if goEntry.Content == nil || goEntry.Content.Body != "Start here\n\nThe Go site" {
+		t.Errorf("Content = %+v, want the comment then the description", goEntry.Content)
+	}
+	if len(goEntry.Categories) != 1 || goEntry.Categories[0].Term != "lang/go" {
+		t.Errorf("Categories = %+v, want lang/go", goEntry.Categories)
+	}
+	if len(goEntry.Links) != 2 || goEntry.Links[0].Href != "https://go.dev/" || goEntry.Links[1].Rel != "related" {
+		t.Errorf("Links = %+v, want the page and the permalink", goEntry.Links)
+	}
+}
+
+func TestFeedExporter_Atom_Defaults(t *testing.T) {
+	var buf bytes.Buffer
+	if err := (&FeedExporter{}).Export(&buf, bookmark.NewCollection()); err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+	var feed atomFeed
+	if err := xml.Unmarshal(buf.Bytes(), &feed); err != nil {
+		t.Fatalf("invalid Atom: %v", err)
+	}
+	if feed.Title != DefaultFeedTitle || feed.ID != "urn:moxli:feed:Bookmarks" || feed.Updated != "1970-01-01T00:00:00Z" || len(feed.Links) != 0 {
+		t.Errorf("feed = %+v, want the default title, a URN ID and no links", feed)
+	}
+}
+
+func TestFeedExporter_RSS(t *testing.T) {
+	f := &FeedExporter{Format: FeedRSS, Title: "Team links", Link: "https://links.example/", Tag: []string{"lang"}}
+	var buf bytes.Buffer
+	if err := f.Export(&buf, feedCollection()); err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+
+	var feed rssFeed
+	if err := xml.Unmarshal(buf.Bytes(), &feed); err != nil {
+		t.Fatalf("invalid RSS: %v", err)
+	}
+	if feed.Version != "2.0" || feed.Channel.Title != "Team links" || feed.Channel.Link != "https://links.example/" {
+		t.Errorf("channel = %+v, want RSS 2.0 with the title and link", feed.Channel)
+	}
+	if feed.Channel.LastBuildDate != "Sun, 05 Jan 2025 00:00:00 +0000" {
+		t.Errorf("LastBuildDate = %v, want the latest change", feed.Channel.LastBuildDate)
+	}
+	if len(feed.Channel.Items) != 2 {
+		t.Fatalf("len(Items) = %v, want the 2 lang bookmarks", len(feed.Channel.Items))
+	}
+
+	rust := feed.Channel.Items[0]
+	if rust.Title != "Rust & friends" || rust.Link != "https://rust-lang.org/" || rust.PubDate != "Fri, 03 Jan 2025 00:00:00 +0000" {
+		t.Errorf("item = %+v", rust)
+	}
+	if rust.GUID.Value != "urn:moxli:bookmark:rust" || rust.GUID.IsPermaLink {
+		t.Errorf("GUID = %+v, want a non-permalink ID", rust.GUID)
+	}
+	if len(rust.Categories) != 1 || rust.Categories[0] != "lang/rust" {
+		t.Errorf("Categories = %v, want [lang/rust]", rust.Categories)
+	}
+	if !strings.Contains(buf.String(), "Rust &amp; friends") {
+		t.Errorf("title isn't escaped")
+	}
+}
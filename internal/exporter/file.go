@@ -0,0 +1,50 @@
+package exporter
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/lelopez-io/moxli/internal/bookmark"
+)
+
+// BackupSuffix is appended to a file's name to keep its previous version
+const BackupSuffix = ".bak"
+
+// WriteFile exports the collection to path, replacing any existing file.
+// The export is written to a temporary file first so a failed export never
+// truncates the original, and the previous version is kept as path + BackupSuffix.
+// Returns the backup path, or "" if there was no previous file.
+func WriteFile(path string, e Exporter, c *bookmark.Collection) (string, error) {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		return "", fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	tmpPath := tmp.Name()
+	defer os.Remove(tmpPath) // No-op once renamed into place
+
+	if err := e.Export(tmp, c); err != nil {
+		tmp.Close()
+		return "", fmt.Errorf("failed to export collection: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return "", fmt.Errorf("failed to write temporary file: %w", err)
+	}
+	if err := os.Chmod(tmpPath, 0644); err != nil {
+		return "", fmt.Errorf("failed to set file permissions: %w", err)
+	}
+
+	backup := ""
+	if _, err := os.Stat(path); err == nil {
+		backup = path + BackupSuffix
+		if err := os.Rename(path, backup); err != nil {
+			return "", fmt.Errorf("failed to back up %s: %w", path, err)
+		}
+	}
+
+	if err := os.Rename(tmpPath, path); err != nil {
+		return "", fmt.Errorf("failed to replace %s: %w", path, err)
+	}
+
+	return backup, nil
+}
@@ -0,0 +1,97 @@
+package exporter
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/lelopez-io/moxli/internal/bookmark"
+)
+
+func TestWriteFile_NewFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "bookmarks.json")
+
+	collection := bookmark.NewCollection()
+	collection.Add(&bookmark.Bookmark{URL: "https://example.com", Title: "Example"})
+
+	backup, err := WriteFile(path, &AnyboxExporter{}, collection)
+	if err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	if backup != "" {
+		t.Errorf("backup = %v, want empty for new file", backup)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+
+	var bookmarks []*bookmark.Bookmark
+	if err := json.Unmarshal(data, &bookmarks); err != nil {
+		t.Fatalf("Failed to parse written JSON: %v", err)
+	}
+	if len(bookmarks) != 1 {
+		t.Errorf("len(bookmarks) = %v, want 1", len(bookmarks))
+	}
+}
+
+func TestWriteFile_BacksUpExisting(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "bookmarks.json")
+	if err := os.WriteFile(path, []byte("previous"), 0644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	backup, err := WriteFile(path, &AnyboxExporter{}, bookmark.NewCollection())
+	if err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	if backup != path+BackupSuffix {
+		t.Errorf("backup = %v, want %v", backup, path+BackupSuffix)
+	}
+
+	data, err := os.ReadFile(backup)
+	if err != nil {
+		t.Fatalf("ReadFile(backup) error = %v", err)
+	}
+	if string(data) != "previous" {
+		t.Errorf("backup content = %q, want previous version", data)
+	}
+}
+
+// failingExporter always fails to export
+type failingExporter struct{}
+
+func (failingExporter) Export(w io.Writer, c *bookmark.Collection) error {
+	return errors.New("boom")
+}
+
+func TestWriteFile_FailureKeepsOriginal(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "bookmarks.json")
+	if err := os.WriteFile(path, []byte("previous"), 0644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	if _, err := WriteFile(path, failingExporter{}, bookmark.NewCollection()); err == nil {
+		t.Fatal("WriteFile() should return exporter error")
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	if string(data) != "previous" {
+		t.Errorf("content = %q, original should be untouched", data)
+	}
+
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 1 {
+		t.Errorf("len(entries) = %v, want 1 (temporary file should be cleaned up)", len(entries))
+	}
+}
@@ -0,0 +1,44 @@
+package exporter
+
+// Format describes an export format available to the CLI and TUI
+type Format struct {
+	Name      string          // Identifier, e.g. "anybox"
+	Label     string          // Human-readable name
+	Extension string          // File extension including the dot
+	New       func() Exporter // Creates an exporter with default settings
+}
+
+// Formats returns every registered export format.
+// The first format is the default.
+func Formats() []Format {
+	return []Format{
+		{
+			Name:      "anybox",
+			Label:     "Anybox JSON",
+			Extension: ".json",
+			New:       func() Exporter { return &AnyboxExporter{Indent: true} },
+		},
+		{
+			Name:      "atom",
+			Label:     "Atom feed",
+			Extension: ".xml",
+			New:       func() Exporter { return &FeedExporter{Format: FeedAtom} },
+		},
+		{
+			Name:      "rss",
+			Label:     "RSS 2.0 feed",
+			Extension: ".xml",
+			New:       func() Exporter { return &FeedExporter{Format: FeedRSS} },
+		},
+	}
+}
+
+// LookupFormat returns the format with the given name
+func LookupFormat(name string) (Format, bool) {
+	for _, f := range Formats() {
+		if f.Name == name {
+			return f, true
+		}
+	}
+	return Format{}, false
+}
@@ -0,0 +1,48 @@
+package exporter
+
+import "testing"
+
+func TestFormats(t *testing.T) {
+	formats := Formats()
+	if len(formats) == 0 {
+		t.Fatal("Formats() should not be empty")
+	}
+
+	if formats[0].Name != "anybox" {
+		t.Errorf("Formats()[0].Name = %v, want anybox (default)", formats[0].Name)
+	}
+
+	seen := make(map[string]bool)
+	for _, f := range formats {
+		if seen[f.Name] {
+			t.Errorf("duplicate format name %q", f.Name)
+		}
+		seen[f.Name] = true
+
+		if f.New() == nil {
+			t.Errorf("format %q New() returned nil", f.Name)
+		}
+		if f.Extension == "" || f.Extension[0] != '.' {
+			t.Errorf("format %q Extension = %q, want leading dot", f.Name, f.Extension)
+		}
+	}
+}
+
+func TestLookupFormat(t *testing.T) {
+	f, ok := LookupFormat("anybox")
+	if !ok {
+		t.Fatal("LookupFormat(anybox) should exist")
+	}
+	if _, isAnybox := f.New().(*AnyboxExporter); !isAnybox {
+		t.Errorf("anybox New() = %T, want *AnyboxExporter", f.New())
+	}
+
+	f, ok = LookupFormat("rss")
+	if feed, isFeed := f.New().(*FeedExporter); !ok || !isFeed || feed.Format != FeedRSS {
+		t.Errorf("rss New() = %#v, want an RSS *FeedExporter", f.New())
+	}
+
+	if _, ok := LookupFormat("unknown"); ok {
+		t.Error("LookupFormat(unknown) should not exist")
+	}
+}
@@ -0,0 +1,283 @@
+package extract
+
+import (
+	"regexp"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+var (
+	// unlikely matches classes and ids of page furniture, removed before
+	// scoring unless likely matches too
+	unlikely = regexp.MustCompile(`(?i)-ad-|ad-break|agegate|banner|breadcrumb|combx|comment|community|cookie|disqus|extra|footer|gdpr|header|legends|menu|newsletter|pager|pagination|popup|promo|related|remark|replies|rss|share|shoutbox|sidebar|skyscraper|social|sponsor|subscribe|supplemental|yom-remote`)
+	likely   = regexp.MustCompile(`(?i)and|article|body|column|content|main|shadow`)
+
+	// positive and negative weigh the classes and ids of scored blocks
+	positive = regexp.MustCompile(`(?i)article|blog|body|content|entry|h-entry|hentry|main|page|post|story|text`)
+	negative = regexp.MustCompile(`(?i)-ad-|banner|byline|combx|comment|com-|contact|foot|footer|footnote|hidden|masthead|media|meta|outbrain|promo|related|scroll|share|shoutbox|sidebar|skyscraper|sponsor|shopping|tags|tool|widget`)
+)
+
+// junk lists elements that never hold article text
+var junk = map[string]bool{
+	"script": true, "style": true, "noscript": true, "template": true, "link": true, "meta": true,
+	"nav": true, "aside": true, "footer": true, "form": true, "button": true, "input": true,
+	"select": true, "textarea": true, "iframe": true, "object": true, "embed": true,
+	"canvas": true, "svg": true, "dialog": true,
+}
+
+// paragraphs lists elements whose text is scored
+var paragraphs = map[string]bool{"p": true, "pre": true, "td": true, "blockquote": true}
+
+// blockElements lists elements that make a <div> a container rather than a
+// paragraph
+var blockElements = map[string]bool{
+	"address": true, "article": true, "aside": true, "blockquote": true, "dl": true, "div": true,
+	"figure": true, "footer": true, "form": true, "h1": true, "h2": true, "h3": true, "h4": true,
+	"h5": true, "h6": true, "header": true, "hr": true, "main": true, "ol": true, "p": true,
+	"pre": true, "section": true, "table": true, "ul": true,
+}
+
+const (
+	// minParagraphLength is the shortest text scored as a paragraph
+	minParagraphLength = 25
+	// minSiblingScore is the lowest score at which a sibling of the best
+	// block is kept with it
+	minSiblingScore = 10
+)
+
+// findContent returns the blocks holding the article: the best scoring
+// element and those of its siblings that look like part of it. The
+// document is cleaned of boilerplate on the way.
+func findContent(doc *html.Node) []*html.Node {
+	body := findElement(doc, "body")
+	if body == nil {
+		return nil
+	}
+	prune(body)
+
+	s := &scorer{scores: make(map[*html.Node]float64)}
+	s.walk(body)
+
+	var top *html.Node
+	for _, n := range s.order {
+		s.scores[n] *= 1 - linkDensity(n)
+		if top == nil || s.scores[n] > s.scores[top] {
+			top = n
+		}
+	}
+	if top == nil {
+		cleanContent(body)
+		return []*html.Node{body}
+	}
+
+	content := []*html.Node{top}
+	if top != body {
+		content = s.withSiblings(top)
+	}
+	for _, n := range content {
+		cleanContent(n)
+	}
+	return content
+}
+
+// prune removes comments, junk elements, hidden elements and page
+// furniture
+func prune(n *html.Node) {
+	for c := n.FirstChild; c != nil; {
+		next := c.NextSibling
+		if c.Type == html.CommentNode || (c.Type == html.ElementNode && removable(c)) {
+			n.RemoveChild(c)
+		} else {
+			prune(c)
+		}
+		c = next
+	}
+}
+
+func removable(n *html.Node) bool {
+	if junk[n.Data] || isHidden(n) {
+		return true
+	}
+	switch n.Data {
+	case "body", "article", "main", "a", "pre", "code", "table", "tbody", "tr", "td", "th":
+		return false
+	}
+	hints := attr(n, "class") + " " + attr(n, "id")
+	return unlikely.MatchString(hints) && !likely.MatchString(hints)
+}
+
+func isHidden(n *html.Node) bool {
+	style := strings.ReplaceAll(strings.ToLower(attr(n, "style")), " ", "")
+	return hasAttr(n, "hidden") || attr(n, "aria-hidden") == "true" ||
+		strings.Contains(style, "display:none") || strings.Contains(style, "visibility:hidden")
+}
+
+// scorer gives each paragraph's parent and grandparents points for its
+// text, so the block holding most of the article text scores highest
+type scorer struct {
+	scores map[*html.Node]float64
+	order  []*html.Node // Scored elements in the order they were found
+}
+
+func (s *scorer) walk(n *html.Node) {
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type != html.ElementNode {
+			continue
+		}
+		if paragraphs[c.Data] || (c.Data == "div" && !hasBlockChild(c)) {
+			s.paragraph(c)
+		}
+		s.walk(c)
+	}
+}
+
+// paragraph scores a paragraph on its length and commas and shares the
+// points with its ancestors: all of them for the parent, half for the
+// grandparent and a sixth for the great-grandparent
+func (s *scorer) paragraph(n *html.Node) {
+	text := collapseSpace(textContent(n))
+	length := len([]rune(text))
+	if length < minParagraphLength {
+		return
+	}
+	points := 1 + float64(strings.Count(text, ",")) + min(float64(length)/100, 3)
+
+	ancestor := n.Parent
+	for _, divisor := range []float64{1, 2, 6} {
+		if ancestor == nil || ancestor.Type != html.ElementNode || ancestor.Data == "html" {
+			return
+		}
+		s.add(ancestor, points/divisor)
+		ancestor = ancestor.Parent
+	}
+}
+
+func (s *scorer) add(n *html.Node, points float64) {
+	if _, ok := s.scores[n]; !ok {
+		s.scores[n] = tagScore(n) + classWeight(n)
+		s.order = append(s.order, n)
+	}
+	s.scores[n] += points
+}
+
+// withSiblings returns top with the siblings that score close to it or
+// read like prose
+func (s *scorer) withSiblings(top *html.Node) []*html.Node {
+	topScore := s.scores[top]
+	threshold := max(minSiblingScore, topScore*0.2)
+	topClass := attr(top, "class")
+
+	var content []*html.Node
+	for sib := top.Parent.FirstChild; sib != nil; sib = sib.NextSibling {
+		if sib == top {
+			content = append(content, sib)
+			continue
+		}
+		if sib.Type != html.ElementNode {
+			continue
+		}
+
+		bonus := 0.0
+		if topClass != "" && attr(sib, "class") == topClass {
+			bonus = topScore * 0.2
+		}
+		if score, ok := s.scores[sib]; ok && score+bonus >= threshold {
+			content = append(content, sib)
+			continue
+		}
+		if sib.Data == "p" {
+			text := collapseSpace(textContent(sib))
+			density := linkDensity(sib)
+			length := len([]rune(text))
+			if (length > 80 && density < 0.25) || (length > 0 && density == 0 && strings.Contains(text, ". ")) {
+				content = append(content, sib)
+			}
+		}
+	}
+	return content
+}
+
+// cleanContent removes the lists and containers inside the article that
+// are mostly links or marked as boilerplate, such as related-post lists and
+// share widgets
+func cleanContent(n *html.Node) {
+	for c := n.FirstChild; c != nil; {
+		next := c.NextSibling
+		if c.Type == html.ElementNode {
+			switch c.Data {
+			case "div", "section", "ul", "ol", "table", "header":
+				if weight := classWeight(c); weight < 0 || (linkDensity(c) > 0.5 && weight < 25) {
+					n.RemoveChild(c)
+					c = next
+					continue
+				}
+			}
+			cleanContent(c)
+		}
+		c = next
+	}
+}
+
+// tagScore is the starting score of an element by kind
+func tagScore(n *html.Node) float64 {
+	switch n.Data {
+	case "article", "main", "section", "div":
+		return 5
+	case "pre", "td", "blockquote":
+		return 3
+	case "address", "ol", "ul", "dl", "dd", "dt", "li", "form":
+		return -3
+	case "h1", "h2", "h3", "h4", "h5", "h6", "th":
+		return -5
+	}
+	return 0
+}
+
+// classWeight scores an element's class and id by the words they contain
+func classWeight(n *html.Node) float64 {
+	weight := 0.0
+	for _, hint := range []string{attr(n, "class"), attr(n, "id")} {
+		if hint == "" {
+			continue
+		}
+		if negative.MatchString(hint) {
+			weight -= 25
+		}
+		if positive.MatchString(hint) {
+			weight += 25
+		}
+	}
+	return weight
+}
+
+// linkDensity is the share of an element's text inside links
+func linkDensity(n *html.Node) float64 {
+	length := len([]rune(collapseSpace(textContent(n))))
+	if length == 0 {
+		return 0
+	}
+	linked := 0
+	for _, a := range findElements(n, "a") {
+		linked += len([]rune(collapseSpace(textContent(a))))
+	}
+	return float64(linked) / float64(length)
+}
+
+func hasBlockChild(n *html.Node) bool {
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type == html.ElementNode && blockElements[c.Data] {
+			return true
+		}
+	}
+	return false
+}
+
+func hasAttr(n *html.Node, key string) bool {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,93 @@
+package extract
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+
+	"golang.org/x/net/html"
+)
+
+func TestFindContent(t *testing.T) {
+	prose := "This paragraph is long enough to count, with a comma or two, and reads like article text. "
+
+	tests := []struct {
+		name    string
+		page    string
+		want    []string // Text expected in the content
+		notWant []string // Text expected to be left out
+	}{
+		{
+			name: "densest block over link lists",
+			page: `<body><div id="links"><p><a href="/1">` + prose + `</a></p></div>
+			<div id="story"><p>` + prose + `</p><p>` + prose + `</p><p>Story end.</p></div></body>`,
+			want:    []string{"Story end."},
+			notWant: []string{"[This paragraph"},
+		},
+		{
+			name:    "positive class beats plain div",
+			page:    `<body><div><p>` + prose + `Plain.</p></div><div class="entry-content"><p>` + prose + `Entry.</p></div></body>`,
+			want:    []string{"Entry."},
+			notWant: []string{"Plain."},
+		},
+		{
+			name:    "prose siblings are kept",
+			page:    `<body><div><div class="text"><p>` + prose + `</p><p>` + prose + `</p></div><p>A short closing note. With two sentences.</p><p><a href="/x">Next post</a></p></div></body>`,
+			want:    []string{"A short closing note."},
+			notWant: []string{"Next post"},
+		},
+		{
+			name:    "hidden and unlikely blocks are pruned",
+			page:    `<body><div><p>` + prose + `</p><p style="display: none">Hidden text.</p><div class="newsletter-signup"><p>` + prose + `Subscribe!</p></div><p>` + prose + `</p></div></body>`,
+			notWant: []string{"Hidden text.", "Subscribe!"},
+		},
+		{
+			name: "no paragraphs falls back to the body",
+			page: `<body><div><span>Short</span> <span>text</span></div></body>`,
+			want: []string{"Short text"},
+		},
+	}
+
+	base, _ := url.Parse("https://example.com/")
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := html.Parse(strings.NewReader(tt.page))
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := toMarkdown(findContent(doc), base, "")
+			for _, want := range tt.want {
+				if !strings.Contains(got, want) {
+					t.Errorf("content lacks %q\n%s", want, got)
+				}
+			}
+			for _, unwanted := range tt.notWant {
+				if strings.Contains(got, unwanted) {
+					t.Errorf("content has %q\n%s", unwanted, got)
+				}
+			}
+		})
+	}
+}
+
+func TestLinkDensity(t *testing.T) {
+	tests := []struct {
+		html string
+		want float64
+	}{
+		{`<p>no links</p>`, 0},
+		{`<p><a href="/">all link</a></p>`, 1},
+		{`<p>abc <a href="/">link</a></p>`, 0.5},
+		{`<p></p>`, 0},
+	}
+
+	for _, tt := range tests {
+		doc, err := html.Parse(strings.NewReader(tt.html))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := linkDensity(findElement(doc, "p")); got != tt.want {
+			t.Errorf("linkDensity(%s) = %v, want %v", tt.html, got, tt.want)
+		}
+	}
+}
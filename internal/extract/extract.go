@@ -0,0 +1,214 @@
+// Package extract pulls the readable article out of an HTML page, leaving
+// navigation, sidebars, comment threads and other boilerplate behind. The
+// content is found by scoring blocks on their text and link density, in the
+// manner of Readability, and returned as Markdown.
+package extract
+
+import (
+	"io"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// titleSeparators split a document title from the site name, as in
+// "Article | Site"
+var titleSeparators = []string{" | ", " – ", " — ", " - ", " :: ", " » ", " · "}
+
+var (
+	// bylineHint matches classes and ids of elements holding the author
+	bylineHint = regexp.MustCompile(`(?i)byline|author|dateline|writtenby`)
+	// commentsHint matches comment threads, whose authors are not the byline
+	commentsHint = regexp.MustCompile(`(?i)comment|disqus|replies`)
+)
+
+// maxBylineLength is the longest text accepted as a byline
+const maxBylineLength = 100
+
+// Article is the readable content of a page
+type Article struct {
+	Title    string
+	Byline   string // Author, as credited on the page
+	Markdown string // Article body, without the title
+}
+
+// Words returns the number of words in the article body
+func (a *Article) Words() int {
+	return len(strings.Fields(a.Markdown))
+}
+
+// Extract finds the article in an HTML page. Links and images are made
+// absolute against pageURL, or the document's <base>. An article with an
+// empty body is returned when the page has no readable text.
+func Extract(r io.Reader, pageURL string) (*Article, error) {
+	doc, err := html.Parse(r)
+	if err != nil {
+		return nil, err
+	}
+	base, err := url.Parse(pageURL)
+	if err != nil {
+		return nil, err
+	}
+	if b := findElement(doc, "base"); b != nil {
+		if u, err := base.Parse(attr(b, "href")); err == nil {
+			base = u
+		}
+	}
+
+	a := &Article{Title: findTitle(doc)}
+	var bylineNode *html.Node
+	a.Byline, bylineNode = findByline(doc)
+	if bylineNode != nil && bylineNode.Parent != nil {
+		// Credited once in Byline rather than again in the body
+		bylineNode.Parent.RemoveChild(bylineNode)
+	}
+
+	a.Markdown = toMarkdown(findContent(doc), base, a.Title)
+	return a, nil
+}
+
+// findTitle returns og:title, the document title without the site name, or
+// the page's only <h1>
+func findTitle(doc *html.Node) string {
+	if title := metaContent(doc, "og:title"); title != "" {
+		return title
+	}
+	if t := findElement(doc, "title"); t != nil {
+		if title := cleanTitle(collapseSpace(textContent(t))); title != "" {
+			return title
+		}
+	}
+	if h1s := findElements(doc, "h1"); len(h1s) == 1 {
+		return collapseSpace(textContent(h1s[0]))
+	}
+	return ""
+}
+
+// cleanTitle drops a trailing site name from a document title, unless
+// that would leave fewer than three words
+func cleanTitle(title string) string {
+	for _, sep := range titleSeparators {
+		if i := strings.LastIndex(title, sep); i > 0 {
+			if head := title[:i]; len(strings.Fields(head)) >= 3 {
+				return head
+			}
+		}
+	}
+	return title
+}
+
+// findByline returns the author named by the page's meta tags or, failing
+// that, by a short element marked as the byline, along with that element
+func findByline(doc *html.Node) (string, *html.Node) {
+	if author := metaContent(doc, "author"); author != "" {
+		return author, nil
+	}
+	if author := metaContent(doc, "article:author"); author != "" && !strings.HasPrefix(author, "http") {
+		return author, nil
+	}
+
+	var found *html.Node
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		if found != nil {
+			return
+		}
+		if n.Type == html.ElementNode && commentsHint.MatchString(attr(n, "class")+" "+attr(n, "id")) {
+			return
+		}
+		if n.Type == html.ElementNode && isByline(n) {
+			if text := collapseSpace(textContent(n)); text != "" && len(text) <= maxBylineLength {
+				found = n
+				return
+			}
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	if body := findElement(doc, "body"); body != nil {
+		walk(body)
+	}
+	if found == nil {
+		return "", nil
+	}
+
+	byline := collapseSpace(textContent(found))
+	if len(byline) > 3 && strings.EqualFold(byline[:3], "by ") {
+		byline = byline[3:]
+	}
+	return byline, found
+}
+
+func isByline(n *html.Node) bool {
+	if attr(n, "rel") == "author" || strings.Contains(attr(n, "itemprop"), "author") {
+		return true
+	}
+	return bylineHint.MatchString(attr(n, "class") + " " + attr(n, "id"))
+}
+
+// metaContent returns the content of the first <meta> whose name or
+// property is key
+func metaContent(doc *html.Node, key string) string {
+	for _, m := range findElements(doc, "meta") {
+		if strings.EqualFold(attr(m, "name"), key) || strings.EqualFold(attr(m, "property"), key) {
+			if content := collapseSpace(attr(m, "content")); content != "" {
+				return content
+			}
+		}
+	}
+	return ""
+}
+
+// findElement returns the first element named tag in depth-first order
+func findElement(n *html.Node, tag string) *html.Node {
+	if n.Type == html.ElementNode && n.Data == tag {
+		return n
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if found := findElement(c, tag); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+// findElements returns every element named tag in document order
+func findElements(n *html.Node, tag string) []*html.Node {
+	var found []*html.Node
+	if n.Type == html.ElementNode && n.Data == tag {
+		found = append(found, n)
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		found = append(found, findElements(c, tag)...)
+	}
+	return found
+}
+
+// textContent concatenates the text below a node
+func textContent(n *html.Node) string {
+	if n.Type == html.TextNode {
+		return n.Data
+	}
+	var s strings.Builder
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		s.WriteString(textContent(c))
+	}
+	return s.String()
+}
+
+// attr returns the value of an element's attribute, or "" when it has none
+func attr(n *html.Node, key string) string {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return a.Val
+		}
+	}
+	return ""
+}
+
+func collapseSpace(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
@@ -0,0 +1,139 @@
+package extract
+
+import (
+	"strings"
+	"testing"
+)
+
+const blogPage = `<!DOCTYPE html>
+<html><head>
+<title>Understanding Go Interfaces | Example Blog</title>
+<meta name="description" content="A tour of interfaces">
+</head><body>
+<header class="site-header"><a href="/">Example Blog</a></header>
+<nav><a href="/">Home</a> <a href="/archive">Archive</a> <a href="/about">About</a></nav>
+<div id="page">
+  <div class="sidebar"><h3>Popular</h3><ul><li><a href="/a">Post A</a></li><li><a href="/b">Post B</a></li></ul></div>
+  <article class="post">
+    <h1>Understanding Go Interfaces</h1>
+    <p class="byline">By Jane Doe</p>
+    <p>Interfaces in Go are satisfied implicitly, which means a type never declares the interfaces it implements, and that keeps packages decoupled.</p>
+    <p>A small interface, like io.Reader, is easy to implement, easy to mock in tests, and composes well with other small interfaces.</p>
+    <div class="share-links"><a href="/share/x">Share on X</a> <a href="/share/fb">Share on Facebook</a></div>
+    <p>When an interface grows, consider splitting it, so that callers depend only on the methods they use.</p>
+  </article>
+  <div class="comments"><p>Great post, thanks for writing it, it cleared up a lot for me!</p></div>
+</div>
+<footer>© 2024 Example Blog. All rights reserved.</footer>
+<script>track();</script>
+</body></html>`
+
+func TestExtract(t *testing.T) {
+	a, err := Extract(strings.NewReader(blogPage), "https://blog.example.com/interfaces")
+	if err != nil {
+		t.Fatalf("Extract() error = %v", err)
+	}
+
+	if a.Title != "Understanding Go Interfaces" {
+		t.Errorf("Title = %q", a.Title)
+	}
+	if a.Byline != "Jane Doe" {
+		t.Errorf("Byline = %q", a.Byline)
+	}
+
+	for _, want := range []string{
+		"Interfaces in Go are satisfied implicitly",
+		"A small interface, like io.Reader",
+		"When an interface grows",
+	} {
+		if !strings.Contains(a.Markdown, want) {
+			t.Errorf("Markdown lacks %q\n%s", want, a.Markdown)
+		}
+	}
+	for _, unwanted := range []string{"Home", "Popular", "Share on", "Great post", "rights reserved", "track()", "# Understanding", "Jane Doe"} {
+		if strings.Contains(a.Markdown, unwanted) {
+			t.Errorf("Markdown has boilerplate %q\n%s", unwanted, a.Markdown)
+		}
+	}
+	if words := a.Words(); words < 50 || words > 70 {
+		t.Errorf("Words() = %v, want the three paragraphs", words)
+	}
+}
+
+func TestExtract_Metadata(t *testing.T) {
+	tests := []struct {
+		name       string
+		page       string
+		wantTitle  string
+		wantByline string
+	}{
+		{
+			name:      "og:title wins",
+			page:      `<head><title>Page | Site</title><meta property="og:title" content="Open Graph Title"></head>`,
+			wantTitle: "Open Graph Title",
+		},
+		{
+			name:      "single h1 when there is no title",
+			page:      `<body><h1>Heading</h1><p>Text</p></body>`,
+			wantTitle: "Heading",
+		},
+		{
+			name:       "meta author",
+			page:       `<head><meta name="author" content="John Smith"></head><body><span class="author">Someone Else</span></body>`,
+			wantByline: "John Smith",
+		},
+		{
+			name:       "article:author URLs are not names",
+			page:       `<head><meta property="article:author" content="https://example.com/john"></head><body><a rel="author" href="/john">John</a></body>`,
+			wantByline: "John",
+		},
+		{
+			name: "comment authors are not the byline",
+			page: `<body><div class="comments"><span class="comment-author">Commenter</span></div></body>`,
+		},
+		{
+			name: "long author bios are not bylines",
+			page: `<body><div class="author-bio">` + strings.Repeat("Writes about things. ", 10) + `</div></body>`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, err := Extract(strings.NewReader(tt.page), "https://example.com/")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if a.Title != tt.wantTitle || a.Byline != tt.wantByline {
+				t.Errorf("Extract() title, byline = %q, %q; want %q, %q", a.Title, a.Byline, tt.wantTitle, tt.wantByline)
+			}
+		})
+	}
+}
+
+func TestExtract_Empty(t *testing.T) {
+	a, err := Extract(strings.NewReader(`<html><body><nav><a href="/">Home</a></nav></body></html>`), "https://example.com/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.Markdown != "" {
+		t.Errorf("Markdown = %q, want empty for a page without text", a.Markdown)
+	}
+}
+
+func TestCleanTitle(t *testing.T) {
+	tests := []struct {
+		title string
+		want  string
+	}{
+		{"How to Write Go Code | The Go Blog", "How to Write Go Code"},
+		{"A Long Article Title - Example News", "A Long Article Title"},
+		{"Go - Docs", "Go - Docs"},
+		{"No separator here", "No separator here"},
+	}
+
+	for _, tt := range tests {
+		if got := cleanTitle(tt.title); got != tt.want {
+			t.Errorf("cleanTitle(%q) = %q, want %q", tt.title, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,310 @@
+package extract
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+var (
+	// spaceRun matches whitespace that renders as a single space
+	spaceRun = regexp.MustCompile(`\s+`)
+	// escaper escapes characters with a meaning in Markdown text
+	escaper = strings.NewReplacer(`\`, `\\`, "*", `\*`, "_", `\_`, "`", "\\`", "[", `\[`, "]", `\]`)
+)
+
+// containers lists elements rendered as a sequence of blocks
+var containers = map[string]bool{
+	"address": true, "article": true, "body": true, "center": true, "details": true, "div": true,
+	"dl": true, "figure": true, "footer": true, "header": true, "main": true, "section": true,
+	"summary": true,
+}
+
+// markdown renders nodes as Markdown blocks separated by blank lines
+type markdown struct {
+	base   *url.URL
+	title  string // Leading heading left out because it repeats the title
+	blocks []string
+	line   strings.Builder // Inline text of the block being rendered
+}
+
+// toMarkdown renders nodes as Markdown, resolving links against base. A
+// heading repeating title before any text is left out.
+func toMarkdown(nodes []*html.Node, base *url.URL, title string) string {
+	m := &markdown{base: base, title: title}
+	for _, n := range nodes {
+		m.block(n)
+	}
+	m.flush()
+	return strings.Join(m.blocks, "\n\n")
+}
+
+// block renders a node that may contain blocks
+func (m *markdown) block(n *html.Node) {
+	if n.Type == html.TextNode {
+		m.line.WriteString(escaper.Replace(spaceRun.ReplaceAllString(n.Data, " ")))
+		return
+	}
+	if n.Type != html.ElementNode {
+		return
+	}
+
+	switch n.Data {
+	case "h1", "h2", "h3", "h4", "h5", "h6":
+		m.flush()
+		text := m.inlineChildren(n)
+		if len(m.blocks) == 0 && m.title != "" && collapseSpace(textContent(n)) == m.title {
+			return
+		}
+		level, _ := strconv.Atoi(n.Data[1:])
+		m.emit(strings.Repeat("#", level) + " " + text)
+	case "p", "figcaption", "dd":
+		m.flush()
+		m.emit(m.inlineChildren(n))
+	case "dt":
+		m.flush()
+		m.emit(wrap(m.inlineChildren(n), "**"))
+	case "ul", "ol":
+		m.flush()
+		if lines := m.list(n, ""); len(lines) > 0 {
+			m.blocks = append(m.blocks, strings.Join(lines, "\n"))
+		}
+	case "blockquote":
+		m.flush()
+		var children []*html.Node
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			children = append(children, c)
+		}
+		if quoted := toMarkdown(children, m.base, ""); quoted != "" {
+			lines := strings.Split(quoted, "\n")
+			for i, line := range lines {
+				lines[i] = strings.TrimRight("> "+line, " ")
+			}
+			m.blocks = append(m.blocks, strings.Join(lines, "\n"))
+		}
+	case "pre":
+		m.flush()
+		m.code(n)
+	case "table":
+		m.flush()
+		m.table(n)
+	case "hr":
+		m.flush()
+		m.blocks = append(m.blocks, "---")
+	default:
+		if !containers[n.Data] {
+			m.line.WriteString(m.inline(n))
+			return
+		}
+		m.flush()
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			m.block(c)
+		}
+		m.flush()
+	}
+}
+
+// inline renders a node inside a block
+func (m *markdown) inline(n *html.Node) string {
+	if n.Type == html.TextNode {
+		return escaper.Replace(spaceRun.ReplaceAllString(n.Data, " "))
+	}
+	if n.Type != html.ElementNode {
+		return ""
+	}
+
+	switch n.Data {
+	case "br":
+		return "\n"
+	case "em", "i", "cite":
+		return wrap(m.inlineChildren(n), "*")
+	case "strong", "b":
+		return wrap(m.inlineChildren(n), "**")
+	case "code", "kbd", "samp", "tt":
+		code := textContent(n)
+		fence := "`"
+		if strings.Contains(code, "`") {
+			fence = "``"
+		}
+		if strings.TrimSpace(code) == "" {
+			return code
+		}
+		return fence + code + fence
+	case "a":
+		text := m.inlineChildren(n)
+		href := m.link(attr(n, "href"))
+		if href == "" || strings.TrimSpace(text) == "" {
+			return text
+		}
+		return "[" + strings.TrimSpace(text) + "](" + href + ")"
+	case "img":
+		src := attr(n, "src")
+		if src == "" || strings.HasPrefix(src, "data:") {
+			// Lazy-loaded images keep the real source aside
+			src = attr(n, "data-src")
+		}
+		if src = m.link(src); src == "" {
+			return ""
+		}
+		return "![" + escaper.Replace(collapseSpace(attr(n, "alt"))) + "](" + src + ")"
+	}
+	return m.inlineChildren(n)
+}
+
+func (m *markdown) inlineChildren(n *html.Node) string {
+	var s strings.Builder
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		s.WriteString(m.inline(c))
+	}
+	return s.String()
+}
+
+// list renders the items of a list, nested lists indented under them
+func (m *markdown) list(n *html.Node, indent string) []string {
+	number := 1
+	if start, err := strconv.Atoi(attr(n, "start")); err == nil {
+		number = start
+	}
+
+	var lines []string
+	for li := n.FirstChild; li != nil; li = li.NextSibling {
+		if li.Type != html.ElementNode || li.Data != "li" {
+			continue
+		}
+		marker := "- "
+		if n.Data == "ol" {
+			marker = strconv.Itoa(number) + ". "
+			number++
+		}
+
+		var text strings.Builder
+		var nested []string
+		for c := li.FirstChild; c != nil; c = c.NextSibling {
+			if c.Type == html.ElementNode && (c.Data == "ul" || c.Data == "ol") {
+				nested = append(nested, m.list(c, indent+strings.Repeat(" ", len(marker)))...)
+				continue
+			}
+			text.WriteString(m.inline(c))
+			if c.Type == html.ElementNode && (c.Data == "p" || c.Data == "div") {
+				text.WriteString(" ")
+			}
+		}
+		if item := cleanLine(text.String()); item != "" || len(nested) > 0 {
+			lines = append(lines, indent+marker+item)
+		}
+		lines = append(lines, nested...)
+	}
+	return lines
+}
+
+// code renders a preformatted block as a fenced code block, with the
+// language of a "language-x" class
+func (m *markdown) code(n *html.Node) {
+	code := strings.Trim(textContent(n), "\n")
+	if strings.TrimSpace(code) == "" {
+		return
+	}
+
+	language := ""
+	classes := attr(n, "class")
+	if c := findElement(n, "code"); c != nil {
+		classes += " " + attr(c, "class")
+	}
+	for _, class := range strings.Fields(classes) {
+		if lang, ok := strings.CutPrefix(class, "language-"); ok {
+			language = lang
+			break
+		}
+	}
+
+	fence := "```"
+	for strings.Contains(code, fence) {
+		fence += "`"
+	}
+	m.blocks = append(m.blocks, fence+language+"\n"+code+"\n"+fence)
+}
+
+// table renders a table as a pipe table, its first row as the header
+func (m *markdown) table(n *html.Node) {
+	var rows [][]string
+	for _, tr := range findElements(n, "tr") {
+		var cells []string
+		for c := tr.FirstChild; c != nil; c = c.NextSibling {
+			if c.Type == html.ElementNode && (c.Data == "td" || c.Data == "th") {
+				cell := strings.ReplaceAll(cleanLine(strings.ReplaceAll(m.inlineChildren(c), "\n", " ")), "|", `\|`)
+				cells = append(cells, cell)
+			}
+		}
+		if len(cells) > 0 {
+			rows = append(rows, cells)
+		}
+	}
+	if len(rows) == 0 {
+		return
+	}
+
+	columns := 0
+	for _, row := range rows {
+		columns = max(columns, len(row))
+	}
+	var s strings.Builder
+	for i, row := range rows {
+		row = append(row, make([]string, columns-len(row))...)
+		fmt.Fprintf(&s, "| %s |\n", strings.Join(row, " | "))
+		if i == 0 {
+			fmt.Fprintf(&s, "|%s\n", strings.Repeat(" --- |", columns))
+		}
+	}
+	m.blocks = append(m.blocks, strings.TrimRight(s.String(), "\n"))
+}
+
+// link makes href absolute, keeping http(s) links only
+func (m *markdown) link(href string) string {
+	href = strings.TrimSpace(href)
+	if href == "" || strings.HasPrefix(href, "#") {
+		return ""
+	}
+	u, err := m.base.Parse(href)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return ""
+	}
+	return strings.NewReplacer("(", "%28", ")", "%29", " ", "%20").Replace(u.String())
+}
+
+// flush ends the block being rendered
+func (m *markdown) flush() {
+	m.emit(m.line.String())
+	m.line.Reset()
+}
+
+func (m *markdown) emit(text string) {
+	if text = cleanLine(text); text != "" {
+		m.blocks = append(m.blocks, text)
+	}
+}
+
+// cleanLine collapses the whitespace of rendered inline text, keeping line
+// breaks as Markdown hard breaks
+func cleanLine(text string) string {
+	var lines []string
+	for _, line := range strings.Split(text, "\n") {
+		if line = collapseSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return strings.Join(lines, "  \n")
+}
+
+// wrap surrounds text with a marker, leaving its outer spaces outside
+func wrap(text, marker string) string {
+	trimmed := strings.TrimSpace(text)
+	if trimmed == "" {
+		return text
+	}
+	start := strings.Index(text, trimmed)
+	return text[:start] + marker + trimmed + marker + text[start+len(trimmed):]
+}
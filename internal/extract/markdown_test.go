@@ -0,0 +1,83 @@
+package extract
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+
+	"golang.org/x/net/html"
+)
+
+func TestToMarkdown(t *testing.T) {
+	tests := []struct {
+		name string
+		html string
+		want string
+	}{
+		{
+			name: "paragraphs and inline markup",
+			html: `<p>Some <em>emphasis</em>, <strong>strong</strong> and <code>code</code>.</p><p>Second
+			paragraph.</p>`,
+			want: "Some *emphasis*, **strong** and `code`.\n\nSecond paragraph.",
+		},
+		{
+			name: "headings",
+			html: `<h2>Section</h2><p>Text</p><h3>Sub <i>section</i></h3>`,
+			want: "## Section\n\nText\n\n### Sub *section*",
+		},
+		{
+			name: "heading repeating the title is left out",
+			html: `<h1>The Title</h1><p>Text</p><h2>The Title</h2>`,
+			want: "Text\n\n## The Title",
+		},
+		{
+			name: "links and images resolved",
+			html: `<p>See <a href="/docs">the docs</a> or <a href="#top">top</a>.</p><img src="img/a.png" alt="A chart">`,
+			want: "See [the docs](https://example.com/docs) or top.\n\n![A chart](https://example.com/post/img/a.png)",
+		},
+		{
+			name: "data URI images use the lazy source or are dropped",
+			html: `<p><img src="data:image/gif;base64,R0lG" data-src="/real.jpg"><img src="data:image/png;base64,iVBO"></p>`,
+			want: "![](https://example.com/real.jpg)",
+		},
+		{
+			name: "nested lists",
+			html: `<ul><li>One<ul><li>One a</li></ul></li><li><p>Two</p></li></ul><ol start="3"><li>Three</li><li>Four</li></ol>`,
+			want: "- One\n  - One a\n- Two\n\n3. Three\n4. Four",
+		},
+		{
+			name: "blockquote",
+			html: `<blockquote><p>Quoted</p><p>Twice</p></blockquote>`,
+			want: "> Quoted\n>\n> Twice",
+		},
+		{
+			name: "code block with language",
+			html: "<pre><code class=\"language-go\">func main() {\n\tfmt.Println(\"*hi*\")\n}\n</code></pre>",
+			want: "```go\nfunc main() {\n\tfmt.Println(\"*hi*\")\n}\n```",
+		},
+		{
+			name: "table",
+			html: `<table><tr><th>Name</th><th>Value</th></tr><tr><td>a|b</td><td>1</td></tr><tr><td>c</td></tr></table>`,
+			want: "| Name | Value |\n| --- | --- |\n| a\\|b | 1 |\n| c |  |",
+		},
+		{
+			name: "line breaks and escaping",
+			html: `<p>first_line<br>[second] *line*</p><hr><div>Loose text</div>`,
+			want: "first\\_line  \n\\[second\\] \\*line\\*\n\n---\n\nLoose text",
+		},
+	}
+
+	base, _ := url.Parse("https://example.com/post/")
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := html.Parse(strings.NewReader(tt.html))
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := toMarkdown([]*html.Node{findElement(doc, "body")}, base, "The Title")
+			if got != tt.want {
+				t.Errorf("toMarkdown() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package fetch
+
+import (
+	"crypto/sha256"
+	"encoding/gob"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Cache keeps fetched pages on disk, one file per URL. A nil *Cache caches
+// nothing.
+type Cache struct {
+	dir string
+	ttl time.Duration
+}
+
+// NewCache creates a cache in dir whose pages expire after ttl (0 keeps
+// them forever)
+func NewCache(dir string, ttl time.Duration) *Cache {
+	return &Cache{dir: dir, ttl: ttl}
+}
+
+// Load returns the cached page for rawURL unless it is missing or expired
+func (c *Cache) Load(rawURL string) (*Page, bool) {
+	if c == nil {
+		return nil, false
+	}
+
+	f, err := os.Open(c.path(rawURL))
+	if err != nil {
+		return nil, false
+	}
+	defer f.Close()
+
+	var page Page
+	if err := gob.NewDecoder(f).Decode(&page); err != nil || page.URL != rawURL {
+		return nil, false
+	}
+	if c.ttl > 0 && time.Since(page.FetchedAt) > c.ttl {
+		return nil, false
+	}
+	page.Cached = true
+	return &page, true
+}
+
+// Store writes a page to the cache, replacing any previous copy atomically
+func (c *Cache) Store(page *Page) error {
+	if c == nil {
+		return nil
+	}
+	if err := os.MkdirAll(c.dir, 0755); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(c.dir, ".page-*")
+	if err != nil {
+		return fmt.Errorf("failed to write cache: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := gob.NewEncoder(tmp).Encode(page); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write cache: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write cache: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), c.path(page.URL)); err != nil {
+		return fmt.Errorf("failed to write cache: %w", err)
+	}
+	return nil
+}
+
+// path returns the cache file of a URL
+func (c *Cache) path(rawURL string) string {
+	sum := sha256.Sum256([]byte(rawURL))
+	return filepath.Join(c.dir, hex.EncodeToString(sum[:])+".gob")
+}
@@ -0,0 +1,46 @@
+package fetch
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCache(t *testing.T) {
+	cache := NewCache(t.TempDir(), time.Hour)
+	page := &Page{URL: "https://example.com/", Body: []byte("hello"), FetchedAt: time.Now()}
+
+	if _, ok := cache.Load(page.URL); ok {
+		t.Fatal("Load() on an empty cache should miss")
+	}
+	if err := cache.Store(page); err != nil {
+		t.Fatalf("Store() error = %v", err)
+	}
+	loaded, ok := cache.Load(page.URL)
+	if !ok || string(loaded.Body) != "hello" || !loaded.Cached {
+		t.Errorf("Load() = %+v, %v, want the stored page marked cached", loaded, ok)
+	}
+	if _, ok := cache.Load("https://example.com/other"); ok {
+		t.Error("Load() of another URL should miss")
+	}
+
+	stale := &Page{URL: "https://example.com/old", FetchedAt: time.Now().Add(-2 * time.Hour)}
+	if err := cache.Store(stale); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := cache.Load(stale.URL); ok {
+		t.Error("Load() should miss pages older than the TTL")
+	}
+	if _, ok := NewCache(cache.dir, 0).Load(stale.URL); !ok {
+		t.Error("Load() without a TTL should keep old pages")
+	}
+}
+
+func TestCache_Nil(t *testing.T) {
+	var cache *Cache
+	if err := cache.Store(&Page{URL: "https://example.com/"}); err != nil {
+		t.Errorf("Store() on a nil cache error = %v", err)
+	}
+	if _, ok := cache.Load("https://example.com/"); ok {
+		t.Error("Load() on a nil cache should miss")
+	}
+}
@@ -0,0 +1,142 @@
+// Package fetch downloads bookmarked pages for enrichment and archiving.
+// Responses are kept in a disk cache so repeated runs don't request the
+// same pages again, and requests to one host are spaced out.
+package fetch
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// UserAgent identifies page requests to servers
+const UserAgent = "moxli/1.0 (+https://github.com/lelopez-io/moxli)"
+
+// DefaultMaxBytes is the largest response body read by default
+const DefaultMaxBytes = 5 << 20
+
+// Options configures a Client
+type Options struct {
+	Timeout   time.Duration // Limit for each request, redirects included
+	MaxBytes  int64         // Largest body read, longer ones are cut (0 uses DefaultMaxBytes)
+	HostDelay time.Duration // Minimum time between requests to the same host
+	Cache     *Cache        // Where responses are kept, nil for none
+}
+
+// Page is a fetched response
+type Page struct {
+	URL         string // Requested URL
+	FinalURL    string // URL after redirects
+	StatusCode  int
+	ContentType string // Media type without parameters
+	Body        []byte
+	Truncated   bool // Body was cut at MaxBytes
+	FetchedAt   time.Time
+	Cached      bool // Served from the cache
+}
+
+// IsHTML reports whether the page is an HTML document
+func (p *Page) IsHTML() bool {
+	return p.ContentType == "text/html" || p.ContentType == "application/xhtml+xml"
+}
+
+// StatusError is returned for responses outside the 2xx range
+type StatusError struct {
+	URL        string
+	StatusCode int
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("%s: %d %s", e.URL, e.StatusCode, http.StatusText(e.StatusCode))
+}
+
+// Client fetches pages over HTTP
+type Client struct {
+	opts   Options
+	client *http.Client
+	hosts  *HostLimiter
+}
+
+// New creates a Client
+func New(opts Options) *Client {
+	if opts.MaxBytes <= 0 {
+		opts.MaxBytes = DefaultMaxBytes
+	}
+	return &Client{
+		opts:   opts,
+		client: &http.Client{Timeout: opts.Timeout},
+		hosts:  NewHostLimiter(opts.HostDelay),
+	}
+}
+
+// Get returns the page at rawURL, from the cache when it holds a fresh
+// copy. Responses outside 2xx are a *StatusError. A fetched page is
+// returned even when caching it fails.
+func (c *Client) Get(ctx context.Context, rawURL string) (*Page, error) {
+	if page, ok := c.opts.Cache.Load(rawURL); ok {
+		return page, nil
+	}
+
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.hosts.Wait(ctx, strings.ToLower(u.Host)); err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", UserAgent)
+	req.Header.Set("Accept", "text/html,application/xhtml+xml,*/*;q=0.8")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, &StatusError{URL: rawURL, StatusCode: resp.StatusCode}
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, c.opts.MaxBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", rawURL, err)
+	}
+	truncated := int64(len(body)) > c.opts.MaxBytes
+	if truncated {
+		body = body[:c.opts.MaxBytes]
+	}
+
+	page := &Page{
+		URL:         rawURL,
+		FinalURL:    resp.Request.URL.String(),
+		StatusCode:  resp.StatusCode,
+		ContentType: mediaType(resp.Header.Get("Content-Type"), body),
+		Body:        body,
+		Truncated:   truncated,
+		FetchedAt:   time.Now(),
+	}
+	if err := c.opts.Cache.Store(page); err != nil {
+		return page, fmt.Errorf("failed to cache %s: %w", rawURL, err)
+	}
+	return page, nil
+}
+
+// mediaType returns the media type of a Content-Type header, sniffing the
+// body when the header is missing or invalid
+func mediaType(header string, body []byte) string {
+	if t, _, err := mime.ParseMediaType(header); err == nil {
+		return t
+	}
+	t, _, _ := mime.ParseMediaType(http.DetectContentType(body))
+	return t
+}
@@ -0,0 +1,109 @@
+package fetch
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func newTestServer(t *testing.T, hits *atomic.Int32) *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/page", func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		if r.UserAgent() != UserAgent {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Write([]byte("<title>Page</title>"))
+	})
+	mux.HandleFunc("/untyped", func(w http.ResponseWriter, r *http.Request) {
+		w.Header()["Content-Type"] = nil
+		w.Write([]byte("<!DOCTYPE html><html><title>Sniffed</title></html>"))
+	})
+	mux.HandleFunc("/big", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(strings.Repeat("x", 100)))
+	})
+	mux.HandleFunc("/moved", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/page", http.StatusMovedPermanently)
+	})
+	mux.HandleFunc("/missing", http.NotFound)
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestClient_Get(t *testing.T) {
+	var hits atomic.Int32
+	server := newTestServer(t, &hits)
+	client := New(Options{Timeout: time.Second, MaxBytes: 10})
+	ctx := context.Background()
+
+	page, err := client.Get(ctx, server.URL+"/moved")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if page.FinalURL != server.URL+"/page" || page.URL != server.URL+"/moved" {
+		t.Errorf("URL, FinalURL = %q, %q, want the requested and the redirected URL", page.URL, page.FinalURL)
+	}
+	if !page.IsHTML() || page.StatusCode != 200 || page.Cached {
+		t.Errorf("page = %q %v cached=%v, want a fresh text/html 200", page.ContentType, page.StatusCode, page.Cached)
+	}
+
+	page, err = New(Options{}).Get(ctx, server.URL+"/untyped")
+	if err != nil || !page.IsHTML() {
+		t.Errorf("Get(/untyped) = %v, %v, want the content type sniffed as HTML", page, err)
+	}
+
+	page, err = client.Get(ctx, server.URL+"/big")
+	if err != nil || len(page.Body) != 10 || !page.Truncated {
+		t.Errorf("Get(/big) body = %v bytes (error %v), want it truncated at MaxBytes 10", len(page.Body), err)
+	}
+
+	page, err = New(Options{MaxBytes: 100}).Get(ctx, server.URL+"/big")
+	if err != nil || len(page.Body) != 100 || page.Truncated {
+		t.Errorf("Get(/big) body = %v bytes (error %v), want all 100 bytes, not truncated", len(page.Body), err)
+	}
+
+	_, err = client.Get(ctx, server.URL+"/missing")
+	var statusErr *StatusError
+	if !errors.As(err, &statusErr) || statusErr.StatusCode != 404 {
+		t.Errorf("Get(/missing) error = %v, want a 404 StatusError", err)
+	}
+}
+
+func TestClient_Get_Cache(t *testing.T) {
+	var hits atomic.Int32
+	server := newTestServer(t, &hits)
+	client := New(Options{Timeout: time.Second, Cache: NewCache(t.TempDir(), time.Hour)})
+	ctx := context.Background()
+
+	first, err := client.Get(ctx, server.URL+"/page")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	second, err := client.Get(ctx, server.URL+"/page")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+
+	if hits.Load() != 1 {
+		t.Errorf("server hit %v times, want once", hits.Load())
+	}
+	if !second.Cached || string(second.Body) != string(first.Body) || second.ContentType != first.ContentType {
+		t.Errorf("second Get() = %+v, want the cached copy of the first", second)
+	}
+
+	if _, err := client.Get(ctx, server.URL+"/missing"); err == nil {
+		t.Fatal("Get(/missing) should fail")
+	}
+	if _, ok := client.opts.Cache.Load(server.URL + "/missing"); ok {
+		t.Error("error responses should not be cached")
+	}
+}
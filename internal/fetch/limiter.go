@@ -0,0 +1,62 @@
+package fetch
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// HostLimiter spaces out requests to each host. Every request reserves the
+// next free slot for its host, so concurrent workers queue up instead of
+// hitting one server at once.
+type HostLimiter struct {
+	delay time.Duration
+
+	mu   sync.Mutex
+	next map[string]time.Time // Earliest start of the next request per host
+}
+
+// NewHostLimiter creates a HostLimiter starting requests to the same host
+// at least delay apart
+func NewHostLimiter(delay time.Duration) *HostLimiter {
+	return &HostLimiter{delay: delay, next: make(map[string]time.Time)}
+}
+
+// Wait blocks until a request to host may start
+func (l *HostLimiter) Wait(ctx context.Context, host string) error {
+	l.mu.Lock()
+	start := time.Now()
+	if next := l.next[host]; next.After(start) {
+		start = next
+	}
+	l.next[host] = start.Add(l.delay)
+	l.mu.Unlock()
+
+	return Sleep(ctx, time.Until(start))
+}
+
+// Pause holds back further requests to host for d, as when the server
+// answers with Retry-After
+func (l *HostLimiter) Pause(host string, d time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if until := time.Now().Add(d); until.After(l.next[host]) {
+		l.next[host] = until
+	}
+}
+
+// Sleep waits for d or until ctx is done
+func Sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
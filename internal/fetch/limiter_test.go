@@ -0,0 +1,56 @@
+package fetch
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestHostLimiter_Wait(t *testing.T) {
+	l := NewHostLimiter(30 * time.Millisecond)
+	ctx := context.Background()
+
+	start := time.Now()
+	for range 3 {
+		if err := l.Wait(ctx, "a.example"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 60*time.Millisecond {
+		t.Errorf("three requests to one host took %v, want at least 60ms", elapsed)
+	}
+
+	start = time.Now()
+	if err := l.Wait(ctx, "b.example"); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed > 20*time.Millisecond {
+		t.Errorf("first request to another host waited %v, want no wait", elapsed)
+	}
+}
+
+func TestHostLimiter_Pause(t *testing.T) {
+	l := NewHostLimiter(0)
+	l.Pause("a.example", 40*time.Millisecond)
+
+	start := time.Now()
+	if err := l.Wait(context.Background(), "a.example"); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed < 40*time.Millisecond {
+		t.Errorf("wait after pause took %v, want at least 40ms", elapsed)
+	}
+}
+
+func TestHostLimiter_Cancelled(t *testing.T) {
+	l := NewHostLimiter(time.Hour)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	if err := l.Wait(ctx, "a.example"); err != nil {
+		t.Fatalf("first wait error = %v, want nil", err)
+	}
+	cancel()
+	if err := l.Wait(ctx, "a.example"); err == nil {
+		t.Error("Wait() after cancel should return the context error")
+	}
+}
@@ -0,0 +1,63 @@
+package fetch
+
+import (
+	"context"
+	"sync"
+)
+
+// Parallel calls fn with each index of order on up to workers goroutines,
+// in that order, and calls progress (when not nil) from the calling
+// goroutine after each. order must hold each index below len(order) once.
+// Once ctx is cancelled no further calls start; the result tells which
+// indexes finished before that.
+func Parallel(ctx context.Context, workers int, order []int, fn func(i int), progress func(done, total int)) []bool {
+	finished := make([]bool, len(order))
+	jobs := make(chan int)
+	done := make(chan int)
+
+	var wg sync.WaitGroup
+	for range min(max(workers, 1), len(order)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				fn(i)
+				// A call cut short by cancellation didn't finish
+				finished[i] = ctx.Err() == nil
+				done <- i
+			}
+		}()
+	}
+	go func() {
+		defer close(jobs)
+		for _, i := range order {
+			select {
+			case jobs <- i:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	count := 0
+	for range done {
+		count++
+		if progress != nil {
+			progress(count, len(order))
+		}
+	}
+	return finished
+}
+
+// Sequence returns the indexes 0 to n-1 in order
+func Sequence(n int) []int {
+	order := make([]int, n)
+	for i := range order {
+		order[i] = i
+	}
+	return order
+}
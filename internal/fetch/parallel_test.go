@@ -0,0 +1,67 @@
+package fetch
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestParallel(t *testing.T) {
+	var mu sync.Mutex
+	inFlight, maxInFlight := 0, 0
+	var started []int
+
+	calls := 0
+	finished := Parallel(context.Background(), 2, []int{2, 0, 1, 3}, func(i int) {
+		mu.Lock()
+		started = append(started, i)
+		inFlight++
+		maxInFlight = max(maxInFlight, inFlight)
+		mu.Unlock()
+
+		time.Sleep(10 * time.Millisecond)
+
+		mu.Lock()
+		inFlight--
+		mu.Unlock()
+	}, func(done, total int) {
+		calls++
+		if done != calls || total != 4 {
+			t.Errorf("progress(%v, %v), want (%v, 4)", done, total, calls)
+		}
+	})
+
+	if !slices.Equal(finished, []bool{true, true, true, true}) {
+		t.Errorf("finished = %v, want all", finished)
+	}
+	if maxInFlight != 2 {
+		t.Errorf("max concurrent calls = %v, want 2", maxInFlight)
+	}
+	if started[0] != 2 && started[1] != 2 {
+		t.Errorf("start order = %v, want index 2 first", started)
+	}
+}
+
+func TestParallel_Cancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	finished := Parallel(ctx, 1, Sequence(3), func(i int) {
+		if i == 0 {
+			cancel()
+		}
+	}, nil)
+
+	if slices.Contains(finished, true) {
+		t.Errorf("finished = %v, want none after cancelling during the first call", finished)
+	}
+}
+
+func TestSequence(t *testing.T) {
+	if got := Sequence(3); !slices.Equal(got, []int{0, 1, 2}) {
+		t.Errorf("Sequence(3) = %v, want [0 1 2]", got)
+	}
+	if got := Sequence(0); len(got) != 0 {
+		t.Errorf("Sequence(0) = %v, want empty", got)
+	}
+}
@@ -0,0 +1,110 @@
+package fulltext
+
+import (
+	"crypto/sha256"
+	"encoding/gob"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/lelopez-io/moxli/internal/bookmark"
+)
+
+// formatVersion is bumped whenever indexing changes, so cached indexes built
+// by an older moxli are rebuilt
+const formatVersion = 2
+
+// LoadOrBuild returns the index of bookmarks loaded from file. A cached index
+// in dir keyed by the file's path and the hash of its contents is used when it
+// matches the bookmarks; otherwise the index is built and cached, replacing
+// the indexes of the file's earlier contents. The index is returned even when
+// caching fails.
+func LoadOrBuild(dir, file string, bookmarks []*bookmark.Bookmark) (*Index, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return Build(bookmarks), fmt.Errorf("failed to read %s: %w", file, err)
+	}
+	prefix := filePrefix(file)
+	sum := sha256.Sum256(data)
+	path := filepath.Join(dir, prefix+hex.EncodeToString(sum[:])+".gob")
+
+	if ix, err := load(path); err == nil && ix.Version == formatVersion && ix.Matches(bookmarks) {
+		return ix, nil
+	}
+
+	ix := Build(bookmarks)
+	if err := save(path, ix); err != nil {
+		return ix, err
+	}
+	prune(dir, prefix, path)
+	return ix, nil
+}
+
+// filePrefix starts the names of the cached indexes of a file
+func filePrefix(file string) string {
+	if abs, err := filepath.Abs(file); err == nil {
+		file = abs
+	}
+	sum := sha256.Sum256([]byte(file))
+	return hex.EncodeToString(sum[:8]) + "-"
+}
+
+// prune removes the file's indexes other than keep, and indexes cached by
+// moxli versions that didn't name them after their file
+func prune(dir, prefix, keep string) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || filepath.Ext(name) != ".gob" || filepath.Join(dir, name) == keep {
+			continue
+		}
+		if strings.HasPrefix(name, prefix) || !strings.Contains(name, "-") {
+			os.Remove(filepath.Join(dir, name))
+		}
+	}
+}
+
+// load reads a cached index
+func load(path string) (*Index, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var ix Index
+	if err := gob.NewDecoder(f).Decode(&ix); err != nil {
+		return nil, err
+	}
+	return &ix, nil
+}
+
+// save writes an index to path, replacing any previous one atomically
+func save(path string, ix *Index) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create index directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".index-*")
+	if err != nil {
+		return fmt.Errorf("failed to write index: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := gob.NewEncoder(tmp).Encode(ix); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write index: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write index: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to write index: %w", err)
+	}
+	return nil
+}
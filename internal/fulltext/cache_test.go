@@ -0,0 +1,107 @@
+package fulltext
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/lelopez-io/moxli/internal/bookmark"
+)
+
+func TestLoadOrBuild(t *testing.T) {
+	dir := t.TempDir()
+	cacheDir := filepath.Join(dir, "index")
+	file := filepath.Join(dir, "bookmarks.json")
+	if err := os.WriteFile(file, []byte(`[{"url":"https://a.example"}]`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	bookmarks := []*bookmark.Bookmark{{URL: "https://a.example", Title: "Cached title"}}
+
+	ix, err := LoadOrBuild(cacheDir, file, bookmarks)
+	if err != nil {
+		t.Fatalf("LoadOrBuild() error = %v", err)
+	}
+	entries, _ := os.ReadDir(cacheDir)
+	if len(entries) != 1 {
+		t.Fatalf("cache has %v files, want 1", len(entries))
+	}
+
+	cached, err := LoadOrBuild(cacheDir, file, bookmarks)
+	if err != nil {
+		t.Fatalf("LoadOrBuild() error = %v", err)
+	}
+	if len(cached.Search("cached")) != 1 || cached.AvgLength != ix.AvgLength {
+		t.Error("cached index does not match the built one")
+	}
+
+	// A changed file gets its own index, replacing the old one
+	if err := os.WriteFile(file, []byte(`[]`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if ix, _ := LoadOrBuild(cacheDir, file, nil); len(ix.Docs) != 0 {
+		t.Errorf("len(Docs) = %v, want 0 for the changed file", len(ix.Docs))
+	}
+	entries, _ = os.ReadDir(cacheDir)
+	if len(entries) != 1 {
+		t.Errorf("cache has %v files, want 1", len(entries))
+	}
+
+	// Other files keep their indexes
+	other := filepath.Join(dir, "other.json")
+	if err := os.WriteFile(other, []byte(`[]`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	LoadOrBuild(cacheDir, other, nil)
+	entries, _ = os.ReadDir(cacheDir)
+	if len(entries) != 2 {
+		t.Errorf("cache has %v files, want 2", len(entries))
+	}
+}
+
+func TestLoadOrBuild_Mismatch(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "bookmarks.json")
+	if err := os.WriteFile(file, []byte(`x`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	LoadOrBuild(dir, file, []*bookmark.Bookmark{{URL: "https://a.example", Title: "Old"}})
+	// Same file, but the caller's bookmarks differ
+	ix, err := LoadOrBuild(dir, file, []*bookmark.Bookmark{{URL: "https://a.example", Title: "New"}})
+	if err != nil {
+		t.Fatalf("LoadOrBuild() error = %v", err)
+	}
+	if len(ix.Search("new")) != 1 {
+		t.Error("LoadOrBuild() returned a stale cached index")
+	}
+}
+
+func TestLoadOrBuild_IgnoresIDs(t *testing.T) {
+	dir := t.TempDir()
+	cacheDir := filepath.Join(dir, "index")
+	file := filepath.Join(dir, "bookmarks.html")
+	if err := os.WriteFile(file, []byte(`x`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	LoadOrBuild(cacheDir, file, []*bookmark.Bookmark{{ID: "1", URL: "https://a.example"}})
+	entries, _ := os.ReadDir(cacheDir)
+	before, _ := os.Stat(filepath.Join(cacheDir, entries[0].Name()))
+
+	// A file without stored IDs gets new ones on every load
+	LoadOrBuild(cacheDir, file, []*bookmark.Bookmark{{ID: "2", URL: "https://a.example"}})
+	after, err := os.Stat(filepath.Join(cacheDir, entries[0].Name()))
+	if err != nil || !os.SameFile(before, after) {
+		t.Error("LoadOrBuild() rebuilt the index for bookmarks with new IDs")
+	}
+}
+
+func TestLoadOrBuild_MissingFile(t *testing.T) {
+	ix, err := LoadOrBuild(t.TempDir(), "/nonexistent/file.json", []*bookmark.Bookmark{{Title: "x"}})
+	if err == nil {
+		t.Error("LoadOrBuild() error = nil, want an error for a missing file")
+	}
+	if ix == nil || len(ix.Docs) != 1 {
+		t.Error("LoadOrBuild() should still return a built index")
+	}
+}
@@ -0,0 +1,188 @@
+package fulltext
+
+import (
+	"hash/fnv"
+	"math"
+	"slices"
+
+	"github.com/lelopez-io/moxli/internal/bookmark"
+)
+
+// BM25 parameters: k1 saturates term frequency, b normalizes by length
+const (
+	k1 = 1.2
+	b  = 0.75
+)
+
+// Field weights. A term in the title counts as much as three in the article
+// body, so short fields are not drowned out by long saved articles.
+const (
+	weightTitle       = 3
+	weightTags        = 2
+	weightDescription = 2
+	weightComment     = 2
+	weightArticle     = 1
+)
+
+// Doc identifies an indexed bookmark by its position in the collection
+type Doc struct {
+	URL    string  // Normalized URL, or the URL when it has none
+	Sum    uint64  // Hash of the indexed text
+	Length float64 // Weighted number of terms
+}
+
+// Posting records how often a term occurs in a document
+type Posting struct {
+	Doc  int32
+	Freq float32 // Weighted term frequency
+}
+
+// Index is an inverted index over a list of bookmarks.
+// Fields are exported so the index can be cached with encoding/gob.
+type Index struct {
+	Version   int
+	Docs      []Doc
+	Postings  map[string][]Posting
+	AvgLength float64
+}
+
+// Hit is a document matching a search, with its BM25 score
+type Hit struct {
+	Doc   int // Position of the bookmark in the indexed list
+	Score float64
+}
+
+// Build indexes the title, tags, description, comment and saved article of
+// each bookmark. The index does not follow later edits; build a new one when
+// the bookmarks change.
+func Build(bookmarks []*bookmark.Bookmark) *Index {
+	ix := &Index{
+		Version:  formatVersion,
+		Docs:     make([]Doc, len(bookmarks)),
+		Postings: map[string][]Posting{},
+	}
+
+	total := 0.0
+	freqs := map[string]float64{}
+	for i, bm := range bookmarks {
+		clear(freqs)
+		length := 0.0
+		add := func(text string, weight float64) {
+			for _, t := range Tokenize(text) {
+				freqs[t.Term] += weight
+				length += weight
+			}
+		}
+		add(bm.Title, weightTitle)
+		for _, tag := range bm.Tags {
+			for _, segment := range tag {
+				add(segment, weightTags)
+			}
+		}
+		add(bm.Description, weightDescription)
+		add(bm.Comment, weightComment)
+		add(bm.Article, weightArticle)
+
+		for term, freq := range freqs {
+			ix.Postings[term] = append(ix.Postings[term], Posting{Doc: int32(i), Freq: float32(freq)})
+		}
+		ix.Docs[i] = Doc{URL: docURL(bm), Sum: docSum(bm), Length: length}
+		total += length
+	}
+
+	if len(bookmarks) > 0 {
+		ix.AvgLength = total / float64(len(bookmarks))
+	}
+	return ix
+}
+
+// Matches reports whether the index was built from bookmarks with the same
+// URLs and text in the same order, so its document positions can be used to
+// look them up. IDs are not compared since files that don't store them get
+// new ones when loaded.
+func (ix *Index) Matches(bookmarks []*bookmark.Bookmark) bool {
+	if len(ix.Docs) != len(bookmarks) {
+		return false
+	}
+	for i, bm := range bookmarks {
+		if ix.Docs[i].URL != docURL(bm) || ix.Docs[i].Sum != docSum(bm) {
+			return false
+		}
+	}
+	return true
+}
+
+// docSum hashes the text Build indexes for a bookmark
+func docSum(bm *bookmark.Bookmark) uint64 {
+	h := fnv.New64a()
+	write := func(text string) {
+		h.Write([]byte(text))
+		h.Write([]byte{0})
+	}
+	write(bm.Title)
+	for _, tag := range bm.Tags {
+		for _, segment := range tag {
+			write(segment)
+		}
+		write("/")
+	}
+	write(bm.Description)
+	write(bm.Comment)
+	write(bm.Article)
+	return h.Sum64()
+}
+
+// docURL returns the URL a document is matched by
+func docURL(bm *bookmark.Bookmark) string {
+	if bm.NormalizedURL != "" {
+		return bm.NormalizedURL
+	}
+	return bm.URL
+}
+
+// Search ranks the documents containing any term of query with BM25, best
+// first. Ties keep collection order.
+func (ix *Index) Search(query string) []Hit {
+	terms := QueryTerms(query)
+	if len(terms) == 0 || ix.AvgLength == 0 {
+		return nil
+	}
+
+	n := float64(len(ix.Docs))
+	scores := map[int32]float64{}
+	for _, term := range terms {
+		postings := ix.Postings[term]
+		if len(postings) == 0 {
+			continue
+		}
+		df := float64(len(postings))
+		idf := math.Log(1 + (n-df+0.5)/(df+0.5))
+		for _, p := range postings {
+			tf := float64(p.Freq)
+			norm := 1 - b + b*ix.Docs[p.Doc].Length/ix.AvgLength
+			scores[p.Doc] += idf * tf * (k1 + 1) / (tf + k1*norm)
+		}
+	}
+
+	hits := make([]Hit, 0, len(scores))
+	for doc, score := range scores {
+		hits = append(hits, Hit{Doc: int(doc), Score: score})
+	}
+	slices.SortFunc(hits, func(x, y Hit) int {
+		if x.Score != y.Score {
+			if x.Score > y.Score {
+				return -1
+			}
+			return 1
+		}
+		return x.Doc - y.Doc
+	})
+	return hits
+}
+
+// QueryTerms returns the distinct terms of a search query
+func QueryTerms(query string) []string {
+	terms := Terms(query)
+	slices.Sort(terms)
+	return slices.Compact(terms)
+}
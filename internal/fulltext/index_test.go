@@ -0,0 +1,109 @@
+package fulltext
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/lelopez-io/moxli/internal/bookmark"
+)
+
+func TestIndex_Search(t *testing.T) {
+	bookmarks := []*bookmark.Bookmark{
+		{ID: "1", Title: "Cooking pasta", Article: "Boil water, add salt and cook the pasta."},
+		{ID: "2", Title: "Writing Kubernetes operators", Description: "A guide to controllers"},
+		{ID: "3", Title: "Cluster notes", Article: strings.Repeat("Notes about running clusters. ", 20) + "Operators reconcile state."},
+		{ID: "4", Title: "Unrelated", Tags: [][]string{{"infra", "Kubernetes"}}},
+	}
+	ix := Build(bookmarks)
+
+	hits := ix.Search("operator")
+	if len(hits) != 2 {
+		t.Fatalf("len(hits) = %v, want 2", len(hits))
+	}
+	if hits[0].Doc != 1 {
+		t.Errorf("hits[0].Doc = %v, want the title match (1)", hits[0].Doc)
+	}
+	if hits[1].Doc != 2 {
+		t.Errorf("hits[1].Doc = %v, want the article match (2)", hits[1].Doc)
+	}
+	if hits[0].Score <= hits[1].Score {
+		t.Errorf("scores = %v, %v, want descending", hits[0].Score, hits[1].Score)
+	}
+
+	// Tags are indexed, and any term may match
+	if hits := ix.Search("kubernetes pasta"); len(hits) != 3 {
+		t.Errorf("Search(kubernetes pasta) = %v hits, want 3", len(hits))
+	}
+	if hits := ix.Search("the and"); hits != nil {
+		t.Errorf("Search() of stopwords = %v, want nil", hits)
+	}
+	if hits := ix.Search("missing"); len(hits) != 0 {
+		t.Errorf("Search(missing) = %v, want no hits", hits)
+	}
+}
+
+func TestIndex_Search_RareTermsWeighMore(t *testing.T) {
+	bookmarks := make([]*bookmark.Bookmark, 10)
+	for i := range bookmarks {
+		bookmarks[i] = &bookmark.Bookmark{Title: fmt.Sprintf("golang tip %d", i)}
+	}
+	bookmarks[3].Title = "golang generics"
+	bookmarks[7].Title = "generics golang golang"
+	ix := Build(bookmarks)
+
+	hits := ix.Search("golang generics")
+	if len(hits) != 10 {
+		t.Fatalf("len(hits) = %v, want 10", len(hits))
+	}
+	if hits[0].Doc != 3 || hits[1].Doc != 7 {
+		t.Errorf("top hits = %v, %v, want 3 then 7", hits[0].Doc, hits[1].Doc)
+	}
+}
+
+func TestIndex_Matches(t *testing.T) {
+	bookmarks := []*bookmark.Bookmark{
+		{ID: "1", URL: "https://a.example"},
+		{ID: "2", URL: "https://b.example"},
+	}
+	ix := Build(bookmarks)
+
+	if !ix.Matches(bookmarks) {
+		t.Error("Matches() = false for the indexed bookmarks")
+	}
+	if ix.Matches(bookmarks[:1]) {
+		t.Error("Matches() = true for fewer bookmarks")
+	}
+	swapped := []*bookmark.Bookmark{bookmarks[1], bookmarks[0]}
+	if ix.Matches(swapped) {
+		t.Error("Matches() = true for reordered bookmarks")
+	}
+
+	renumbered := []*bookmark.Bookmark{{ID: "3", URL: "https://a.example"}, {ID: "4", URL: "https://b.example"}}
+	if !ix.Matches(renumbered) {
+		t.Error("Matches() = false for the same bookmarks with new IDs")
+	}
+	edited := []*bookmark.Bookmark{bookmarks[0], {ID: "2", URL: "https://b.example", Title: "Edited"}}
+	if ix.Matches(edited) {
+		t.Error("Matches() = true for an edited bookmark")
+	}
+}
+
+// BenchmarkIndex_Search measures a search over 10k bookmarks with articles
+func BenchmarkIndex_Search(b *testing.B) {
+	words := []string{"kubernetes", "golang", "rust", "security", "auth", "docs", "guide", "blog", "tutorial", "api"}
+	bookmarks := make([]*bookmark.Bookmark, 10000)
+	for i := range bookmarks {
+		w1, w2 := words[i%len(words)], words[(i/len(words))%len(words)]
+		bookmarks[i] = &bookmark.Bookmark{
+			Title:   fmt.Sprintf("%s %s article number %d", w1, w2, i),
+			Article: strings.Repeat(fmt.Sprintf("Some text about %s and %s. ", w2, w1), 50),
+		}
+	}
+	ix := Build(bookmarks)
+
+	b.ResetTimer()
+	for range b.N {
+		ix.Search("golang security guide")
+	}
+}
@@ -0,0 +1,137 @@
+package fulltext
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/lelopez-io/moxli/internal/bookmark"
+)
+
+// Snippet is an excerpt of a document around the terms of a search
+type Snippet struct {
+	Text       string
+	Highlights [][2]int // Byte ranges of Text matching a search term
+}
+
+// Mark returns the snippet text with each highlight wrapped in open and close
+func (s Snippet) Mark(open, close string) string {
+	var out strings.Builder
+	last := 0
+	for _, h := range s.Highlights {
+		out.WriteString(s.Text[last:h[0]])
+		out.WriteString(open)
+		out.WriteString(s.Text[h[0]:h[1]])
+		out.WriteString(close)
+		last = h[1]
+	}
+	out.WriteString(s.Text[last:])
+	return out.String()
+}
+
+// BookmarkSnippet extracts a snippet for a search result from the first of
+// the bookmark's article, description and comment that contains a term,
+// falling back to the start of the description or article
+func BookmarkSnippet(bm *bookmark.Bookmark, terms []string, width int) Snippet {
+	for _, text := range []string{bm.Article, bm.Description, bm.Comment} {
+		if s := MakeSnippet(text, terms, width); len(s.Highlights) > 0 {
+			return s
+		}
+	}
+	if bm.Description != "" {
+		return MakeSnippet(bm.Description, nil, width)
+	}
+	return MakeSnippet(bm.Article, nil, width)
+}
+
+// MakeSnippet extracts about width bytes of text around the densest cluster
+// of terms, collapsing whitespace and marking "…" where text was cut
+func MakeSnippet(text string, terms []string, width int) Snippet {
+	if text == "" || width <= 0 {
+		return Snippet{}
+	}
+	want := make(map[string]bool, len(terms))
+	for _, t := range terms {
+		want[t] = true
+	}
+
+	// Pick the window starting at a matching token that covers the most
+	// distinct terms, then the most matches
+	start := 0
+	if len(want) > 0 {
+		tokens := Tokenize(text)
+		bestDistinct, bestCount := 0, 0
+		for i, t := range tokens {
+			if !want[t.Term] {
+				continue
+			}
+			seen := map[string]bool{}
+			count := 0
+			for _, u := range tokens[i:] {
+				if u.End-t.Start > width {
+					break
+				}
+				if want[u.Term] {
+					seen[u.Term] = true
+					count++
+				}
+			}
+			if len(seen) > bestDistinct || (len(seen) == bestDistinct && count > bestCount) {
+				bestDistinct, bestCount = len(seen), count
+				start = t.Start
+			}
+		}
+		// Show some context before the first match
+		start = max(0, start-width/4)
+	}
+
+	end := min(len(text), start+width)
+	start, end = snapToWords(text, start, end)
+	excerpt := strings.Join(strings.Fields(text[start:end]), " ")
+
+	snippet := Snippet{}
+	if start > 0 {
+		snippet.Text = "…"
+	}
+	offset := len(snippet.Text)
+	snippet.Text += excerpt
+	if end < len(text) {
+		snippet.Text += "…"
+	}
+
+	if len(want) > 0 {
+		for _, t := range Tokenize(excerpt) {
+			if want[t.Term] {
+				snippet.Highlights = append(snippet.Highlights, [2]int{offset + t.Start, offset + t.End})
+			}
+		}
+	}
+	return snippet
+}
+
+// snapToWords moves start forward and end backward to whitespace so the
+// excerpt text[start:end] does not cut words, unless that would leave nothing
+func snapToWords(text string, start, end int) (int, int) {
+	s, e := start, end
+	if s > 0 {
+		if i := strings.IndexAny(text[s:e], " \t\n\r"); i >= 0 {
+			s += i + 1
+		}
+	}
+	if e < len(text) {
+		if i := strings.LastIndexAny(text[s:e], " \t\n\r"); i > 0 {
+			e = s + i
+		}
+	}
+	if s >= e {
+		s, e = start, end
+	}
+
+	// Text without whitespace still must not be cut inside a rune
+	for s > 0 && !utf8.RuneStart(text[s]) {
+		s--
+	}
+	for e < len(text) && !utf8.RuneStart(text[e]) {
+		e--
+	}
+	return s, e
+}
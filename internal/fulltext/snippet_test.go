@@ -0,0 +1,61 @@
+package fulltext
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/lelopez-io/moxli/internal/bookmark"
+)
+
+func TestMakeSnippet(t *testing.T) {
+	text := strings.Repeat("filler words here. ", 20) +
+		"The   reconciler\nloop of an operator watches resources. " +
+		strings.Repeat("more filler text. ", 20)
+	terms := QueryTerms("operators reconcile")
+
+	s := MakeSnippet(text, terms, 80)
+	if !strings.HasPrefix(s.Text, "…") || !strings.HasSuffix(s.Text, "…") {
+		t.Errorf("Text = %q, want … at both ends", s.Text)
+	}
+	if !strings.Contains(s.Text, "The reconciler loop of an operator") {
+		t.Errorf("Text = %q, want the matching sentence with whitespace collapsed", s.Text)
+	}
+	if got := s.Mark("[", "]"); !strings.Contains(got, "[reconciler] loop of an [operator]") {
+		t.Errorf("Mark() = %q, want both matches marked", got)
+	}
+}
+
+func TestMakeSnippet_Short(t *testing.T) {
+	s := MakeSnippet("Short text", nil, 80)
+	if s.Text != "Short text" || len(s.Highlights) != 0 {
+		t.Errorf("MakeSnippet() = %+v, want the whole text unmarked", s)
+	}
+	if s := MakeSnippet("", []string{"x"}, 80); s.Text != "" {
+		t.Errorf("MakeSnippet(empty) = %+v, want empty", s)
+	}
+}
+
+func TestMakeSnippet_NoSpaces(t *testing.T) {
+	text := strings.Repeat("日本語", 50)
+	s := MakeSnippet(text, nil, 20)
+	if !strings.HasSuffix(s.Text, "…") {
+		t.Errorf("Text = %q, want … suffix", s.Text)
+	}
+	if !strings.HasPrefix(text, strings.TrimSuffix(s.Text, "…")) {
+		t.Errorf("Text = %q, want a prefix cut on a rune boundary", s.Text)
+	}
+}
+
+func TestBookmarkSnippet(t *testing.T) {
+	bm := &bookmark.Bookmark{
+		Description: "A description",
+		Article:     "An article about testing.",
+	}
+
+	if s := BookmarkSnippet(bm, QueryTerms("tests"), 80); s.Text != "An article about testing." {
+		t.Errorf("BookmarkSnippet(tests) = %q, want the article", s.Text)
+	}
+	if s := BookmarkSnippet(bm, QueryTerms("title"), 80); s.Text != "A description" {
+		t.Errorf("BookmarkSnippet(title) = %q, want the description", s.Text)
+	}
+}
@@ -0,0 +1,88 @@
+package fulltext
+
+import "testing"
+
+func TestStem(t *testing.T) {
+	// Examples from Porter's paper
+	tests := map[string]string{
+		"caresses":        "caress",
+		"ponies":          "poni",
+		"ties":            "ti",
+		"caress":          "caress",
+		"cats":            "cat",
+		"feed":            "feed",
+		"agreed":          "agre",
+		"plastered":       "plaster",
+		"bled":            "bled",
+		"motoring":        "motor",
+		"sing":            "sing",
+		"conflated":       "conflat",
+		"troubled":        "troubl",
+		"sized":           "size",
+		"hopping":         "hop",
+		"tanned":          "tan",
+		"falling":         "fall",
+		"hissing":         "hiss",
+		"fizzed":          "fizz",
+		"failing":         "fail",
+		"filing":          "file",
+		"happy":           "happi",
+		"sky":             "sky",
+		"relational":      "relat",
+		"conditional":     "condit",
+		"rational":        "ration",
+		"digitizer":       "digit",
+		"vietnamization":  "vietnam",
+		"predication":     "predic",
+		"operator":        "oper",
+		"feudalism":       "feudal",
+		"decisiveness":    "decis",
+		"hopefulness":     "hope",
+		"callousness":     "callous",
+		"formaliti":       "formal",
+		"sensitiviti":     "sensit",
+		"sensibiliti":     "sensibl",
+		"triplicate":      "triplic",
+		"formative":       "form",
+		"formalize":       "formal",
+		"electriciti":     "electr",
+		"electrical":      "electr",
+		"hopeful":         "hope",
+		"goodness":        "good",
+		"revival":         "reviv",
+		"allowance":       "allow",
+		"inference":       "infer",
+		"airliner":        "airlin",
+		"gyroscopic":      "gyroscop",
+		"adjustable":      "adjust",
+		"defensible":      "defens",
+		"irritant":        "irrit",
+		"replacement":     "replac",
+		"adjustment":      "adjust",
+		"dependent":       "depend",
+		"adoption":        "adopt",
+		"homologous":      "homolog",
+		"communism":       "commun",
+		"activate":        "activ",
+		"angulariti":      "angular",
+		"effective":       "effect",
+		"bowdlerize":      "bowdler",
+		"probate":         "probat",
+		"rate":            "rate",
+		"cease":           "ceas",
+		"controll":        "control",
+		"roll":            "roll",
+		"generalizations": "gener",
+		"oscillators":     "oscil",
+		// Left alone
+		"go":    "go",
+		"k8s":   "k8s",
+		"naïve": "naïve",
+	}
+
+	for word, want := range tests {
+		if got := Stem(word); got != want {
+			t.Errorf("Stem(%q) = %q, want %q", word, got, want)
+		}
+	}
+}
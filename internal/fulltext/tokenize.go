@@ -0,0 +1,99 @@
+// Package fulltext implements a full-text search index over bookmarks:
+// English tokenization with Porter stemming, an inverted index ranked with
+// BM25, snippet extraction and an on-disk cache keyed by file hash.
+package fulltext
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Token is a term found in a text, with the byte offsets of the word it was
+// derived from
+type Token struct {
+	Term       string
+	Start, End int
+}
+
+// Tokenize splits text into lowercase words, drops stopwords and stems each
+// remaining word. Words are runs of letters and digits; an apostrophe inside
+// a word ("don't") is kept with it.
+func Tokenize(text string) []Token {
+	tokens := []Token{}
+	start := -1
+	for i, r := range text {
+		if isWordRune(r) || (r == '\'' && start >= 0 && nextIsWordRune(text, i+1)) {
+			if start < 0 {
+				start = i
+			}
+			continue
+		}
+		if start >= 0 {
+			tokens = appendToken(tokens, text, start, i)
+			start = -1
+		}
+	}
+	if start >= 0 {
+		tokens = appendToken(tokens, text, start, len(text))
+	}
+	return tokens
+}
+
+// Terms returns the terms of text in order, as used for indexing and queries
+func Terms(text string) []string {
+	tokens := Tokenize(text)
+	terms := make([]string, len(tokens))
+	for i, t := range tokens {
+		terms[i] = t.Term
+	}
+	return terms
+}
+
+// appendToken normalizes text[start:end] and appends it unless it is a
+// stopword
+func appendToken(tokens []Token, text string, start, end int) []Token {
+	word := strings.ToLower(text[start:end])
+	if i := strings.IndexByte(word, '\''); i >= 0 {
+		// Possessives and contractions index under their stem: "go's" → "go"
+		word = word[:i]
+	}
+	if word == "" || stopwords[word] {
+		return tokens
+	}
+	return append(tokens, Token{Term: Stem(word), Start: start, End: end})
+}
+
+// isWordRune reports whether r is part of a word
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// nextIsWordRune reports whether the rune starting at text[i] is part of a word
+func nextIsWordRune(text string, i int) bool {
+	if i >= len(text) {
+		return false
+	}
+	r, _ := utf8.DecodeRuneInString(text[i:])
+	return isWordRune(r)
+}
+
+// stopwords are common English words left out of the index
+var stopwords = map[string]bool{
+	"a": true, "about": true, "after": true, "all": true, "also": true,
+	"an": true, "and": true, "any": true, "are": true, "as": true,
+	"at": true, "be": true, "been": true, "but": true, "by": true,
+	"can": true, "could": true, "did": true, "do": true, "does": true,
+	"for": true, "from": true, "had": true, "has": true, "have": true,
+	"he": true, "her": true, "his": true, "how": true, "i": true,
+	"if": true, "in": true, "into": true, "is": true, "it": true,
+	"its": true, "just": true, "more": true, "most": true, "my": true,
+	"no": true, "not": true, "of": true, "on": true, "or": true,
+	"our": true, "out": true, "she": true, "so": true, "some": true,
+	"such": true, "than": true, "that": true, "the": true, "their": true,
+	"them": true, "then": true, "there": true, "these": true, "they": true,
+	"this": true, "those": true, "to": true, "up": true, "us": true,
+	"was": true, "we": true, "were": true, "what": true, "when": true,
+	"where": true, "which": true, "while": true, "who": true, "why": true,
+	"will": true, "with": true, "would": true, "you": true, "your": true,
+}
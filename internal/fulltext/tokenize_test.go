@@ -0,0 +1,47 @@
+package fulltext
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestTerms(t *testing.T) {
+	tests := []struct {
+		text string
+		want []string
+	}{
+		{"", []string{}},
+		{"The Kubernetes operators", []string{"kubernet", "oper"}},
+		{"Go's concurrency: don't communicate by sharing memory!", []string{"go", "concurr", "don", "commun", "share", "memori"}},
+		{"OAuth2-PKCE flow", []string{"oauth2", "pkce", "flow"}},
+		{"Überblick über Äpfel", []string{"überblick", "über", "äpfel"}},
+		{"it's 'quoted'", []string{"quot"}},
+	}
+
+	for _, tt := range tests {
+		if got := Terms(tt.text); !slices.Equal(got, tt.want) {
+			t.Errorf("Terms(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestTokenize_Offsets(t *testing.T) {
+	text := "Running  über tests"
+	tokens := Tokenize(text)
+	if len(tokens) != 3 {
+		t.Fatalf("len(tokens) = %v, want 3", len(tokens))
+	}
+	if got := text[tokens[1].Start:tokens[1].End]; got != "über" {
+		t.Errorf("tokens[1] covers %q, want über", got)
+	}
+	if tokens[0].Term != "run" {
+		t.Errorf("tokens[0].Term = %q, want run", tokens[0].Term)
+	}
+}
+
+func TestQueryTerms(t *testing.T) {
+	got := QueryTerms("testing the tests")
+	if !slices.Equal(got, []string{"test"}) {
+		t.Errorf("QueryTerms() = %q, want [test]", got)
+	}
+}
@@ -0,0 +1,138 @@
+package fuzzy
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/lelopez-io/moxli/internal/bookmark"
+)
+
+// titleBoost multiplies scores of matches in the title, so a bookmark whose
+// title matches ranks above one that only matches in its URL or tags
+const titleBoost = 2
+
+// textDivisor divides the score of words found in the description, comment,
+// keyword or folder, so those rank below matches in the other fields
+const textDivisor = 2
+
+// Result is a bookmark matching a fuzzy search
+type Result struct {
+	Bookmark       *bookmark.Bookmark
+	Score          int
+	TitlePositions []int // Rune positions in the title to highlight
+}
+
+// field is a searchable string with its lowercase form precomputed
+type field struct {
+	text, lower []rune
+}
+
+func newField(s string) field {
+	text := []rune(s)
+	return field{text: text, lower: Lower(text)}
+}
+
+// entry holds the searchable fields of one bookmark
+type entry struct {
+	bookmark *bookmark.Bookmark
+	title    field
+	url      field
+	tags     field
+	text     []rune // Lowercase description, comment, keyword and folder
+}
+
+// Index is a fuzzy search index over a list of bookmarks.
+// Lowercased runes are computed once so each search only scans.
+type Index struct {
+	entries []entry
+}
+
+// NewIndex builds an index over bookmarks. The index does not follow later
+// edits; build a new one when the bookmarks change.
+func NewIndex(bookmarks []*bookmark.Bookmark) *Index {
+	entries := make([]entry, len(bookmarks))
+	for i, b := range bookmarks {
+		entries[i] = entry{
+			bookmark: b,
+			title:    newField(b.Title),
+			url:      newField(b.URL),
+			tags:     newField(bookmark.FormatTags(b.Tags)),
+			text:     Lower([]rune(strings.Join([]string{b.Description, b.Comment, b.Keyword, strings.Join(b.Folder, "/")}, "\n"))),
+		}
+	}
+	return &Index{entries: entries}
+}
+
+// Search returns bookmarks matching every space-separated word of pattern,
+// best first. Each word is fuzzy-matched against the title, URL and tags,
+// keeping the best field; title scores are boosted. A word that appears as is
+// in the description, comment, keyword or folder also matches, with a lower
+// score, since fuzzy matching long text would match nearly anything. Ties
+// keep collection order.
+func (ix *Index) Search(pattern string) []Result {
+	words := strings.Fields(pattern)
+	if len(words) == 0 {
+		return nil
+	}
+	needles := make([][]rune, len(words))
+	for i, w := range words {
+		needles[i] = Lower([]rune(w))
+	}
+
+	results := []Result{}
+	for i := range ix.entries {
+		if result, ok := ix.entries[i].match(needles); ok {
+			results = append(results, result)
+		}
+	}
+
+	slices.SortStableFunc(results, func(a, b Result) int {
+		return b.Score - a.Score
+	})
+	return results
+}
+
+// match scores every needle against the entry; all needles must match
+func (e *entry) match(needles [][]rune) (Result, bool) {
+	result := Result{Bookmark: e.bookmark}
+
+	for _, needle := range needles {
+		best, found := 0, false
+		var titlePositions []int
+
+		if score, positions, ok := Match(needle, e.title.text, e.title.lower); ok {
+			best, found = score*titleBoost, true
+			titlePositions = positions
+		}
+		for _, f := range []*field{&e.url, &e.tags} {
+			if score, _, ok := Match(needle, f.text, f.lower); ok && (!found || score > best) {
+				best, found = score, true
+			}
+		}
+		if !found && containsRunes(e.text, needle) {
+			best, found = scoreMatch*len(needle)/textDivisor, true
+		}
+		if !found {
+			return Result{}, false
+		}
+
+		result.Score += best
+		result.TitlePositions = append(result.TitlePositions, titlePositions...)
+	}
+
+	if len(needles) > 1 {
+		slices.Sort(result.TitlePositions)
+		result.TitlePositions = slices.Compact(result.TitlePositions)
+	}
+	return result, true
+}
+
+// containsRunes reports whether needle appears in text
+func containsRunes(text, needle []rune) bool {
+	for i := 0; i+len(needle) <= len(text); i++ {
+		if slices.Equal(text[i:i+len(needle)], needle) {
+			return true
+		}
+	}
+	return false
+}
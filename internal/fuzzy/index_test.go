@@ -0,0 +1,97 @@
+package fuzzy
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/lelopez-io/moxli/internal/bookmark"
+)
+
+func TestIndex_Search(t *testing.T) {
+	bookmarks := []*bookmark.Bookmark{
+		{Title: "Kubernetes docs", URL: "https://kubernetes.io/docs"},
+		{Title: "Random page", URL: "https://example.com/kube-tips"},
+		{Title: "Go blog", URL: "https://go.dev/blog", Tags: [][]string{{"golang"}}},
+		{Title: "Untagged", URL: "https://example.com/x", Tags: [][]string{{"infra", "kubernetes"}}},
+	}
+	ix := NewIndex(bookmarks)
+
+	results := ix.Search("kube")
+	if len(results) != 3 {
+		t.Fatalf("len(results) = %v, want 3", len(results))
+	}
+	if results[0].Bookmark != bookmarks[0] {
+		t.Errorf("results[0] = %q, want the title match first", results[0].Bookmark.Title)
+	}
+	if !slices.Equal(results[0].TitlePositions, []int{0, 1, 2, 3}) {
+		t.Errorf("TitlePositions = %v, want [0 1 2 3]", results[0].TitlePositions)
+	}
+	if len(results[1].TitlePositions) != 0 {
+		t.Errorf("URL-only match should not highlight the title, got %v", results[1].TitlePositions)
+	}
+}
+
+func TestIndex_Search_AllWordsMustMatch(t *testing.T) {
+	bookmarks := []*bookmark.Bookmark{
+		{Title: "Go blog", URL: "https://go.dev/blog"},
+		{Title: "Go tour", URL: "https://go.dev/tour"},
+	}
+	ix := NewIndex(bookmarks)
+
+	results := ix.Search("go tour")
+	if len(results) != 1 || results[0].Bookmark != bookmarks[1] {
+		t.Errorf("Search(go tour) = %v results, want only the tour", len(results))
+	}
+	if ix.Search("   ") != nil {
+		t.Error("Search() of a blank pattern should return nil")
+	}
+}
+
+// BenchmarkIndex_Search measures one keystroke's search over 50k bookmarks
+func BenchmarkIndex_Search(b *testing.B) {
+	words := []string{"kubernetes", "golang", "rust", "security", "auth", "docs", "guide", "blog", "tutorial", "api"}
+	bookmarks := make([]*bookmark.Bookmark, 50000)
+	for i := range bookmarks {
+		w1, w2 := words[i%len(words)], words[(i/len(words))%len(words)]
+		bookmarks[i] = &bookmark.Bookmark{
+			Title: fmt.Sprintf("%s %s article number %d", w1, w2, i),
+			URL:   fmt.Sprintf("https://example%d.com/%s/%s", i%97, w2, w1),
+			Tags:  [][]string{{w1}, {w2, "misc"}},
+		}
+	}
+	ix := NewIndex(bookmarks)
+
+	b.ResetTimer()
+	for b.Loop() {
+		ix.Search("kub doc")
+	}
+}
+
+func TestIndex_Search_Text(t *testing.T) {
+	bookmarks := []*bookmark.Bookmark{
+		{Title: "Notes", URL: "https://example.com/a", Description: "Setting up Kubernetes ingress"},
+		{Title: "Ingress guide", URL: "https://example.com/b"},
+		{Title: "Other", URL: "https://example.com/c", Comment: "read later", Folder: []string{"Infra"}},
+		{Title: "Unrelated", URL: "https://example.com/d", Description: "i n g r e s s"},
+	}
+	ix := NewIndex(bookmarks)
+
+	results := ix.Search("ingress")
+	if len(results) != 2 {
+		t.Fatalf("len(results) = %v, want 2", len(results))
+	}
+	if results[0].Bookmark != bookmarks[1] || results[1].Bookmark != bookmarks[0] {
+		t.Errorf("results = %q, %q; want the title match before the description match",
+			results[0].Bookmark.Title, results[1].Bookmark.Title)
+	}
+	if len(results[1].TitlePositions) != 0 {
+		t.Errorf("Description-only match should not highlight the title, got %v", results[1].TitlePositions)
+	}
+
+	for _, word := range []string{"later", "infra"} {
+		if results := ix.Search(word); len(results) != 1 || results[0].Bookmark != bookmarks[2] {
+			t.Errorf("Search(%q) should find the comment and folder", word)
+		}
+	}
+}
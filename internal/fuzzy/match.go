@@ -0,0 +1,145 @@
+// Package fuzzy implements fzf-style fuzzy matching and a ranked search index
+// over bookmarks.
+package fuzzy
+
+import "unicode"
+
+// Scoring constants, modelled on fzf's v1 algorithm
+const (
+	scoreMatch        = 16
+	scoreGapStart     = -3
+	scoreGapExtension = -1
+
+	// Matching right after a separator ("/", "-", " ", ...) or at the start
+	bonusBoundary = scoreMatch / 2
+	// Matching an uppercase letter after a lowercase one (camelCase)
+	bonusCamel = bonusBoundary - 1
+	// Each match directly following the previous one
+	bonusConsecutive = -(scoreGapStart + scoreGapExtension)
+	// The first pattern character counts this many times more
+	bonusFirstCharMultiplier = 2
+)
+
+// Match fuzzy-matches a lowercase pattern against text.
+// lower must be text with every rune lowercased (see Lower), so positions in
+// it are positions in text. Returns the score and the matched rune positions,
+// or ok=false when pattern is not a subsequence of text.
+func Match(pattern, text, lower []rune) (score int, positions []int, ok bool) {
+	if len(pattern) == 0 {
+		return 0, nil, true
+	}
+
+	// Forward pass: find the earliest end of a match
+	pi := 0
+	end := -1
+	for i, r := range lower {
+		if r == pattern[pi] {
+			pi++
+			if pi == len(pattern) {
+				end = i
+				break
+			}
+		}
+	}
+	if end < 0 {
+		return 0, nil, false
+	}
+
+	// Backward pass: find the latest start, giving the shortest window
+	pi = len(pattern) - 1
+	start := end
+	for i := end; i >= 0; i-- {
+		if lower[i] == pattern[pi] {
+			pi--
+			if pi < 0 {
+				start = i
+				break
+			}
+		}
+	}
+
+	score, positions = scoreWindow(pattern, text, lower, start, end)
+	return score, positions, true
+}
+
+// scoreWindow scores the match of pattern inside text[start:end+1]
+func scoreWindow(pattern, text, lower []rune, start, end int) (int, []int) {
+	positions := make([]int, 0, len(pattern))
+	score := 0
+	pi := 0
+	inGap := false
+	consecutive := 0
+	firstBonus := 0
+
+	for i := start; i <= end; i++ {
+		if pi < len(pattern) && lower[i] == pattern[pi] {
+			score += scoreMatch
+			bonus := charBonus(text, i)
+
+			if consecutive == 0 {
+				firstBonus = bonus
+			} else {
+				// A boundary inside a consecutive run restarts the chunk
+				if bonus >= bonusBoundary && bonus > firstBonus {
+					firstBonus = bonus
+				}
+				bonus = max(bonus, firstBonus, bonusConsecutive)
+			}
+
+			if pi == 0 {
+				score += bonus * bonusFirstCharMultiplier
+			} else {
+				score += bonus
+			}
+
+			positions = append(positions, i)
+			inGap = false
+			consecutive++
+			pi++
+			continue
+		}
+
+		if inGap {
+			score += scoreGapExtension
+		} else {
+			score += scoreGapStart
+		}
+		inGap = true
+		consecutive = 0
+		firstBonus = 0
+	}
+
+	return score, positions
+}
+
+// charBonus returns the bonus for matching text[i], based on the previous rune
+func charBonus(text []rune, i int) int {
+	if i == 0 {
+		return bonusBoundary
+	}
+
+	prev, cur := text[i-1], text[i]
+	switch {
+	case !isWordRune(prev) && isWordRune(cur):
+		return bonusBoundary
+	case unicode.IsLower(prev) && unicode.IsUpper(cur):
+		return bonusCamel
+	case unicode.IsLetter(prev) && unicode.IsDigit(cur):
+		return bonusCamel
+	}
+	return 0
+}
+
+// isWordRune reports whether r is a letter or digit
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// Lower lowercases text rune by rune, keeping positions aligned with text
+func Lower(text []rune) []rune {
+	lower := make([]rune, len(text))
+	for i, r := range text {
+		lower[i] = unicode.ToLower(r)
+	}
+	return lower
+}
@@ -0,0 +1,74 @@
+package fuzzy
+
+import (
+	"slices"
+	"testing"
+)
+
+func match(pattern, text string) (int, []int, bool) {
+	t := []rune(text)
+	return Match(Lower([]rune(pattern)), t, Lower(t))
+}
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		pattern   string
+		text      string
+		ok        bool
+		positions []int
+	}{
+		{"oag", "OAuth Guide", true, []int{0, 1, 6}},
+		{"guide", "OAuth Guide", true, []int{6, 7, 8, 9, 10}},
+		{"xyz", "OAuth Guide", false, nil},
+		{"", "anything", true, nil},
+		{"ab", "a", false, nil},
+		{"ü", "Über", true, []int{0}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern+"/"+tt.text, func(t *testing.T) {
+			_, positions, ok := match(tt.pattern, tt.text)
+			if ok != tt.ok {
+				t.Fatalf("Match() ok = %v, want %v", ok, tt.ok)
+			}
+			if !slices.Equal(positions, tt.positions) {
+				t.Errorf("Match() positions = %v, want %v", positions, tt.positions)
+			}
+		})
+	}
+}
+
+func TestMatch_ShortestWindow(t *testing.T) {
+	// The backward pass should pick "abc" at the end, not span from the first "a"
+	_, positions, _ := match("abc", "a---abc")
+	if !slices.Equal(positions, []int{4, 5, 6}) {
+		t.Errorf("positions = %v, want [4 5 6]", positions)
+	}
+}
+
+func TestMatch_Ranking(t *testing.T) {
+	tests := []struct {
+		pattern       string
+		better, worse string
+	}{
+		// Consecutive beats scattered
+		{"auth", "oauth", "paxumtxhe"},
+		// Word boundary beats mid-word
+		{"go", "the go tour", "algorithms"},
+		// camelCase boundary beats mid-word
+		{"gs", "getStarted", "strings"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			better, _, ok1 := match(tt.pattern, tt.better)
+			worse, _, ok2 := match(tt.pattern, tt.worse)
+			if !ok1 || !ok2 {
+				t.Fatalf("both texts should match %q", tt.pattern)
+			}
+			if better <= worse {
+				t.Errorf("score(%q) = %v should beat score(%q) = %v", tt.better, better, tt.worse, worse)
+			}
+		})
+	}
+}
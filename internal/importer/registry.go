@@ -0,0 +1,56 @@
+package importer
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+
+	"github.com/lelopez-io/moxli/internal/bookmark"
+)
+
+// All returns every known importer in detection order.
+// Anybox HTML must be tried before Firefox since both use Netscape markers,
+// and Firefox before Safari since Safari is detected by missing timestamps.
+func All() []Importer {
+	return []Importer{
+		&AnyboxImporter{},
+		&AnyboxHTMLImporter{},
+		&FirefoxImporter{},
+		&SafariImporter{},
+	}
+}
+
+// Detect returns the first importer that recognizes the content, or nil
+func Detect(content []byte) Importer {
+	for _, imp := range All() {
+		if imp.Detect(bytes.NewReader(content)) {
+			return imp
+		}
+	}
+	return nil
+}
+
+// ForSource returns the importer with the given source identifier
+func ForSource(source string) (Importer, bool) {
+	for _, imp := range All() {
+		if imp.Source() == source {
+			return imp, true
+		}
+	}
+	return nil, false
+}
+
+// LoadFile reads a bookmark file from disk, detecting its format
+func LoadFile(path string) (*bookmark.Collection, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+
+	imp := Detect(content)
+	if imp == nil {
+		return nil, fmt.Errorf("unknown bookmark format: %s", path)
+	}
+
+	return imp.Parse(bytes.NewReader(content))
+}
@@ -0,0 +1,87 @@
+package importer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{
+			name:    "anybox json",
+			content: `[{"url": "https://example.com", "isStarred": false}]`,
+			want:    "anybox",
+		},
+		{
+			name:    "anybox html",
+			content: `<DL><DT><A HREF="https://example.com" ADD_DATE="1" TAGS="go">Go</A></DL>`,
+			want:    "anybox-html",
+		},
+		{
+			name: "firefox",
+			content: `<!DOCTYPE NETSCAPE-Bookmark-file-1>
+<DL><DT><H3>Folder</H3><DL><DT><A HREF="https://example.com" ADD_DATE="1">Example</A></DL></DL>`,
+			want: "firefox",
+		},
+		{
+			name: "safari",
+			content: `<!DOCTYPE NETSCAPE-Bookmark-file-1>
+<H1>Bookmarks</H1><DL><DT><A HREF="https://example.com">Example</A></DL>`,
+			want: "safari",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			imp := Detect([]byte(tt.content))
+			if imp == nil {
+				t.Fatal("Detect() = nil, want importer")
+			}
+			if imp.Source() != tt.want {
+				t.Errorf("Detect().Source() = %v, want %v", imp.Source(), tt.want)
+			}
+		})
+	}
+}
+
+func TestDetect_Unknown(t *testing.T) {
+	if imp := Detect([]byte("not a bookmark file")); imp != nil {
+		t.Errorf("Detect() = %v, want nil", imp.Source())
+	}
+}
+
+func TestForSource(t *testing.T) {
+	imp, ok := ForSource("firefox")
+	if !ok {
+		t.Fatal("ForSource(firefox) should exist")
+	}
+	if _, isFirefox := imp.(*FirefoxImporter); !isFirefox {
+		t.Errorf("ForSource(firefox) = %T, want *FirefoxImporter", imp)
+	}
+
+	if _, ok := ForSource("netscape"); ok {
+		t.Error("ForSource(netscape) should not exist")
+	}
+}
+
+func TestLoadFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "bookmarks.json")
+	content := `[{"url": "https://example.com", "title": "Example", "tags": []}]`
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	collection, err := LoadFile(path)
+	if err != nil {
+		t.Fatalf("LoadFile() error = %v", err)
+	}
+
+	if len(collection.Bookmarks) != 1 {
+		t.Errorf("len(Bookmarks) = %v, want 1", len(collection.Bookmarks))
+	}
+}
@@ -0,0 +1,343 @@
+// Package linkcheck finds dead and redirected bookmark links. Requests run
+// on a bounded pool of workers, spaced out per host, with a timeout and
+// retries for failures that may be temporary. Redirects are followed one
+// at a time so the whole chain is recorded.
+package linkcheck
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lelopez-io/moxli/internal/bookmark"
+	"github.com/lelopez-io/moxli/internal/fetch"
+)
+
+// UserAgent identifies link check requests to servers
+const UserAgent = "moxli-linkcheck/1.0 (+https://github.com/lelopez-io/moxli)"
+
+const (
+	// maxRetryAfter caps the wait a server can ask for with Retry-After
+	maxRetryAfter = time.Minute
+	// maxDrain is how much of a GET response body is read so the
+	// connection can be reused; the rest is discarded with the connection
+	maxDrain = 64 << 10
+)
+
+// Options configures a Checker
+type Options struct {
+	Workers      int           // Concurrent requests
+	Timeout      time.Duration // Limit for each request, redirects included
+	Retries      int           // Extra attempts after a network error, 429 or 5xx
+	RetryDelay   time.Duration // Wait before the first retry, doubled for each further one
+	HostDelay    time.Duration // Minimum time between requests to the same host
+	MaxRedirects int           // Longest redirect chain followed (0 uses DefaultMaxRedirects)
+}
+
+// DefaultMaxRedirects is the longest redirect chain followed by default
+const DefaultMaxRedirects = 10
+
+// DefaultOptions returns the options used by `moxli check`
+func DefaultOptions() Options {
+	return Options{
+		Workers:      8,
+		Timeout:      15 * time.Second,
+		Retries:      2,
+		RetryDelay:   2 * time.Second,
+		HostDelay:    time.Second,
+		MaxRedirects: DefaultMaxRedirects,
+	}
+}
+
+// Checker checks URLs over HTTP
+type Checker struct {
+	opts   Options
+	client *http.Client
+	hosts  *fetch.HostLimiter
+}
+
+// New creates a Checker
+func New(opts Options) *Checker {
+	opts.Workers = max(opts.Workers, 1)
+	if opts.MaxRedirects <= 0 {
+		opts.MaxRedirects = DefaultMaxRedirects
+	}
+	return &Checker{
+		opts: opts,
+		client: &http.Client{
+			// Redirects are followed by request, one hop at a time
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		hosts: fetch.NewHostLimiter(opts.HostDelay),
+	}
+}
+
+// Result is the link status found for one bookmark
+type Result struct {
+	Bookmark *bookmark.Bookmark
+	Status   bookmark.LinkStatus
+}
+
+// Checkable reports whether a URL can be checked; only http and https
+// links can
+func Checkable(rawURL string) bool {
+	u, err := url.Parse(rawURL)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
+// CheckAll checks the bookmarks' URLs on the worker pool, calling progress
+// (when not nil) from a single goroutine after each URL. Bookmarks sharing
+// a URL are checked once and bookmarks that aren't Checkable are skipped.
+// Results follow the order of bookmarks; when ctx is cancelled only the
+// finished checks are returned. The bookmarks are not modified.
+func (c *Checker) CheckAll(ctx context.Context, bookmarks []*bookmark.Bookmark, progress func(done, total int)) []Result {
+	var urls []string
+	byURL := make(map[string][]int)
+	for i, b := range bookmarks {
+		if !Checkable(b.URL) {
+			continue
+		}
+		if _, seen := byURL[b.URL]; !seen {
+			urls = append(urls, b.URL)
+		}
+		byURL[b.URL] = append(byURL[b.URL], i)
+	}
+
+	statuses := make([]bookmark.LinkStatus, len(urls))
+	finished := fetch.Parallel(ctx, c.opts.Workers, interleaveHosts(urls), func(i int) {
+		statuses[i] = c.Check(ctx, urls[i])
+	}, progress)
+
+	status := make(map[int]bookmark.LinkStatus, len(bookmarks))
+	for i, u := range urls {
+		if finished[i] {
+			for _, b := range byURL[u] {
+				status[b] = statuses[i]
+			}
+		}
+	}
+	results := make([]Result, 0, len(status))
+	for i, b := range bookmarks {
+		if s, ok := status[i]; ok {
+			results = append(results, Result{Bookmark: b, Status: s})
+		}
+	}
+	return results
+}
+
+// interleaveHosts orders URL indexes round-robin by host, so the workers
+// spread over many servers instead of queueing on one
+func interleaveHosts(urls []string) []int {
+	var hosts []string
+	byHost := make(map[string][]int)
+	for i, u := range urls {
+		h := hostOf(u)
+		if _, seen := byHost[h]; !seen {
+			hosts = append(hosts, h)
+		}
+		byHost[h] = append(byHost[h], i)
+	}
+
+	order := make([]int, 0, len(urls))
+	for round := 0; len(order) < len(urls); round++ {
+		for _, h := range hosts {
+			if round < len(byHost[h]) {
+				order = append(order, byHost[h][round])
+			}
+		}
+	}
+	return order
+}
+
+// hostOf returns the lowercase host and port of a URL
+func hostOf(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+	return strings.ToLower(u.Host)
+}
+
+// Check requests a URL, retrying failures that may be temporary
+func (c *Checker) Check(ctx context.Context, rawURL string) bookmark.LinkStatus {
+	delay := c.opts.RetryDelay
+
+	var o outcome
+	for attempt := 0; ; attempt++ {
+		o = c.attempt(ctx, rawURL)
+		if !o.retry || attempt >= c.opts.Retries || ctx.Err() != nil {
+			break
+		}
+
+		wait := delay
+		if o.retryAfter > 0 {
+			// The server asked every client to back off, so hold the host
+			c.hosts.Pause(o.host, o.retryAfter)
+			wait = max(wait, o.retryAfter)
+		}
+		if fetch.Sleep(ctx, wait) != nil {
+			break
+		}
+		delay *= 2
+	}
+
+	o.status.CheckedAt = time.Now()
+	return o.status
+}
+
+// outcome is the result of one attempt at a URL
+type outcome struct {
+	status     bookmark.LinkStatus
+	host       string        // Host of the last request
+	retry      bool          // The failure may be temporary
+	retryAfter time.Duration // Wait asked for by the server
+}
+
+// attempt requests the URL with HEAD, falling back to GET for servers that
+// reject or mishandle HEAD
+func (c *Checker) attempt(ctx context.Context, rawURL string) outcome {
+	o := c.follow(ctx, http.MethodHead, rawURL)
+	if code := o.status.StatusCode; code >= 400 && code != http.StatusTooManyRequests {
+		return c.follow(ctx, http.MethodGet, rawURL)
+	}
+	return o
+}
+
+// follow requests the URL and the redirects it leads to, up to
+// MaxRedirects, within one timeout
+func (c *Checker) follow(ctx context.Context, method, rawURL string) outcome {
+	if c.opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.opts.Timeout)
+		defer cancel()
+	}
+
+	var o outcome
+	seen := make(map[string]bool)
+	current := rawURL
+	for {
+		seen[current] = true
+		o.host = hostOf(current)
+		resp, err := c.request(ctx, o.host, method, current)
+		if err != nil {
+			o.status.Error = errorMessage(err)
+			o.retry = temporary(err)
+			return o
+		}
+
+		next := redirectTarget(resp)
+		if next == "" {
+			o.status.StatusCode = resp.StatusCode
+			if current != rawURL {
+				o.status.FinalURL = current
+			}
+			if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500 {
+				o.retry = true
+				o.retryAfter = retryAfter(resp.Header.Get("Retry-After"), time.Now())
+			}
+			return o
+		}
+
+		o.status.Redirects = append(o.status.Redirects, bookmark.Redirect{URL: current, StatusCode: resp.StatusCode})
+		switch {
+		case seen[next]:
+			o.status.Error = "redirect loop at " + next
+			return o
+		case len(o.status.Redirects) > c.opts.MaxRedirects:
+			o.status.Error = fmt.Sprintf("more than %d redirects", c.opts.MaxRedirects)
+			return o
+		}
+		current = next
+	}
+}
+
+// request sends one request without following redirects. The body is
+// drained and closed; only the status and headers are kept.
+func (c *Checker) request(ctx context.Context, host, method, rawURL string) (*http.Response, error) {
+	if err := c.hosts.Wait(ctx, host); err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", UserAgent)
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	_, _ = io.CopyN(io.Discard, resp.Body, maxDrain)
+	resp.Body.Close()
+	return resp, nil
+}
+
+// redirectTarget returns the absolute URL a redirect response points to,
+// or "" when the response is not a redirect
+func redirectTarget(resp *http.Response) string {
+	switch resp.StatusCode {
+	case http.StatusMovedPermanently, http.StatusFound, http.StatusSeeOther,
+		http.StatusTemporaryRedirect, http.StatusPermanentRedirect:
+	default:
+		return ""
+	}
+
+	target, err := resp.Location()
+	if err != nil {
+		return ""
+	}
+	return target.String()
+}
+
+// errorMessage describes a failed request without repeating the URL
+func errorMessage(err error) string {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		if urlErr.Timeout() {
+			return "timeout"
+		}
+		return urlErr.Err.Error()
+	}
+	return err.Error()
+}
+
+// temporary reports whether a failed request is worth retrying. Unknown
+// hosts and invalid certificates won't fix themselves within a check.
+func temporary(err error) bool {
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) && dnsErr.IsNotFound {
+		return false
+	}
+	var certErr *tls.CertificateVerificationError
+	if errors.As(err, &certErr) {
+		return false
+	}
+	return !errors.Is(err, context.Canceled)
+}
+
+// retryAfter parses a Retry-After header given in seconds or as an HTTP
+// date, capped at maxRetryAfter
+func retryAfter(header string, now time.Time) time.Duration {
+	if header == "" {
+		return 0
+	}
+
+	var d time.Duration
+	if seconds, err := strconv.Atoi(header); err == nil {
+		d = time.Duration(seconds) * time.Second
+	} else if t, err := http.ParseTime(header); err == nil {
+		d = t.Sub(now)
+	}
+	return min(max(d, 0), maxRetryAfter)
+}
@@ -0,0 +1,317 @@
+package linkcheck
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/lelopez-io/moxli/internal/bookmark"
+)
+
+// testOptions checks quickly, without politeness delays
+func testOptions() Options {
+	return Options{Workers: 4, Timeout: time.Second, Retries: 1, RetryDelay: time.Millisecond}
+}
+
+func newTestServer(t *testing.T) *httptest.Server {
+	var flaky atomic.Int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("/ok", func(w http.ResponseWriter, r *http.Request) {})
+	mux.HandleFunc("/missing", http.NotFound)
+	mux.HandleFunc("/moved", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/ok", http.StatusMovedPermanently)
+	})
+	mux.HandleFunc("/gone", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/missing", http.StatusFound)
+	})
+	mux.HandleFunc("/old", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/moved", http.StatusPermanentRedirect)
+	})
+	mux.HandleFunc("/temporary", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/ok", http.StatusFound)
+	})
+	mux.HandleFunc("/loop", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/loop-back", http.StatusMovedPermanently)
+	})
+	mux.HandleFunc("/loop-back", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/loop", http.StatusMovedPermanently)
+	})
+	mux.HandleFunc("/hop/{n}", func(w http.ResponseWriter, r *http.Request) {
+		n, _ := strconv.Atoi(r.PathValue("n"))
+		if n > 0 {
+			http.Redirect(w, r, fmt.Sprintf("/hop/%d", n-1), http.StatusMovedPermanently)
+		}
+	})
+	mux.HandleFunc("/no-head", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodHead {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	})
+	mux.HandleFunc("/flaky", func(w http.ResponseWriter, r *http.Request) {
+		// Fails for the HEAD and GET of the first attempt
+		if flaky.Add(1) <= 2 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	})
+	mux.HandleFunc("/down", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	})
+	mux.HandleFunc("/slow", func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+	})
+	mux.HandleFunc("/agent", func(w http.ResponseWriter, r *http.Request) {
+		if r.UserAgent() != UserAgent {
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	})
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestChecker_Check(t *testing.T) {
+	server := newTestServer(t)
+
+	tests := []struct {
+		path      string
+		status    int
+		finalPath string
+		dead      bool
+	}{
+		{"/ok", 200, "", false},
+		{"/missing", 404, "", true},
+		{"/moved", 200, "/ok", false},
+		{"/gone", 404, "/missing", true},
+		{"/no-head", 200, "", false},
+		{"/flaky", 200, "", false},
+		{"/down", 502, "", true},
+		{"/agent", 200, "", false},
+	}
+
+	checker := New(testOptions())
+	for _, tt := range tests {
+		status := checker.Check(context.Background(), server.URL+tt.path)
+
+		if status.StatusCode != tt.status {
+			t.Errorf("%s: StatusCode = %v, want %v (error %q)", tt.path, status.StatusCode, tt.status, status.Error)
+		}
+		wantFinal := ""
+		if tt.finalPath != "" {
+			wantFinal = server.URL + tt.finalPath
+		}
+		if status.FinalURL != wantFinal {
+			t.Errorf("%s: FinalURL = %q, want %q", tt.path, status.FinalURL, wantFinal)
+		}
+		if status.Dead() != tt.dead {
+			t.Errorf("%s: Dead() = %v, want %v", tt.path, status.Dead(), tt.dead)
+		}
+		if status.CheckedAt.IsZero() {
+			t.Errorf("%s: CheckedAt not set", tt.path)
+		}
+	}
+}
+
+func TestChecker_Check_Redirects(t *testing.T) {
+	server := newTestServer(t)
+
+	tests := []struct {
+		path      string
+		chain     []bookmark.Redirect
+		permanent string
+		err       string
+	}{
+		{"/ok", nil, "", ""},
+		{"/old", []bookmark.Redirect{{URL: "/old", StatusCode: 308}, {URL: "/moved", StatusCode: 301}}, "/ok", ""},
+		{"/temporary", []bookmark.Redirect{{URL: "/temporary", StatusCode: 302}}, "", ""},
+		{"/loop", []bookmark.Redirect{{URL: "/loop", StatusCode: 301}, {URL: "/loop-back", StatusCode: 301}}, "", "redirect loop at " + server.URL + "/loop"},
+		{"/hop/3", []bookmark.Redirect{{URL: "/hop/3", StatusCode: 301}, {URL: "/hop/2", StatusCode: 301}, {URL: "/hop/1", StatusCode: 301}}, "/hop/0", ""},
+		{"/hop/5", []bookmark.Redirect{{URL: "/hop/5", StatusCode: 301}, {URL: "/hop/4", StatusCode: 301}, {URL: "/hop/3", StatusCode: 301}, {URL: "/hop/2", StatusCode: 301}}, "", "more than 3 redirects"},
+	}
+
+	opts := testOptions()
+	opts.MaxRedirects = 3
+	checker := New(opts)
+	for _, tt := range tests {
+		status := checker.Check(context.Background(), server.URL+tt.path)
+
+		var chain []bookmark.Redirect
+		for _, r := range tt.chain {
+			chain = append(chain, bookmark.Redirect{URL: server.URL + r.URL, StatusCode: r.StatusCode})
+		}
+		if !slices.Equal(status.Redirects, chain) {
+			t.Errorf("%s: Redirects = %v, want %v", tt.path, status.Redirects, chain)
+		}
+		wantPermanent := ""
+		if tt.permanent != "" {
+			wantPermanent = server.URL + tt.permanent
+		}
+		if got := status.PermanentURL(); got != wantPermanent {
+			t.Errorf("%s: PermanentURL() = %q, want %q", tt.path, got, wantPermanent)
+		}
+		if status.Error != tt.err {
+			t.Errorf("%s: Error = %q, want %q", tt.path, status.Error, tt.err)
+		}
+	}
+}
+
+func TestChecker_Check_Timeout(t *testing.T) {
+	server := newTestServer(t)
+
+	opts := testOptions()
+	opts.Timeout = 50 * time.Millisecond
+	opts.Retries = 0
+	status := New(opts).Check(context.Background(), server.URL+"/slow")
+
+	if status.Error != "timeout" || status.StatusCode != 0 {
+		t.Errorf("Check(/slow) = %+v, want a timeout error", status)
+	}
+	if !status.Dead() {
+		t.Error("a timed out link should count as dead")
+	}
+}
+
+func TestChecker_Check_Unreachable(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	url := server.URL
+	server.Close()
+
+	status := New(testOptions()).Check(context.Background(), url)
+	if status.Error == "" || !status.Dead() {
+		t.Errorf("Check(closed server) = %+v, want a connection error", status)
+	}
+}
+
+func TestChecker_CheckAll(t *testing.T) {
+	var mu sync.Mutex
+	inFlight, maxInFlight := 0, 0
+	hits := make(map[string]int)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		inFlight++
+		maxInFlight = max(maxInFlight, inFlight)
+		hits[r.URL.Path]++
+		mu.Unlock()
+
+		time.Sleep(10 * time.Millisecond)
+
+		mu.Lock()
+		inFlight--
+		mu.Unlock()
+		if r.URL.Path == "/dead" {
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	var bookmarks []*bookmark.Bookmark
+	for i := range 12 {
+		bookmarks = append(bookmarks, &bookmark.Bookmark{ID: fmt.Sprint(i), URL: fmt.Sprintf("%s/page%d", server.URL, i)})
+	}
+	bookmarks = append(bookmarks,
+		&bookmark.Bookmark{ID: "dead", URL: server.URL + "/dead"},
+		&bookmark.Bookmark{ID: "copy", URL: server.URL + "/page0"},
+		&bookmark.Bookmark{ID: "note", URL: "javascript:void(0)"},
+	)
+
+	opts := testOptions()
+	opts.Workers = 3
+	calls := 0
+	results := New(opts).CheckAll(context.Background(), bookmarks, func(done, total int) {
+		calls++
+		if done != calls || total != 13 {
+			t.Errorf("progress(%v, %v), want (%v, 13)", done, total, calls)
+		}
+	})
+
+	if len(results) != 14 {
+		t.Fatalf("len(results) = %v, want 14 (the javascript: link skipped)", len(results))
+	}
+	var ids []string
+	for _, r := range results {
+		ids = append(ids, r.Bookmark.ID)
+	}
+	if !slices.Equal(ids[12:], []string{"dead", "copy"}) || ids[0] != "0" {
+		t.Errorf("result order = %v, want the bookmark order", ids)
+	}
+	if results[12].Status.StatusCode != 404 || results[13].Status.StatusCode != 200 {
+		t.Errorf("statuses = %v, %v, want 404 and 200", results[12].Status.StatusCode, results[13].Status.StatusCode)
+	}
+	if maxInFlight > 3 {
+		t.Errorf("max concurrent requests = %v, want at most 3 workers", maxInFlight)
+	}
+	if hits["/page0"] != 1 {
+		t.Errorf("/page0 requested %v times, want once for both bookmarks", hits["/page0"])
+	}
+	if bookmarks[0].LinkStatus != nil {
+		t.Error("CheckAll should not modify the bookmarks")
+	}
+}
+
+func TestChecker_CheckAll_Cancelled(t *testing.T) {
+	server := newTestServer(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	bookmarks := []*bookmark.Bookmark{{URL: server.URL + "/ok"}, {URL: server.URL + "/missing"}}
+	if results := New(testOptions()).CheckAll(ctx, bookmarks, nil); len(results) != 0 {
+		t.Errorf("CheckAll() after cancel = %v results, want none", len(results))
+	}
+}
+
+func TestInterleaveHosts(t *testing.T) {
+	urls := []string{
+		"https://a.example/1", "https://a.example/2", "https://a.example/3",
+		"https://b.example/1", "https://B.example/2",
+		"https://c.example/1",
+	}
+	want := []int{0, 3, 5, 1, 4, 2}
+	if got := interleaveHosts(urls); !slices.Equal(got, want) {
+		t.Errorf("interleaveHosts() = %v, want %v", got, want)
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		header string
+		want   time.Duration
+	}{
+		{"", 0},
+		{"3", 3 * time.Second},
+		{"-5", 0},
+		{"3600", maxRetryAfter},
+		{"Wed, 01 Jan 2025 12:00:10 GMT", 10 * time.Second},
+		{"Wed, 01 Jan 2025 11:00:00 GMT", 0},
+		{"soon", 0},
+	}
+
+	for _, tt := range tests {
+		if got := retryAfter(tt.header, now); got != tt.want {
+			t.Errorf("retryAfter(%q) = %v, want %v", tt.header, got, tt.want)
+		}
+	}
+}
+
+func TestCheckable(t *testing.T) {
+	tests := map[string]bool{
+		"https://example.com/x": true,
+		"http://example.com":    true,
+		"javascript:void(0)":    false,
+		"file:///tmp/x.html":    false,
+		"place:sort=8":          false,
+		"https://":              false,
+	}
+	for url, want := range tests {
+		if got := Checkable(url); got != want {
+			t.Errorf("Checkable(%q) = %v, want %v", url, got, want)
+		}
+	}
+}
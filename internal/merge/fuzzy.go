@@ -0,0 +1,112 @@
+package merge
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/lelopez-io/moxli/internal/bookmark"
+)
+
+// Default confidence levels for the fuzzy matching pass
+const (
+	DefaultFuzzyThreshold = 0.85 // Auto-apply matches at or above this confidence
+	DefaultMinConfidence  = 0.5  // List matches at or above this confidence as candidates
+)
+
+// ambiguityMargin is how close the runner-up may score before a match is
+// considered ambiguous and only listed as a candidate
+const ambiguityMargin = 0.05
+
+// FuzzyOptions configures the optional second matching pass for source
+// bookmarks whose normalized URL is not in the base
+type FuzzyOptions struct {
+	Threshold     float64 // Minimum confidence to apply a match (0 uses DefaultFuzzyThreshold)
+	MinConfidence float64 // Minimum confidence to report a candidate (0 uses DefaultMinConfidence)
+}
+
+// FuzzyMatch pairs a source bookmark with the base bookmark it most likely refers to
+type FuzzyMatch struct {
+	Source     *bookmark.Bookmark
+	Base       *bookmark.Bookmark
+	Confidence float64 // 0-1, higher is more certain
+}
+
+// fuzzyMatcher scores source bookmarks against base bookmarks grouped by domain
+type fuzzyMatcher struct {
+	base     *bookmark.Collection
+	byDomain map[string][]*bookmark.Bookmark
+}
+
+func newFuzzyMatcher(base *bookmark.Collection) *fuzzyMatcher {
+	byDomain := make(map[string][]*bookmark.Bookmark)
+	for _, b := range base.Bookmarks {
+		if domain := b.Domain(); domain != "" {
+			byDomain[domain] = append(byDomain[domain], b)
+		}
+	}
+	return &fuzzyMatcher{base: base, byDomain: byDomain}
+}
+
+// bestMatch returns the most likely base bookmark for a source bookmark.
+// Confidence is computed as:
+//   - same domain, both titled: 0.6 × title similarity + 0.4 × path similarity
+//   - same domain, untitled: 0.8 × path similarity
+//   - shortlink (domain unknown): 0.75 × title similarity across the whole base
+//
+// The weights cap untitled and shortlink matches below the default threshold,
+// so those only ever surface as candidates. Ambiguous matches, where the
+// runner-up scores within ambiguityMargin, are flagged so they aren't applied.
+func (f *fuzzyMatcher) bestMatch(source *bookmark.Bookmark, target string) (match FuzzyMatch, ambiguous bool) {
+	match.Source = source
+
+	var candidates []*bookmark.Bookmark
+	shortlink := IsShortlink(target)
+	if shortlink {
+		if source.Title == "" {
+			return match, false
+		}
+		candidates = f.base.Bookmarks
+	} else {
+		// Domain the target the same way the base bookmarks were grouped
+		unwrapped := &bookmark.Bookmark{URL: target}
+		candidates = f.byDomain[unwrapped.Domain()]
+	}
+
+	runnerUp := 0.0
+	for _, candidate := range candidates {
+		var score float64
+		switch {
+		case shortlink:
+			score = 0.75 * bookmark.TitleSimilarity(source.Title, candidate.Title)
+		case source.Title != "" && candidate.Title != "":
+			score = 0.6*bookmark.TitleSimilarity(source.Title, candidate.Title) +
+				0.4*bookmark.Similarity(pathOf(target), pathOf(candidate.NormalizedURL))
+		default:
+			score = 0.8 * bookmark.Similarity(pathOf(target), pathOf(candidate.NormalizedURL))
+		}
+
+		if score > match.Confidence {
+			runnerUp = match.Confidence
+			match.Confidence = score
+			match.Base = candidate
+		} else if score > runnerUp {
+			runnerUp = score
+		}
+	}
+
+	ambiguous = match.Base != nil && match.Confidence-runnerUp < ambiguityMargin
+	return match, ambiguous
+}
+
+// pathOf returns the path and query of a URL without the leading slash
+func pathOf(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+	p := strings.TrimPrefix(u.Path, "/")
+	if u.RawQuery != "" {
+		p += "?" + u.RawQuery
+	}
+	return p
+}
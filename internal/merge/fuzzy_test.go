@@ -0,0 +1,133 @@
+package merge
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lelopez-io/moxli/internal/bookmark"
+)
+
+// newBookmark creates a bookmark with its normalized URL set
+func newBookmark(t *testing.T, rawURL, title string, added time.Time) *bookmark.Bookmark {
+	t.Helper()
+	b := &bookmark.Bookmark{URL: rawURL, Title: title, DateAdded: added}
+	if err := bookmark.NormalizeBookmarkURL(b); err != nil {
+		t.Fatalf("NormalizeBookmarkURL() error = %v", err)
+	}
+	return b
+}
+
+func TestMerger_Fuzzy_UnwrapsRedirector(t *testing.T) {
+	recent := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	old := time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	base := bookmark.NewCollection()
+	base.Add(newBookmark(t, "https://example.com/article", "Article", recent))
+
+	source := bookmark.NewCollection()
+	source.Add(newBookmark(t, "https://www.google.com/url?q=https%3A%2F%2Fexample.com%2Farticle", "", old))
+
+	merger := New(base, source).EnableFuzzy(FuzzyOptions{})
+	result, err := merger.Merge()
+	if err != nil {
+		t.Fatalf("Merge() error = %v", err)
+	}
+
+	if !result.Bookmarks[0].DateAdded.Equal(old) {
+		t.Errorf("DateAdded = %v, want %v (from unwrapped source)", result.Bookmarks[0].DateAdded, old)
+	}
+
+	report := merger.Report()
+	if report.Unwrapped != 1 {
+		t.Errorf("Unwrapped = %v, want 1", report.Unwrapped)
+	}
+	if report.Enhanced != 1 {
+		t.Errorf("Enhanced = %v, want 1", report.Enhanced)
+	}
+}
+
+func TestMerger_Fuzzy_AppliesConfidentMatch(t *testing.T) {
+	recent := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	old := time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	base := bookmark.NewCollection()
+	base.Add(newBookmark(t, "https://blog.example.com/posts/go-generics", "Understanding Go Generics", recent))
+	base.Add(newBookmark(t, "https://blog.example.com/about", "About", recent))
+
+	source := bookmark.NewCollection()
+	source.Add(newBookmark(t, "https://blog.example.com/posts/go-generics.html", "Understanding Go Generics", old))
+
+	merger := New(base, source).EnableFuzzy(FuzzyOptions{})
+	result, err := merger.Merge()
+	if err != nil {
+		t.Fatalf("Merge() error = %v", err)
+	}
+
+	report := merger.Report()
+	if len(report.Applied) != 1 {
+		t.Fatalf("len(Applied) = %v, want 1", len(report.Applied))
+	}
+	if report.Applied[0].Confidence < DefaultFuzzyThreshold {
+		t.Errorf("Confidence = %v, want >= %v", report.Applied[0].Confidence, DefaultFuzzyThreshold)
+	}
+	if !result.Bookmarks[0].DateAdded.Equal(old) {
+		t.Errorf("DateAdded = %v, want %v", result.Bookmarks[0].DateAdded, old)
+	}
+	if len(result.Bookmarks) != 2 {
+		t.Errorf("len(Bookmarks) = %v, want 2 (fuzzy pass never adds bookmarks)", len(result.Bookmarks))
+	}
+}
+
+func TestMerger_Fuzzy_ListsLowConfidenceCandidates(t *testing.T) {
+	recent := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	old := time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	base := bookmark.NewCollection()
+	base.Add(newBookmark(t, "https://example.com/guides/intro", "Getting Started Guide", recent))
+
+	source := bookmark.NewCollection()
+	// Shortlinks can only be matched by title, so they never auto-apply
+	source.Add(newBookmark(t, "https://t.co/abc123", "Getting Started Guide", old))
+
+	merger := New(base, source).EnableFuzzy(FuzzyOptions{})
+	result, err := merger.Merge()
+	if err != nil {
+		t.Fatalf("Merge() error = %v", err)
+	}
+
+	report := merger.Report()
+	if len(report.Applied) != 0 {
+		t.Errorf("len(Applied) = %v, want 0", len(report.Applied))
+	}
+	if len(report.Candidates) != 1 {
+		t.Fatalf("len(Candidates) = %v, want 1", len(report.Candidates))
+	}
+	if report.Candidates[0].Base != result.Bookmarks[0] {
+		t.Error("Candidate should point at the matching base bookmark")
+	}
+	if !result.Bookmarks[0].DateAdded.Equal(recent) {
+		t.Error("Candidates should not modify the base bookmark")
+	}
+}
+
+func TestMerger_Fuzzy_DisabledByDefault(t *testing.T) {
+	base := bookmark.NewCollection()
+	base.Add(newBookmark(t, "https://example.com/article", "Article", time.Time{}))
+
+	source := bookmark.NewCollection()
+	source.Add(newBookmark(t, "https://www.google.com/url?q=https%3A%2F%2Fexample.com%2Farticle", "",
+		time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC)))
+
+	merger := New(base, source)
+	result, err := merger.Merge()
+	if err != nil {
+		t.Fatalf("Merge() error = %v", err)
+	}
+
+	if !result.Bookmarks[0].DateAdded.IsZero() {
+		t.Error("Redirector links should not match without EnableFuzzy")
+	}
+	if merger.Report().Unwrapped != 0 {
+		t.Errorf("Unwrapped = %v, want 0", merger.Report().Unwrapped)
+	}
+}
@@ -8,6 +8,16 @@ import (
 type Merger struct {
 	base    *bookmark.Collection
 	sources []*bookmark.Collection
+	fuzzy   *FuzzyOptions // nil disables the fuzzy fallback pass
+	report  Report
+}
+
+// Report summarizes the outcome of the last merge
+type Report struct {
+	Enhanced   int          // Base bookmarks with timestamps updated
+	Unwrapped  int          // Source bookmarks matched after unwrapping a redirector link
+	Applied    []FuzzyMatch // Fuzzy matches at or above the threshold that were applied
+	Candidates []FuzzyMatch // Lower-confidence or ambiguous matches listed for review
 }
 
 // New creates a new merger with a base collection and source collections
@@ -18,6 +28,25 @@ func New(base *bookmark.Collection, sources ...*bookmark.Collection) *Merger {
 	}
 }
 
+// EnableFuzzy turns on a second matching pass for source bookmarks whose
+// normalized URL isn't in the base. Redirector links are unwrapped first, then
+// remaining bookmarks are matched by domain, title and path similarity.
+func (m *Merger) EnableFuzzy(opts FuzzyOptions) *Merger {
+	if opts.Threshold <= 0 {
+		opts.Threshold = DefaultFuzzyThreshold
+	}
+	if opts.MinConfidence <= 0 {
+		opts.MinConfidence = DefaultMinConfidence
+	}
+	m.fuzzy = &opts
+	return m
+}
+
+// Report returns the summary of the last Merge call
+func (m *Merger) Report() Report {
+	return m.report
+}
+
 // Merge performs the base-centric merge with timestamp-only enhancement
 func (m *Merger) Merge() (*bookmark.Collection, error) {
 	// Clone the base to avoid modifying original
@@ -28,7 +57,8 @@ func (m *Merger) Merge() (*bookmark.Collection, error) {
 	_, _ = result.FindByURL("")
 
 	// Track enhancement statistics
-	enhanced := 0
+	m.report = Report{}
+	var unmatched []*bookmark.Bookmark
 
 	// Process each source collection
 	for _, source := range m.sources {
@@ -38,17 +68,59 @@ func (m *Merger) Merge() (*bookmark.Collection, error) {
 			if exists {
 				// URL exists in base - enhance with timestamps only
 				if m.enhanceTimestamps(baseBookmark, sourceBookmark) {
-					enhanced++
+					m.report.Enhanced++
 				}
+				continue
 			}
 			// If URL not in base, skip (intentionally deleted/not included)
+			// unless the fuzzy pass can find the bookmark under another URL
+			unmatched = append(unmatched, sourceBookmark)
 		}
 	}
 
+	if m.fuzzy != nil {
+		m.matchFuzzy(result, unmatched)
+	}
+
 	result.UpdateMetadata()
 	return result, nil
 }
 
+// matchFuzzy runs the fallback pass over source bookmarks without an exact URL match
+func (m *Merger) matchFuzzy(result *bookmark.Collection, unmatched []*bookmark.Bookmark) {
+	matcher := newFuzzyMatcher(result)
+
+	for _, sourceBookmark := range unmatched {
+		target := UnwrapRedirect(sourceBookmark.URL)
+
+		// An unwrapped redirector may point straight at a base bookmark
+		if target != sourceBookmark.URL {
+			if normalized, err := bookmark.NormalizeURL(target); err == nil {
+				if baseBookmark, exists := result.FindByURL(normalized); exists {
+					m.report.Unwrapped++
+					if m.enhanceTimestamps(baseBookmark, sourceBookmark) {
+						m.report.Enhanced++
+					}
+					continue
+				}
+			}
+		}
+
+		match, ambiguous := matcher.bestMatch(sourceBookmark, target)
+		switch {
+		case match.Base == nil || match.Confidence < m.fuzzy.MinConfidence:
+			// No plausible match - treat as intentionally deleted
+		case match.Confidence >= m.fuzzy.Threshold && !ambiguous:
+			m.report.Applied = append(m.report.Applied, match)
+			if m.enhanceTimestamps(match.Base, sourceBookmark) {
+				m.report.Enhanced++
+			}
+		default:
+			m.report.Candidates = append(m.report.Candidates, match)
+		}
+	}
+}
+
 // enhanceTimestamps updates base bookmark with older timestamps from source
 // Returns true if any timestamp was updated
 func (m *Merger) enhanceTimestamps(base, source *bookmark.Bookmark) bool {
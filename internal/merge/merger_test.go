@@ -207,3 +207,32 @@ func TestMerger_Merge_NoModificationToBase(t *testing.T) {
 		t.Errorf("DateAdded = %v, want %v (should keep base date)", b.DateAdded, want)
 	}
 }
+
+func TestMerger_Merge_MatchesAlias(t *testing.T) {
+	// Base bookmark moved to its new URL after a permanent redirect
+	base := bookmark.NewCollection()
+	base.Add(&bookmark.Bookmark{
+		URL:           "https://new.example.com/post",
+		NormalizedURL: "https://new.example.com/post",
+		Aliases:       []string{"http://old.example.com/post"},
+		DateAdded:     time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+	})
+
+	// Source still has the old URL
+	source := bookmark.NewCollection()
+	source.Add(&bookmark.Bookmark{
+		URL:           "http://old.example.com/post",
+		NormalizedURL: "http://old.example.com/post",
+		DateAdded:     time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC),
+	})
+
+	result, err := New(base, source).Merge()
+	if err != nil {
+		t.Fatalf("Merge() error = %v", err)
+	}
+
+	want := time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC)
+	if b := result.Bookmarks[0]; !b.DateAdded.Equal(want) {
+		t.Errorf("DateAdded = %v, want %v (matched by alias)", b.DateAdded, want)
+	}
+}
@@ -0,0 +1,121 @@
+package merge
+
+import (
+	"net/url"
+	"strings"
+)
+
+// maxUnwrapDepth limits how many nested redirectors are unwrapped
+const maxUnwrapDepth = 5
+
+// redirector describes a link-wrapping service that carries the target in a query parameter
+type redirector struct {
+	match func(host, path string) bool
+	param []string // Query parameters that may hold the target URL, in priority order
+}
+
+// redirectors lists known link wrappers whose target can be recovered offline
+var redirectors = []redirector{
+	{
+		// Google search result links: https://www.google.com/url?q=<target>
+		match: func(host, path string) bool {
+			return (host == "google.com" || strings.HasPrefix(host, "google.") || strings.HasSuffix(host, ".google.com")) && path == "/url"
+		},
+		param: []string{"q", "url"},
+	},
+	{
+		// Outlook Safe Links: https://eur01.safelinks.protection.outlook.com/?url=<target>
+		match: func(host, path string) bool {
+			return strings.HasSuffix(host, ".safelinks.protection.outlook.com")
+		},
+		param: []string{"url"},
+	},
+	{
+		// Facebook outbound links: https://l.facebook.com/l.php?u=<target>
+		match: func(host, path string) bool {
+			return (host == "l.facebook.com" || host == "lm.facebook.com") && path == "/l.php"
+		},
+		param: []string{"u"},
+	},
+	{
+		// DuckDuckGo result links: https://duckduckgo.com/l/?uddg=<target>
+		match: func(host, path string) bool {
+			return host == "duckduckgo.com" && strings.HasPrefix(path, "/l")
+		},
+		param: []string{"uddg"},
+	},
+	{
+		// YouTube description links: https://www.youtube.com/redirect?q=<target>
+		match: func(host, path string) bool {
+			return host == "youtube.com" && path == "/redirect"
+		},
+		param: []string{"q"},
+	},
+	{
+		// Slack outbound links: https://slack-redir.net/link?url=<target>
+		match: func(host, path string) bool {
+			return host == "slack-redir.net" && path == "/link"
+		},
+		param: []string{"url"},
+	},
+}
+
+// shortlinkHosts are URL shorteners whose target cannot be recovered without a request
+var shortlinkHosts = map[string]bool{
+	"t.co":        true,
+	"bit.ly":      true,
+	"goo.gl":      true,
+	"tinyurl.com": true,
+	"ow.ly":       true,
+	"buff.ly":     true,
+	"lnkd.in":     true,
+	"is.gd":       true,
+}
+
+// UnwrapRedirect returns the target of a known redirector link, following
+// nested wrappers. URLs that aren't wrapped are returned unchanged.
+func UnwrapRedirect(rawURL string) string {
+	current := rawURL
+	for i := 0; i < maxUnwrapDepth; i++ {
+		target, ok := unwrapOnce(current)
+		if !ok {
+			break
+		}
+		current = target
+	}
+	return current
+}
+
+// unwrapOnce extracts the target of a single redirector layer
+func unwrapOnce(rawURL string) (string, bool) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", false
+	}
+
+	host := strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+	query := u.Query()
+
+	for _, r := range redirectors {
+		if !r.match(host, u.Path) {
+			continue
+		}
+		for _, param := range r.param {
+			target := query.Get(param)
+			if strings.HasPrefix(target, "http://") || strings.HasPrefix(target, "https://") {
+				return target, true
+			}
+		}
+	}
+
+	return "", false
+}
+
+// IsShortlink reports whether the URL belongs to a known URL shortener
+func IsShortlink(rawURL string) bool {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return false
+	}
+	return shortlinkHosts[strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")]
+}
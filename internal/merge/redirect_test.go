@@ -0,0 +1,67 @@
+package merge
+
+import "testing"
+
+func TestUnwrapRedirect(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{
+			name:  "google search link",
+			input: "https://www.google.com/url?sa=t&q=https%3A%2F%2Fexample.com%2Fpage&usg=abc",
+			want:  "https://example.com/page",
+		},
+		{
+			name:  "google url parameter",
+			input: "https://google.co.uk/url?url=https://example.com/a",
+			want:  "https://example.com/a",
+		},
+		{
+			name:  "outlook safelinks",
+			input: "https://eur01.safelinks.protection.outlook.com/?url=https%3A%2F%2Fexample.com%2Fdoc&data=xyz",
+			want:  "https://example.com/doc",
+		},
+		{
+			name:  "facebook outbound",
+			input: "https://l.facebook.com/l.php?u=https%3A%2F%2Fexample.com%2F&h=abc",
+			want:  "https://example.com/",
+		},
+		{
+			name:  "nested wrappers",
+			input: "https://www.google.com/url?q=" + "https%3A%2F%2Fl.facebook.com%2Fl.php%3Fu%3Dhttps%253A%252F%252Fexample.com%252Fdeep",
+			want:  "https://example.com/deep",
+		},
+		{
+			name:  "plain url unchanged",
+			input: "https://example.com/url?q=https://other.com",
+			want:  "https://example.com/url?q=https://other.com",
+		},
+		{
+			name:  "google non-redirect path unchanged",
+			input: "https://www.google.com/search?q=https://example.com",
+			want:  "https://www.google.com/search?q=https://example.com",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := UnwrapRedirect(tt.input); got != tt.want {
+				t.Errorf("UnwrapRedirect() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsShortlink(t *testing.T) {
+	if !IsShortlink("https://t.co/abc123") {
+		t.Error("t.co should be a shortlink")
+	}
+	if !IsShortlink("http://www.bit.ly/xyz") {
+		t.Error("bit.ly should be a shortlink")
+	}
+	if IsShortlink("https://example.com/t.co") {
+		t.Error("example.com should not be a shortlink")
+	}
+}
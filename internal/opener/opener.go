@@ -0,0 +1,168 @@
+// Package opener opens URLs in the user's browser, using a configured
+// command template, $BROWSER or the platform's default opener.
+package opener
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// Placeholder stands for the URL in command templates
+const Placeholder = "{url}"
+
+// Opener builds and runs the command that opens a URL
+type Opener struct {
+	template string
+	goos     string
+	getenv   func(string) string
+	lookPath func(string) (string, error)
+}
+
+// New returns an opener using template, a command line in which Placeholder
+// is replaced by the URL (the URL is appended when it is missing). An empty
+// template picks the first available command of $BROWSER, then the platform
+// default: open on macOS, xdg-open elsewhere.
+func New(template string) *Opener {
+	return &Opener{
+		template: template,
+		goos:     runtime.GOOS,
+		getenv:   os.Getenv,
+		lookPath: exec.LookPath,
+	}
+}
+
+// Command returns the command that opens url
+func (o *Opener) Command(url string) (*exec.Cmd, error) {
+	args, err := o.args(url)
+	if err != nil {
+		return nil, err
+	}
+	return exec.Command(args[0], args[1:]...), nil
+}
+
+// Open starts the command opening url without waiting for it to finish
+func (o *Opener) Open(url string) error {
+	cmd, err := o.Command(url)
+	if err != nil {
+		return err
+	}
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("failed to run %s: %w", cmd.Args[0], err)
+	}
+	// Reap the process once the browser has taken over
+	go cmd.Wait()
+	return nil
+}
+
+// args returns the command line opening url
+func (o *Opener) args(url string) ([]string, error) {
+	if o.template != "" {
+		args, err := splitArgs(o.template)
+		if err != nil {
+			return nil, fmt.Errorf("invalid opener %q: %w", o.template, err)
+		}
+		if len(args) == 0 {
+			return nil, fmt.Errorf("invalid opener %q: no command", o.template)
+		}
+		return expand(args, url, Placeholder), nil
+	}
+
+	// $BROWSER lists commands separated by ":", with %s standing for the URL
+	if browser := o.getenv("BROWSER"); browser != "" {
+		for _, candidate := range strings.Split(browser, ":") {
+			args, err := splitArgs(candidate)
+			if err != nil || len(args) == 0 {
+				continue
+			}
+			if _, err := o.lookPath(args[0]); err == nil {
+				return expand(args, url, "%s"), nil
+			}
+		}
+	}
+
+	switch o.goos {
+	case "darwin":
+		return []string{"open", url}, nil
+	case "windows":
+		return []string{"rundll32", "url.dll,FileProtocolHandler", url}, nil
+	}
+	if _, err := o.lookPath("xdg-open"); err != nil {
+		return nil, errors.New("no way to open URLs: install xdg-open, set $BROWSER or set opener in ~/.moxli/config.yaml")
+	}
+	return []string{"xdg-open", url}, nil
+}
+
+// expand replaces placeholder in args with url, appending url when no
+// argument contains the placeholder
+func expand(args []string, url, placeholder string) []string {
+	expanded := make([]string, len(args))
+	found := false
+	for i, arg := range args {
+		if strings.Contains(arg, placeholder) {
+			found = true
+			arg = strings.ReplaceAll(arg, placeholder, url)
+		}
+		expanded[i] = arg
+	}
+	if !found {
+		expanded = append(expanded, url)
+	}
+	return expanded
+}
+
+// splitArgs splits a command line into arguments like a POSIX shell would,
+// honouring single and double quotes and backslash escapes. The command is
+// never run through a shell, so URLs cannot inject commands.
+func splitArgs(s string) ([]string, error) {
+	var args []string
+	var current strings.Builder
+	inArg := false
+	var quote rune
+
+	runes := []rune(s)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case quote == '\'':
+			if r == '\'' {
+				quote = 0
+			} else {
+				current.WriteRune(r)
+			}
+		case r == '\\' && i+1 < len(runes) && (quote == 0 || runes[i+1] == '"' || runes[i+1] == '\\'):
+			i++
+			current.WriteRune(runes[i])
+			inArg = true
+		case quote == '"':
+			if r == '"' {
+				quote = 0
+			} else {
+				current.WriteRune(r)
+			}
+		case r == '\'' || r == '"':
+			quote = r
+			inArg = true
+		case r == ' ' || r == '\t' || r == '\n':
+			if inArg {
+				args = append(args, current.String())
+				current.Reset()
+				inArg = false
+			}
+		default:
+			current.WriteRune(r)
+			inArg = true
+		}
+	}
+
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated %c quote", quote)
+	}
+	if inArg {
+		args = append(args, current.String())
+	}
+	return args, nil
+}
@@ -0,0 +1,117 @@
+package opener
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+// fakeOpener returns an opener for goos with the given environment and
+// commands available on the PATH
+func fakeOpener(template, goos string, env map[string]string, available ...string) *Opener {
+	return &Opener{
+		template: template,
+		goos:     goos,
+		getenv:   func(key string) string { return env[key] },
+		lookPath: func(name string) (string, error) {
+			if slices.Contains(available, name) {
+				return "/usr/bin/" + name, nil
+			}
+			return "", errors.New("not found")
+		},
+	}
+}
+
+func TestOpener_Command(t *testing.T) {
+	url := "https://example.com/a?b=1&c=2"
+
+	tests := []struct {
+		name   string
+		opener *Opener
+		want   []string
+	}{
+		{
+			name:   "template with placeholder",
+			opener: fakeOpener(`firefox --new-tab {url}`, "linux", nil),
+			want:   []string{"firefox", "--new-tab", url},
+		},
+		{
+			name:   "template without placeholder",
+			opener: fakeOpener(`"/Applications/My Browser" -x`, "darwin", nil),
+			want:   []string{"/Applications/My Browser", "-x", url},
+		},
+		{
+			name:   "template beats $BROWSER",
+			opener: fakeOpener("w3m", "linux", map[string]string{"BROWSER": "lynx"}, "lynx"),
+			want:   []string{"w3m", url},
+		},
+		{
+			name:   "$BROWSER first available",
+			opener: fakeOpener("", "linux", map[string]string{"BROWSER": "missing:chromium --incognito %s"}, "chromium"),
+			want:   []string{"chromium", "--incognito", url},
+		},
+		{
+			name:   "macOS default",
+			opener: fakeOpener("", "darwin", nil),
+			want:   []string{"open", url},
+		},
+		{
+			name:   "Linux default",
+			opener: fakeOpener("", "linux", map[string]string{"BROWSER": "missing"}, "xdg-open"),
+			want:   []string{"xdg-open", url},
+		},
+	}
+
+	for _, tt := range tests {
+		cmd, err := tt.opener.Command(url)
+		if err != nil {
+			t.Errorf("%s: Command() error = %v", tt.name, err)
+			continue
+		}
+		if !slices.Equal(cmd.Args, tt.want) {
+			t.Errorf("%s: Args = %q, want %q", tt.name, cmd.Args, tt.want)
+		}
+	}
+}
+
+func TestOpener_Command_Errors(t *testing.T) {
+	tests := map[string]*Opener{
+		"no xdg-open":  fakeOpener("", "linux", nil),
+		"bad template": fakeOpener(`firefox "{url}`, "linux", nil),
+		"only spaces":  fakeOpener("   ", "linux", nil),
+	}
+
+	for name, o := range tests {
+		if _, err := o.Command("https://example.com"); err == nil {
+			t.Errorf("%s: Command() error = nil, want an error", name)
+		}
+	}
+}
+
+func TestSplitArgs(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []string
+	}{
+		{"open -a Safari", []string{"open", "-a", "Safari"}},
+		{`"My Browser" '{url}'`, []string{"My Browser", "{url}"}},
+		{`a\ b "c \"d\"" 'e\f'`, []string{"a b", `c "d"`, `e\f`}},
+		{`x ""`, []string{"x", ""}},
+		{"", nil},
+	}
+
+	for _, tt := range tests {
+		got, err := splitArgs(tt.in)
+		if err != nil {
+			t.Errorf("splitArgs(%q) error = %v", tt.in, err)
+			continue
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("splitArgs(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+
+	if _, err := splitArgs(`'open`); err == nil {
+		t.Error("splitArgs() of an unterminated quote should fail")
+	}
+}
@@ -0,0 +1,335 @@
+package query
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/lelopez-io/moxli/internal/bookmark"
+)
+
+// Node is a parsed query expression that can be evaluated against a bookmark
+type Node interface {
+	Match(b *bookmark.Bookmark) bool
+	String() string
+}
+
+// And matches when every child matches. An empty And matches everything.
+type And struct {
+	Nodes []Node
+}
+
+// Or matches when any child matches
+type Or struct {
+	Nodes []Node
+}
+
+// Not inverts its child
+type Not struct {
+	Node Node
+}
+
+// Term is a single condition: plain text or a field:value filter
+type Term struct {
+	Field string // Empty for plain text
+	Value string
+
+	tagPath  []string                      // Normalized tag hierarchy for tag:
+	folder   []string                      // Lowercase folder path for folder:
+	cmp      string                        // Comparison for date fields: "=", ">", ">=", "<", "<="
+	from, to time.Time                     // Date range [from, to) for date fields
+	check    func(*bookmark.Bookmark) bool // Compiled is:/has: condition
+}
+
+// fields lists the known field names. Text before ":" that is not a field is
+// searched as plain text.
+var fields = map[string]bool{
+	"tag":      true,
+	"folder":   true,
+	"domain":   true,
+	"source":   true,
+	"title":    true,
+	"url":      true,
+	"is":       true,
+	"has":      true,
+	"added":    true,
+	"modified": true,
+}
+
+// isConditions are the values accepted by is:
+var isConditions = map[string]func(*bookmark.Bookmark) bool{
+	"starred":    func(b *bookmark.Bookmark) bool { return b.IsStarred },
+	"dead":       func(b *bookmark.Bookmark) bool { return b.LinkStatus.Dead() },
+	"redirected": func(b *bookmark.Bookmark) bool { return b.LinkStatus.Redirected() },
+	"checked":    func(b *bookmark.Bookmark) bool { return b.LinkStatus != nil },
+}
+
+// hasConditions are the values accepted by has:
+var hasConditions = map[string]func(*bookmark.Bookmark) bool{
+	"title":       func(b *bookmark.Bookmark) bool { return b.Title != "" },
+	"description": func(b *bookmark.Bookmark) bool { return b.Description != "" },
+	"comment":     func(b *bookmark.Bookmark) bool { return b.Comment != "" },
+	"keyword":     func(b *bookmark.Bookmark) bool { return b.Keyword != "" },
+	"article":     func(b *bookmark.Bookmark) bool { return b.Article != "" },
+	"tags":        func(b *bookmark.Bookmark) bool { return len(b.Tags) > 0 },
+	"folder":      func(b *bookmark.Bookmark) bool { return len(b.Folder) > 0 },
+	"aliases":     func(b *bookmark.Bookmark) bool { return len(b.Aliases) > 0 },
+	"snapshot":    func(b *bookmark.Bookmark) bool { return b.Snapshot != nil },
+}
+
+// dateLayouts are the accepted date formats, most precise first
+var dateLayouts = []struct {
+	layout string
+	next   func(time.Time) time.Time // Start of the following period
+}{
+	{"2006-01-02", func(t time.Time) time.Time { return t.AddDate(0, 0, 1) }},
+	{"2006-01", func(t time.Time) time.Time { return t.AddDate(0, 1, 0) }},
+	{"2006", func(t time.Time) time.Time { return t.AddDate(1, 0, 0) }},
+}
+
+// newTerm validates a term and prepares it for matching
+func newTerm(field, value string) (*Term, error) {
+	t := &Term{Field: field, Value: value}
+	if value == "" {
+		if field == "" {
+			return nil, fmt.Errorf("empty phrase")
+		}
+		return nil, fmt.Errorf("%s: needs a value", field)
+	}
+
+	switch field {
+	case "tag":
+		for _, segment := range strings.Split(value, "/") {
+			if normalized := bookmark.NormalizeTag(segment); normalized != "" {
+				t.tagPath = append(t.tagPath, normalized)
+			}
+		}
+		if len(t.tagPath) == 0 {
+			return nil, fmt.Errorf("tag: needs a tag name")
+		}
+	case "folder":
+		for _, segment := range bookmark.ParseFolder(value) {
+			t.folder = append(t.folder, strings.ToLower(segment))
+		}
+	case "is":
+		check, ok := isConditions[strings.ToLower(value)]
+		if !ok {
+			return nil, fmt.Errorf("unknown condition is:%s", value)
+		}
+		t.check = check
+	case "has":
+		check, ok := hasConditions[strings.ToLower(value)]
+		if !ok {
+			return nil, fmt.Errorf("unknown condition has:%s", value)
+		}
+		t.check = check
+	case "added", "modified":
+		if err := t.parseDate(value); err != nil {
+			return nil, fmt.Errorf("%s: %w", field, err)
+		}
+	}
+
+	return t, nil
+}
+
+// parseDate reads a comparison and a date such as ">2023-01-01" or "2024-05"
+func (t *Term) parseDate(value string) error {
+	t.cmp = "="
+	for _, cmp := range []string{">=", "<=", ">", "<", "="} {
+		if strings.HasPrefix(value, cmp) {
+			t.cmp = cmp
+			value = value[len(cmp):]
+			break
+		}
+	}
+
+	for _, d := range dateLayouts {
+		if from, err := time.ParseInLocation(d.layout, value, time.Local); err == nil {
+			t.from = from
+			t.to = d.next(from)
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid date %q (use YYYY-MM-DD, YYYY-MM or YYYY)", value)
+}
+
+// Match evaluates the term against a bookmark
+func (t *Term) Match(b *bookmark.Bookmark) bool {
+	switch t.Field {
+	case "":
+		return matchText(b, strings.ToLower(t.Value))
+	case "tag":
+		return matchTag(b, t.tagPath)
+	case "folder":
+		return matchFolder(b, t.folder)
+	case "domain":
+		domain := b.Domain()
+		value := strings.TrimPrefix(strings.ToLower(t.Value), "www.")
+		return domain == value || strings.HasSuffix(domain, "."+value)
+	case "source":
+		return strings.EqualFold(b.Source, t.Value)
+	case "title":
+		return containsFold(b.Title, t.Value)
+	case "url":
+		return containsFold(b.URL, t.Value)
+	case "is", "has":
+		return t.check(b)
+	case "added":
+		return t.matchDate(b.DateAdded)
+	case "modified":
+		return t.matchDate(b.LastModified)
+	}
+	return false
+}
+
+// matchDate compares a timestamp with the term's date range
+func (t *Term) matchDate(ts time.Time) bool {
+	if ts.IsZero() {
+		return false
+	}
+	switch t.cmp {
+	case ">":
+		return !ts.Before(t.to)
+	case ">=":
+		return !ts.Before(t.from)
+	case "<":
+		return ts.Before(t.from)
+	case "<=":
+		return ts.Before(t.to)
+	default:
+		return !ts.Before(t.from) && ts.Before(t.to)
+	}
+}
+
+// String renders the term in query syntax
+func (t *Term) String() string {
+	value := t.Value
+	if strings.ContainsAny(value, " \t()") {
+		value = `"` + value + `"`
+	}
+	if t.Field == "" {
+		return value
+	}
+	return t.Field + ":" + value
+}
+
+// Match reports whether every child matches
+func (n *And) Match(b *bookmark.Bookmark) bool {
+	for _, child := range n.Nodes {
+		if !child.Match(b) {
+			return false
+		}
+	}
+	return true
+}
+
+// String renders the expression in query syntax
+func (n *And) String() string {
+	return "(" + joinNodes(n.Nodes, " ") + ")"
+}
+
+// Match reports whether any child matches
+func (n *Or) Match(b *bookmark.Bookmark) bool {
+	for _, child := range n.Nodes {
+		if child.Match(b) {
+			return true
+		}
+	}
+	return false
+}
+
+// String renders the expression in query syntax
+func (n *Or) String() string {
+	return "(" + joinNodes(n.Nodes, " OR ") + ")"
+}
+
+// Match reports whether the child does not match
+func (n *Not) Match(b *bookmark.Bookmark) bool {
+	return !n.Node.Match(b)
+}
+
+// String renders the expression in query syntax
+func (n *Not) String() string {
+	return "-" + n.Node.String()
+}
+
+// joinNodes renders nodes separated by sep
+func joinNodes(nodes []Node, sep string) string {
+	parts := make([]string, len(nodes))
+	for i, node := range nodes {
+		parts[i] = node.String()
+	}
+	return strings.Join(parts, sep)
+}
+
+// matchText searches title, URL, description, comment, keyword, tags and
+// folder for a lowercase substring
+func matchText(b *bookmark.Bookmark, value string) bool {
+	if containsLower(b.Title, value) ||
+		containsLower(b.URL, value) ||
+		containsLower(b.Description, value) ||
+		containsLower(b.Comment, value) ||
+		containsLower(b.Keyword, value) {
+		return true
+	}
+	for _, path := range b.Tags {
+		if containsLower(strings.Join(path, "/"), value) {
+			return true
+		}
+	}
+	for _, segment := range b.Folder {
+		if containsLower(segment, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchTag reports whether any tag hierarchy starts with path, so tag:security
+// also matches security/auth
+func matchTag(b *bookmark.Bookmark, path []string) bool {
+	for _, tag := range b.Tags {
+		if len(tag) < len(path) {
+			continue
+		}
+		match := true
+		for i, segment := range path {
+			if bookmark.NormalizeTag(tag[i]) != segment {
+				match = false
+				break
+			}
+		}
+		if match {
+			return true
+		}
+	}
+	return false
+}
+
+// matchFolder reports whether the folder path contains the given segments in
+// order, so folder:Research matches "Bookmarks Bar / Research / Papers"
+func matchFolder(b *bookmark.Bookmark, folder []string) bool {
+	for start := 0; start+len(folder) <= len(b.Folder); start++ {
+		match := true
+		for i, segment := range folder {
+			if strings.ToLower(b.Folder[start+i]) != segment {
+				match = false
+				break
+			}
+		}
+		if match {
+			return true
+		}
+	}
+	return false
+}
+
+// containsFold reports whether s contains substr, ignoring case
+func containsFold(s, substr string) bool {
+	return containsLower(s, strings.ToLower(substr))
+}
+
+// containsLower reports whether s contains the already lowercase substr
+func containsLower(s, substr string) bool {
+	return strings.Contains(strings.ToLower(s), substr)
+}
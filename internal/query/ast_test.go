@@ -0,0 +1,108 @@
+package query
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lelopez-io/moxli/internal/bookmark"
+)
+
+func testBookmarks() []*bookmark.Bookmark {
+	return []*bookmark.Bookmark{
+		{
+			ID:          "oauth",
+			URL:         "https://www.github.com/org/oauth-guide",
+			Title:       "OAuth Guide",
+			Description: "Securing APIs",
+			Tags:        [][]string{{"security", "user-auth"}, {"reading"}},
+			Folder:      []string{"Bookmarks Bar", "Research", "Auth"},
+			Source:      "firefox",
+			IsStarred:   true,
+			Comment:     "read twice",
+			DateAdded:   time.Date(2023, 3, 15, 10, 0, 0, 0, time.Local),
+		},
+		{
+			ID:         "rust",
+			URL:        "https://docs.rs/tokio",
+			Title:      "Tokio async runtime",
+			Tags:       [][]string{{"Rust"}, {"archived"}},
+			Folder:     []string{"Research"},
+			Source:     "anybox",
+			DateAdded:  time.Date(2022, 12, 31, 23, 0, 0, 0, time.Local),
+			LinkStatus: &bookmark.LinkStatus{StatusCode: 404},
+		},
+		{
+			ID:         "blog",
+			URL:        "https://blog.example.com/post",
+			Title:      "A blog post",
+			Source:     "safari",
+			LinkStatus: &bookmark.LinkStatus{StatusCode: 200, FinalURL: "https://example.com/blog/post"},
+			Aliases:    []string{"http://blog.example.com/post"},
+			Snapshot:   &bookmark.Snapshot{Path: "snapshots/ab/abc.html", Format: "html"},
+		},
+	}
+}
+
+func TestQuery_Match(t *testing.T) {
+	tests := []struct {
+		query string
+		want  string // IDs of matching bookmarks, concatenated
+	}{
+		{"guide", "oauth"},
+		{"GUIDE", "oauth"},
+		{`"async runtime"`, "rust"},
+		{"twice", "oauth"},
+		{"tag:security", "oauth"},
+		{"tag:security/user-auth", "oauth"},
+		{`tag:"Security/User Auth"`, "oauth"},
+		{"tag:user-auth", ""},
+		{"tag:rust", "rust"},
+		{"-tag:archived", "oauthblog"},
+		{"folder:Research", "oauthrust"},
+		{`folder:"research / auth"`, "oauth"},
+		{"domain:github.com", "oauth"},
+		{"domain:example.com", "blog"},
+		{"domain:ample.com", ""},
+		{"source:FIREFOX", "oauth"},
+		{"title:post", "blog"},
+		{"url:tokio", "rust"},
+		{"is:starred", "oauth"},
+		{"-is:starred", "rustblog"},
+		{"is:dead", "rust"},
+		{"is:redirected", "blog"},
+		{"is:checked -is:dead", "blog"},
+		{"-is:checked", "oauth"},
+		{"has:comment", "oauth"},
+		{"has:tags", "oauthrust"},
+		{"has:aliases", "blog"},
+		{"has:snapshot", "blog"},
+		{"added:>2023-01-01", "oauth"},
+		{"added:<2023", "rust"},
+		{"added:2023-03", "oauth"},
+		{"added:<=2022-12-31", "rust"},
+		{"added:>=2023-03-15", "oauth"},
+		{"tag:rust OR source:safari", "rustblog"},
+		{"(tag:rust OR source:safari) -title:blog", "rust"},
+		{"research -tag:archived", "oauth"},
+		{"https://docs.rs", "rust"},
+		{"", "oauthrustblog"},
+	}
+
+	bookmarks := testBookmarks()
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			q, err := Parse(tt.query)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+
+			got := ""
+			for _, b := range q.Filter(bookmarks) {
+				got += b.ID
+			}
+			if got != tt.want {
+				t.Errorf("Filter(%q) = %q, want %q", tt.query, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,129 @@
+package query
+
+import (
+	"fmt"
+	"strings"
+)
+
+// tokenKind identifies the type of a lexical token
+type tokenKind int
+
+const (
+	tokenEOF    tokenKind = iota
+	tokenTerm             // Bare word, quoted phrase or field:value
+	tokenOr               // OR keyword
+	tokenNot              // Leading "-"
+	tokenLParen           // (
+	tokenRParen           // )
+)
+
+// token is a single lexical unit of a query
+type token struct {
+	kind  tokenKind
+	field string // Field name for field:value terms, empty for plain text
+	value string // Term text with quotes removed
+	pos   int    // Byte offset in the query, for error messages
+}
+
+// lex splits a query into tokens.
+// Quotes group words into a phrase, both for plain text ("rust async") and
+// field values (folder:"Bookmarks Bar").
+func lex(input string) ([]token, error) {
+	var tokens []token
+	i := 0
+
+	for i < len(input) {
+		c := input[i]
+		switch {
+		case isSpace(c):
+			i++
+		case c == '(':
+			tokens = append(tokens, token{kind: tokenLParen, pos: i})
+			i++
+		case c == ')':
+			tokens = append(tokens, token{kind: tokenRParen, pos: i})
+			i++
+		case c == '-' && (i == 0 || isTermEnd(input[i-1])):
+			tokens = append(tokens, token{kind: tokenNot, pos: i})
+			i++
+		default:
+			tok, next, err := lexTerm(input, i)
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, tok)
+			i = next
+		}
+	}
+
+	return append(tokens, token{kind: tokenEOF, pos: len(input)}), nil
+}
+
+// lexTerm reads a word, phrase or field:value term starting at i.
+// Returns the token and the offset after it.
+func lexTerm(input string, i int) (token, int, error) {
+	start := i
+
+	if input[i] == '"' {
+		phrase, next, err := lexQuoted(input, i)
+		if err != nil {
+			return token{}, 0, err
+		}
+		return token{kind: tokenTerm, value: phrase, pos: start}, next, nil
+	}
+
+	for i < len(input) && !isTermEnd(input[i]) {
+		if input[i] == ':' && i > start && isFieldName(input[start:i]) {
+			field := strings.ToLower(input[start:i])
+			i++
+
+			if i < len(input) && input[i] == '"' {
+				value, next, err := lexQuoted(input, i)
+				if err != nil {
+					return token{}, 0, err
+				}
+				return token{kind: tokenTerm, field: field, value: value, pos: start}, next, nil
+			}
+
+			valueStart := i
+			for i < len(input) && !isTermEnd(input[i]) {
+				i++
+			}
+			return token{kind: tokenTerm, field: field, value: input[valueStart:i], pos: start}, i, nil
+		}
+		i++
+	}
+
+	word := input[start:i]
+	if word == "OR" {
+		return token{kind: tokenOr, pos: start}, i, nil
+	}
+	return token{kind: tokenTerm, value: word, pos: start}, i, nil
+}
+
+// lexQuoted reads a double-quoted string starting at the opening quote
+func lexQuoted(input string, i int) (string, int, error) {
+	end := strings.IndexByte(input[i+1:], '"')
+	if end < 0 {
+		return "", 0, fmt.Errorf("unterminated quote at position %d", i+1)
+	}
+	return input[i+1 : i+1+end], i + end + 2, nil
+}
+
+// isSpace reports whether c is ASCII whitespace. Queries are scanned byte by
+// byte, so multi-byte UTF-8 text is never split.
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r'
+}
+
+// isTermEnd reports whether c ends an unquoted term
+func isTermEnd(c byte) bool {
+	return isSpace(c) || c == '(' || c == ')'
+}
+
+// isFieldName reports whether s is a known field, so text such as
+// "https://example.com" is searched as plain text
+func isFieldName(s string) bool {
+	_, ok := fields[strings.ToLower(s)]
+	return ok
+}
@@ -0,0 +1,90 @@
+package query
+
+import "testing"
+
+func TestLex(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []token
+	}{
+		{
+			name:  "words and fields",
+			input: `rust tag:security/auth`,
+			want: []token{
+				{kind: tokenTerm, value: "rust"},
+				{kind: tokenTerm, field: "tag", value: "security/auth"},
+			},
+		},
+		{
+			name:  "quoted phrase and quoted field value",
+			input: `"async rust" folder:"Bookmarks Bar"`,
+			want: []token{
+				{kind: tokenTerm, value: "async rust"},
+				{kind: tokenTerm, field: "folder", value: "Bookmarks Bar"},
+			},
+		},
+		{
+			name:  "negation, OR and groups",
+			input: `-tag:archived (a OR b)`,
+			want: []token{
+				{kind: tokenNot},
+				{kind: tokenTerm, field: "tag", value: "archived"},
+				{kind: tokenLParen},
+				{kind: tokenTerm, value: "a"},
+				{kind: tokenOr},
+				{kind: tokenTerm, value: "b"},
+				{kind: tokenRParen},
+			},
+		},
+		{
+			name:  "unknown field is plain text",
+			input: `https://example.com/a-b`,
+			want:  []token{{kind: tokenTerm, value: "https://example.com/a-b"}},
+		},
+		{
+			name:  "field names are case-insensitive",
+			input: `TAG:go`,
+			want:  []token{{kind: tokenTerm, field: "tag", value: "go"}},
+		},
+		{
+			name:  "lowercase or is a word",
+			input: `this or that`,
+			want: []token{
+				{kind: tokenTerm, value: "this"},
+				{kind: tokenTerm, value: "or"},
+				{kind: tokenTerm, value: "that"},
+			},
+		},
+		{
+			name:  "non-ASCII text",
+			input: `Å…`,
+			want:  []token{{kind: tokenTerm, value: "Å…"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := lex(tt.input)
+			if err != nil {
+				t.Fatalf("lex() error = %v", err)
+			}
+
+			got = got[:len(got)-1] // Drop EOF
+			if len(got) != len(tt.want) {
+				t.Fatalf("lex() = %v tokens, want %v: %+v", len(got), len(tt.want), got)
+			}
+			for i := range tt.want {
+				if got[i].kind != tt.want[i].kind || got[i].field != tt.want[i].field || got[i].value != tt.want[i].value {
+					t.Errorf("token[%d] = %+v, want %+v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestLex_UnterminatedQuote(t *testing.T) {
+	if _, err := lex(`title:"open`); err == nil {
+		t.Error("lex() should fail on an unterminated quote")
+	}
+}
@@ -0,0 +1,221 @@
+// Package query implements the bookmark search language used by the TUI
+// filter and `moxli search`.
+//
+// A query is a list of terms that must all match. Terms are plain words,
+// quoted phrases or field filters:
+//
+//	tag:security/auth    tag hierarchy, including nested tags
+//	folder:Research      folder path segment(s)
+//	domain:github.com    host, including subdomains
+//	source:firefox       import source
+//	title:rust url:docs  substring of a single field
+//	is:starred           starred bookmarks
+//	is:dead              link check found the URL broken; is:redirected
+//	                     and is:checked work the same way (moxli check)
+//	has:comment          non-empty field (title, description, comment,
+//	                     keyword, article, tags, folder, aliases,
+//	                     snapshot)
+//	added:>2023-01-01    date comparison (>, >=, <, <=, =) on DateAdded;
+//	                     modified: compares LastModified
+//
+// Terms can be negated with "-", combined with OR and grouped with
+// parentheses: (tag:go OR tag:rust) -is:starred
+package query
+
+import (
+	"fmt"
+
+	"github.com/lelopez-io/moxli/internal/bookmark"
+)
+
+// Query is a parsed search query
+type Query struct {
+	Root Node
+	raw  string
+}
+
+// Parse parses a query string. An empty query matches every bookmark.
+func Parse(input string) (*Query, error) {
+	tokens, err := lex(input)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != tokenEOF {
+		return nil, fmt.Errorf("unexpected %s at position %d", describe(tok), tok.pos+1)
+	}
+
+	return &Query{Root: root, raw: input}, nil
+}
+
+// IsPlain reports whether input is only bare words, without fields, quotes,
+// negation, OR or parentheses. Plain input can be searched fuzzily instead.
+func IsPlain(input string) bool {
+	tokens, err := lex(input)
+	if err != nil {
+		return false
+	}
+	for _, tok := range tokens {
+		switch tok.kind {
+		case tokenEOF:
+			return true
+		case tokenTerm:
+			if tok.field != "" || input[tok.pos] == '"' {
+				return false
+			}
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// Match reports whether the bookmark satisfies the query
+func (q *Query) Match(b *bookmark.Bookmark) bool {
+	return q.Root.Match(b)
+}
+
+// Filter returns the bookmarks matching the query, preserving order
+func (q *Query) Filter(bookmarks []*bookmark.Bookmark) []*bookmark.Bookmark {
+	matched := []*bookmark.Bookmark{}
+	for _, b := range bookmarks {
+		if q.Match(b) {
+			matched = append(matched, b)
+		}
+	}
+	return matched
+}
+
+// String returns the query as it was written
+func (q *Query) String() string {
+	return q.raw
+}
+
+// parser is a recursive descent parser over the token list
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokenEOF {
+		p.pos++
+	}
+	return tok
+}
+
+// parseOr parses: and ("OR" and)*
+func (p *parser) parseOr() (Node, error) {
+	first, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	nodes := []Node{first}
+	for p.peek().kind == tokenOr {
+		or := p.next()
+		if kind := p.peek().kind; kind == tokenEOF || kind == tokenRParen || kind == tokenOr {
+			return nil, fmt.Errorf("OR at position %d needs a term on both sides", or.pos+1)
+		}
+		node, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, node)
+	}
+
+	if len(nodes) == 1 {
+		return first, nil
+	}
+	return &Or{Nodes: nodes}, nil
+}
+
+// parseAnd parses a run of unary expressions up to OR, ")" or the end
+func (p *parser) parseAnd() (Node, error) {
+	var nodes []Node
+	for {
+		switch tok := p.peek(); tok.kind {
+		case tokenEOF, tokenRParen:
+			if len(nodes) == 1 {
+				return nodes[0], nil
+			}
+			return &And{Nodes: nodes}, nil
+		case tokenOr:
+			if len(nodes) == 0 {
+				return nil, fmt.Errorf("OR at position %d needs a term on both sides", tok.pos+1)
+			}
+			if len(nodes) == 1 {
+				return nodes[0], nil
+			}
+			return &And{Nodes: nodes}, nil
+		}
+
+		node, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, node)
+	}
+}
+
+// parseUnary parses: "-" unary | "(" or ")" | term
+func (p *parser) parseUnary() (Node, error) {
+	tok := p.next()
+	switch tok.kind {
+	case tokenNot:
+		node, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &Not{Node: node}, nil
+
+	case tokenLParen:
+		if p.peek().kind == tokenRParen {
+			return nil, fmt.Errorf("empty group at position %d", tok.pos+1)
+		}
+		node, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if closing := p.next(); closing.kind != tokenRParen {
+			return nil, fmt.Errorf("missing ) for ( at position %d", tok.pos+1)
+		}
+		return node, nil
+
+	case tokenTerm:
+		term, err := newTerm(tok.field, tok.value)
+		if err != nil {
+			return nil, fmt.Errorf("%w (position %d)", err, tok.pos+1)
+		}
+		return term, nil
+	}
+
+	return nil, fmt.Errorf("unexpected %s at position %d", describe(tok), tok.pos+1)
+}
+
+// describe names a token for error messages
+func describe(tok token) string {
+	switch tok.kind {
+	case tokenEOF:
+		return "end of query"
+	case tokenOr:
+		return "OR"
+	case tokenNot:
+		return `"-"`
+	case tokenLParen:
+		return `"("`
+	case tokenRParen:
+		return `")"`
+	}
+	return fmt.Sprintf("%q", tok.value)
+}
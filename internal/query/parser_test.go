@@ -0,0 +1,79 @@
+package query
+
+import "testing"
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"rust", "rust"},
+		{"rust async", "(rust async)"},
+		{"a OR b c", "(a OR (b c))"},
+		{"(a OR b) c", "((a OR b) c)"},
+		{"-tag:archived is:starred", "(-tag:archived is:starred)"},
+		{`"two words" -(x OR y)`, `("two words" -(x OR y))`},
+		{"", "()"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			q, err := Parse(tt.input)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if got := q.Root.String(); got != tt.want {
+				t.Errorf("Parse() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParse_Errors(t *testing.T) {
+	inputs := []string{
+		"a OR",
+		"OR a",
+		"(a",
+		"a)",
+		"()",
+		"-",
+		"is:sleeping",
+		"has:wings",
+		"added:>yesterday",
+		"tag:",
+		`""`,
+	}
+
+	for _, input := range inputs {
+		t.Run(input, func(t *testing.T) {
+			if _, err := Parse(input); err == nil {
+				t.Errorf("Parse(%q) should fail", input)
+			}
+		})
+	}
+}
+
+func TestIsPlain(t *testing.T) {
+	tests := []struct {
+		input string
+		want  bool
+	}{
+		{"kube docs", true},
+		{"", true},
+		{"https://docs.rs", true},
+		{"tag:go", false},
+		{`"exact phrase"`, false},
+		{"-archived", false},
+		{"a OR b", false},
+		{"(a)", false},
+		{`"unterminated`, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			if got := IsPlain(tt.input); got != tt.want {
+				t.Errorf("IsPlain(%q) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
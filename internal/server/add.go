@@ -0,0 +1,185 @@
+package server
+
+import (
+	"encoding/json"
+	"html/template"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/lelopez-io/moxli/internal/bookmark"
+)
+
+// addPage is the data of the quick-add form and its confirmation
+type addPage struct {
+	URL     string
+	Title   string
+	Text    string
+	Tags    string
+	Error   string
+	Saved   bool               // The bookmark was saved
+	Updated bool               // An existing bookmark was updated instead of added
+	Current *bookmark.Bookmark // Existing bookmark for the URL, if any
+}
+
+// Bookmarklet returns a javascript: URL that opens the quick-add form of
+// the server at base, such as http://127.0.0.1:8899, for the current page
+// and the selected text
+func Bookmarklet(base string) string {
+	target, _ := json.Marshal(strings.TrimSuffix(base, "/") + "/add")
+	return "javascript:(function(){var e=encodeURIComponent;" +
+		"window.open(" + string(target) + "+'?url='+e(location.href)+'&title='+e(document.title)" +
+		"+'&text='+e(String(window.getSelection())),'moxli','width=520,height=600');})();"
+}
+
+// quickAddForm shows the quick-add form filled in from the query string.
+// Opening a URL only shows the form, so other sites can't add bookmarks by
+// loading it; saving takes a POST from the form itself.
+func (s *Server) quickAddForm(w http.ResponseWriter, r *http.Request) {
+	params := r.URL.Query()
+	page := addPage{
+		URL:   strings.TrimSpace(params.Get("url")),
+		Title: strings.TrimSpace(params.Get("title")),
+		Text:  strings.TrimSpace(params.Get("text")),
+		Tags:  params.Get("tags"),
+	}
+	if page.URL != "" {
+		if normalized, err := bookmark.NormalizeURL(page.URL); err == nil {
+			page.Current, _ = s.collection.FindByURL(normalized)
+		}
+	}
+	writePage(w, http.StatusOK, page)
+}
+
+// quickAdd saves the submitted form: a new URL is added and a bookmarked one
+// gets the new title, tags and selected text instead of a duplicate
+func (s *Server) quickAdd(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxBodyBytes)
+	if err := r.ParseForm(); err != nil {
+		writePage(w, http.StatusBadRequest, addPage{Error: "Invalid form: " + err.Error()})
+		return
+	}
+	page := addPage{
+		URL:   strings.TrimSpace(r.PostForm.Get("url")),
+		Title: strings.TrimSpace(r.PostForm.Get("title")),
+		Text:  strings.TrimSpace(r.PostForm.Get("text")),
+		Tags:  r.PostForm.Get("tags"),
+	}
+
+	b := &bookmark.Bookmark{URL: page.URL, Title: page.Title, Tags: bookmark.ParseTags(page.Tags), Comment: page.Text}
+	if page.URL == "" {
+		page.Error = "A URL is required."
+	} else if err := bookmark.NormalizeBookmarkURL(b); err != nil {
+		page.Error = "Invalid URL: " + err.Error()
+	}
+	if page.Error != "" {
+		writePage(w, http.StatusBadRequest, page)
+		return
+	}
+	bookmark.NormalizeTags(b)
+
+	var err error
+	if existing, ok := s.collection.FindByURL(b.NormalizedURL); ok {
+		page.Current, page.Updated = existing, true
+		err = s.update(existing, b)
+	} else {
+		b.ID = uuid.New().String()
+		b.DateAdded = time.Now()
+		err = s.apply("add "+displayTitle(b), []bookmark.Op{bookmark.AddOp(len(s.collection.Bookmarks), b)})
+		page.Current, _ = s.find(b.ID)
+	}
+	if err != nil {
+		page.Error = err.Error()
+		writePage(w, http.StatusInternalServerError, page)
+		return
+	}
+
+	page.Saved = true
+	writePage(w, http.StatusOK, page)
+}
+
+// update merges a quick-added bookmark into the existing one for its URL:
+// a new title replaces the old one, tags are added and the selected text is
+// appended to the comment
+func (s *Server) update(existing, added *bookmark.Bookmark) error {
+	before := existing.Clone()
+	if added.Title != "" {
+		existing.Title = added.Title
+	}
+	for _, tag := range added.Tags {
+		existing.AddTag(tag)
+	}
+	if added.Comment != "" && !strings.Contains(existing.Comment, added.Comment) {
+		existing.Comment = strings.TrimSpace(existing.Comment + "\n\n" + added.Comment)
+	}
+
+	if len(bookmark.DiffOps(before, existing)) == 0 {
+		return nil
+	}
+	existing.LastModified = time.Now()
+	return s.commit("update "+displayTitle(existing), bookmark.DiffOps(before, existing))
+}
+
+// bookmarkletPage shows the bookmarklet for this server, ready to drag to
+// the bookmarks bar
+func (s *Server) bookmarkletPage(w http.ResponseWriter, r *http.Request) {
+	code := Bookmarklet("http://" + r.Host)
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	// template.URL keeps the javascript: scheme, which is otherwise filtered
+	_ = bookmarkletTemplate.Execute(w, struct {
+		Link template.URL
+		Code string
+	}{template.URL(code), code})
+}
+
+func writePage(w http.ResponseWriter, status int, page addPage) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(status)
+	_ = addTemplate.Execute(w, page)
+}
+
+// pageStyle is shared by the HTML pages
+const pageStyle = `<style>
+body { font: 15px/1.5 system-ui, sans-serif; margin: 1.5em; max-width: 36em; color: #222; }
+label { display: block; margin-top: .8em; font-weight: 600; }
+input, textarea { width: 100%; box-sizing: border-box; font: inherit; padding: .3em; }
+button { margin-top: 1em; font: inherit; padding: .3em 1.2em; }
+.note { color: #666; }
+.error { color: #b00; }
+code, textarea.code { font-family: ui-monospace, monospace; font-size: 13px; }
+</style>`
+
+var addTemplate = template.Must(template.New("add").Funcs(template.FuncMap{"tags": bookmark.FormatTags}).Parse(`<!DOCTYPE html>
+<html><head><meta charset="utf-8"><title>moxli: {{if .Saved}}saved{{else}}add bookmark{{end}}</title>` + pageStyle + `</head>
+<body>
+{{- if .Saved}}
+<h1>{{if .Updated}}✏️ Updated{{else}}✅ Saved{{end}}</h1>
+<p><strong>{{.Current.Title}}</strong><br><a href="{{.Current.URL}}">{{.Current.URL}}</a></p>
+{{- with .Current.Tags}}<p class="note">Tags: {{tags .}}</p>{{end}}
+<button onclick="window.close()">Close</button>
+{{- else}}
+<h1>🔖 Add bookmark</h1>
+{{- with .Error}}<p class="error">{{.}}</p>{{end}}
+{{- with .Current}}<p class="note">Already bookmarked as “{{.Title}}”{{with .Tags}}, tagged {{tags .}}{{end}}. Saving updates it.</p>{{end}}
+<form method="post" action="/add">
+<label>URL <input name="url" value="{{.URL}}" required></label>
+<label>Title <input name="title" value="{{.Title}}"></label>
+<label>Tags <input name="tags" value="{{.Tags}}" placeholder="dev/go, reading" autofocus></label>
+<label>Note <textarea name="text" rows="6">{{.Text}}</textarea></label>
+<button type="submit">Save</button>
+</form>
+{{- end}}
+</body></html>
+`))
+
+var bookmarkletTemplate = template.Must(template.New("bookmarklet").Parse(`<!DOCTYPE html>
+<html><head><meta charset="utf-8"><title>moxli bookmarklet</title>` + pageStyle + `</head>
+<body>
+<h1>🔖 moxli bookmarklet</h1>
+<p>Drag this link to your bookmarks bar: <a href="{{.Link}}">+ moxli</a></p>
+<p class="note">Clicking it on any page opens a form to bookmark the page, with the selected text as a note. It needs <code>moxli serve</code> running at this address.</p>
+<label>Code <textarea class="code" rows="6" readonly>{{.Code}}</textarea></label>
+</body></html>
+`))
@@ -0,0 +1,87 @@
+package server
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestBookmarklet(t *testing.T) {
+	got := Bookmarklet("http://127.0.0.1:8899/")
+	for _, want := range []string{"javascript:", `"http://127.0.0.1:8899/add"`, "location.href", "document.title", "getSelection"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Bookmarklet() = %q, want it to contain %q", got, want)
+		}
+	}
+}
+
+func TestServer_QuickAddForm(t *testing.T) {
+	s, _ := newTestServer(t, Options{})
+
+	rec := do(s, "GET", "/add?url="+url.QueryEscape("https://example.net/page")+"&title=A+%3Cpage%3E&text=quoted", "", nil)
+	body := rec.Body.String()
+	if rec.Code != http.StatusOK || !strings.Contains(body, `value="A &lt;page&gt;"`) || !strings.Contains(body, ">quoted</textarea>") {
+		t.Errorf("form = %v %s, want the fields filled in and escaped", rec.Code, body)
+	}
+	if len(s.collection.Bookmarks) != 3 {
+		t.Errorf("opening the form added a bookmark")
+	}
+
+	rec = do(s, "GET", "/add?url="+url.QueryEscape("https://GO.dev/doc/"), "", nil)
+	if !strings.Contains(rec.Body.String(), "Already bookmarked as “Go docs”") {
+		t.Errorf("form for a bookmarked URL doesn't mention the bookmark: %s", rec.Body)
+	}
+}
+
+func TestServer_QuickAdd(t *testing.T) {
+	s, _ := newTestServer(t, Options{})
+	form := map[string]string{"Content-Type": "application/x-www-form-urlencoded", "Origin": "http://localhost:8899"}
+
+	rec := do(s, "POST", "/add", url.Values{"url": {"https://example.net/new"}, "title": {"New"}, "tags": {"Web Dev, reading"}}.Encode(), form)
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), "✅ Saved") {
+		t.Fatalf("add = %v %s, want a confirmation", rec.Code, rec.Body)
+	}
+	added, ok := s.collection.FindByURL("https://example.net/new")
+	if !ok || added.ID == "" || !added.HasTag([]string{"web-dev"}) || !added.HasTag([]string{"reading"}) {
+		t.Fatalf("added bookmark = %+v, want an ID and normalized tags", added)
+	}
+
+	rec = do(s, "POST", "/add", url.Values{"url": {"https://go.dev/doc/"}, "tags": {"reference"}, "text": {"A quote"}}.Encode(), form)
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), "✏️ Updated") {
+		t.Fatalf("update = %v %s, want an update confirmation", rec.Code, rec.Body)
+	}
+	b, _ := s.find("a")
+	if len(s.collection.Bookmarks) != 4 || b.Title != "Go docs" || !b.HasTag([]string{"reference"}) || !b.HasTag([]string{"lang", "go"}) || b.Comment != "A quote" {
+		t.Errorf("updated bookmark = %+v, want the title kept, the tag added and the text as comment", b)
+	}
+	if got := len(s.history.Entries); got != 2 {
+		t.Errorf("history has %v entries, want 2", got)
+	}
+
+	// Submitting the same again changes nothing
+	do(s, "POST", "/add", url.Values{"url": {"https://go.dev/doc/"}, "tags": {"reference"}, "text": {"A quote"}}.Encode(), form)
+	if b.Comment != "A quote" || len(s.history.Entries) != 2 {
+		t.Errorf("resubmitting changed the bookmark: comment %q, %v history entries", b.Comment, len(s.history.Entries))
+	}
+
+	rec = do(s, "POST", "/add", url.Values{"title": {"No URL"}}.Encode(), form)
+	if rec.Code != http.StatusBadRequest || !strings.Contains(rec.Body.String(), "A URL is required") {
+		t.Errorf("missing URL = %v, want 400 with the form", rec.Code)
+	}
+
+	// Other sites can't post the form
+	form["Origin"] = "https://evil.example"
+	if rec := do(s, "POST", "/add", url.Values{"url": {"https://evil.example"}}.Encode(), form); rec.Code != http.StatusForbidden {
+		t.Errorf("cross-site post status = %v, want 403", rec.Code)
+	}
+}
+
+func TestServer_BookmarkletPage(t *testing.T) {
+	s, _ := newTestServer(t, Options{})
+
+	rec := do(s, "GET", "/bookmarklet", "", nil)
+	if !strings.Contains(rec.Body.String(), `href="javascript:`) {
+		t.Errorf("bookmarklet page has no javascript: link: %s", rec.Body)
+	}
+}
@@ -0,0 +1,316 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/lelopez-io/moxli/internal/bookmark"
+	"github.com/lelopez-io/moxli/internal/fulltext"
+	"github.com/lelopez-io/moxli/internal/fuzzy"
+	"github.com/lelopez-io/moxli/internal/query"
+)
+
+// listResponse is a page of matching bookmarks
+type listResponse struct {
+	Total     int                  `json:"total"` // Matches before offset and limit
+	Bookmarks []*bookmark.Bookmark `json:"bookmarks"`
+}
+
+// listBookmarks lists the bookmarks matching q, filtered as in the TUI:
+// plain words are fuzzy-matched, "?words" is a full-text search and
+// anything else uses the query language. sort names a sort mode, and offset
+// and limit select a page of the results.
+func (s *Server) listBookmarks(w http.ResponseWriter, r *http.Request) {
+	if matchesETag(r.Header.Get("If-None-Match"), s.etag) {
+		w.Header().Set("ETag", s.etag)
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	params := r.URL.Query()
+	results, err := s.search(params.Get("q"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid query: %v", err)
+		return
+	}
+	if name := params.Get("sort"); name != "" {
+		mode, ok := bookmark.ParseSortMode(name)
+		if !ok {
+			writeError(w, http.StatusBadRequest, "unknown sort mode %q", name)
+			return
+		}
+		results = bookmark.Sort(results, mode)
+	}
+
+	offset, limit, err := pageParams(params)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "%v", err)
+		return
+	}
+	total := len(results)
+	results = results[min(offset, total):]
+	if limit > 0 && len(results) > limit {
+		results = results[:limit]
+	}
+
+	w.Header().Set("ETag", s.etag)
+	writeJSON(w, http.StatusOK, listResponse{Total: total, Bookmarks: append([]*bookmark.Bookmark{}, results...)})
+}
+
+// search returns the bookmarks matching input, in the order the TUI's
+// filter shows them
+func (s *Server) search(input string) ([]*bookmark.Bookmark, error) {
+	input = strings.TrimSpace(input)
+	switch {
+	case input == "":
+		return s.collection.Bookmarks, nil
+
+	case strings.HasPrefix(input, "?"):
+		if s.fulltextIndex == nil {
+			// Caching is best-effort; a failure still returns a usable index
+			s.fulltextIndex, _ = fulltext.LoadOrBuild(filepath.Join(s.opts.Manager.ConfigDir(), "index"),
+				s.opts.Path, s.collection.Bookmarks)
+		}
+		hits := s.fulltextIndex.Search(strings.TrimPrefix(input, "?"))
+		results := make([]*bookmark.Bookmark, len(hits))
+		for i, hit := range hits {
+			results[i] = s.collection.Bookmarks[hit.Doc]
+		}
+		return results, nil
+
+	case query.IsPlain(input):
+		if s.fuzzyIndex == nil {
+			s.fuzzyIndex = fuzzy.NewIndex(s.collection.Bookmarks)
+		}
+		matches := s.fuzzyIndex.Search(input)
+		results := make([]*bookmark.Bookmark, len(matches))
+		for i, m := range matches {
+			results[i] = m.Bookmark
+		}
+		return results, nil
+
+	default:
+		q, err := query.Parse(input)
+		if err != nil {
+			return nil, err
+		}
+		return q.Filter(s.collection.Bookmarks), nil
+	}
+}
+
+// pageParams reads the offset and limit query parameters
+func pageParams(params url.Values) (offset, limit int, err error) {
+	for _, p := range []struct {
+		name  string
+		value *int
+	}{{"offset", &offset}, {"limit", &limit}} {
+		raw := params.Get(p.name)
+		if raw == "" {
+			continue
+		}
+		if *p.value, err = strconv.Atoi(raw); err != nil || *p.value < 0 {
+			return 0, 0, fmt.Errorf("%s must be a non-negative number", p.name)
+		}
+	}
+	return offset, limit, nil
+}
+
+func (s *Server) getBookmark(w http.ResponseWriter, r *http.Request) {
+	b, ok := s.find(r.PathValue("id"))
+	if !ok {
+		writeError(w, http.StatusNotFound, "no bookmark with ID %s", r.PathValue("id"))
+		return
+	}
+	etag := bookmarkETag(b)
+	if matchesETag(r.Header.Get("If-None-Match"), etag) {
+		w.Header().Set("ETag", etag)
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	writeBookmark(w, http.StatusOK, b)
+}
+
+// createBookmark adds a bookmark. A URL already in the collection, as a
+// bookmark's URL or alias, is a conflict pointing at that bookmark.
+func (s *Server) createBookmark(w http.ResponseWriter, r *http.Request) {
+	var b bookmark.Bookmark
+	if err := decodeJSON(w, r, &b); err != nil {
+		writeError(w, http.StatusBadRequest, "%v", err)
+		return
+	}
+	if err := prepare(&b); err != nil {
+		writeError(w, http.StatusBadRequest, "%v", err)
+		return
+	}
+	if existing, ok := s.collection.FindByURL(b.NormalizedURL); ok {
+		w.Header().Set("Location", bookmarkPath(existing))
+		writeError(w, http.StatusConflict, "%s is already bookmarked as %s", b.URL, existing.ID)
+		return
+	}
+
+	b.ID = uuid.New().String()
+	if b.DateAdded.IsZero() {
+		b.DateAdded = time.Now()
+	}
+	if err := s.apply("add "+displayTitle(&b), []bookmark.Op{bookmark.AddOp(len(s.collection.Bookmarks), &b)}); err != nil {
+		writeError(w, http.StatusInternalServerError, "%v", err)
+		return
+	}
+
+	created, _ := s.find(b.ID)
+	w.Header().Set("Location", bookmarkPath(created))
+	writeBookmark(w, http.StatusCreated, created)
+}
+
+// replaceBookmark replaces a bookmark with the request body
+func (s *Server) replaceBookmark(w http.ResponseWriter, r *http.Request) {
+	s.updateBookmark(w, r, func(*bookmark.Bookmark) (*bookmark.Bookmark, error) {
+		var b bookmark.Bookmark
+		if err := decodeJSON(w, r, &b); err != nil {
+			return nil, err
+		}
+		return &b, nil
+	})
+}
+
+// patchBookmark changes the fields present in the request body, as a JSON
+// merge patch
+func (s *Server) patchBookmark(w http.ResponseWriter, r *http.Request) {
+	s.updateBookmark(w, r, func(current *bookmark.Bookmark) (*bookmark.Bookmark, error) {
+		var patch map[string]json.RawMessage
+		if err := decodeJSON(w, r, &patch); err != nil {
+			return nil, err
+		}
+
+		var fields map[string]json.RawMessage
+		data, _ := json.Marshal(current)
+		if err := json.Unmarshal(data, &fields); err != nil {
+			return nil, err
+		}
+		maps.Copy(fields, patch)
+		data, _ = json.Marshal(fields)
+
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.DisallowUnknownFields()
+		var b bookmark.Bookmark
+		if err := dec.Decode(&b); err != nil {
+			return nil, fmt.Errorf("invalid patch: %w", err)
+		}
+		return &b, nil
+	})
+}
+
+// updateBookmark replaces a bookmark with the version decode returns. Only
+// the fields the edit history records are taken from it; the ID, date added,
+// article, snapshot, link status and source stay as they are, since an undo
+// could not bring them back. The change is saved only when the bookmark
+// differs, and refused when the new URL belongs to another bookmark.
+func (s *Server) updateBookmark(w http.ResponseWriter, r *http.Request, decode func(current *bookmark.Bookmark) (*bookmark.Bookmark, error)) {
+	current, ok := s.find(r.PathValue("id"))
+	if !ok {
+		writeError(w, http.StatusNotFound, "no bookmark with ID %s", r.PathValue("id"))
+		return
+	}
+	if !checkIfMatch(w, r, bookmarkETag(current)) {
+		return
+	}
+
+	updated, err := decode(current)
+	if err == nil {
+		err = prepare(updated)
+	}
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "%v", err)
+		return
+	}
+	if existing, ok := s.collection.FindByURL(updated.NormalizedURL); ok && existing != current {
+		w.Header().Set("Location", bookmarkPath(existing))
+		writeError(w, http.StatusConflict, "%s is already bookmarked as %s", updated.URL, existing.ID)
+		return
+	}
+
+	edited := current.Clone()
+	edited.URL = updated.URL
+	edited.NormalizedURL = updated.NormalizedURL
+	edited.Title = updated.Title
+	edited.Description = updated.Description
+	edited.Comment = updated.Comment
+	edited.Keyword = updated.Keyword
+	edited.Folder = updated.Folder
+	edited.Tags = updated.Tags
+	edited.IsStarred = updated.IsStarred
+	edited.Aliases = updated.Aliases
+	edited.Canonical = updated.Canonical
+	edited.Favicon = updated.Favicon
+
+	if bookmarkETag(edited) != bookmarkETag(current) {
+		edited.LastModified = time.Now()
+		if err := s.apply("edit "+displayTitle(edited), bookmark.DiffOps(current, edited)); err != nil {
+			writeError(w, http.StatusInternalServerError, "%v", err)
+			return
+		}
+		current, _ = s.find(edited.ID)
+	}
+	writeBookmark(w, http.StatusOK, current)
+}
+
+func (s *Server) deleteBookmark(w http.ResponseWriter, r *http.Request) {
+	b, ok := s.find(r.PathValue("id"))
+	if !ok {
+		writeError(w, http.StatusNotFound, "no bookmark with ID %s", r.PathValue("id"))
+		return
+	}
+	if !checkIfMatch(w, r, bookmarkETag(b)) {
+		return
+	}
+	if err := s.apply("delete "+displayTitle(b), []bookmark.Op{bookmark.RemoveOp(b)}); err != nil {
+		writeError(w, http.StatusInternalServerError, "%v", err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// prepare checks a bookmark sent by a client and normalizes its URL and tags
+func prepare(b *bookmark.Bookmark) error {
+	b.URL = strings.TrimSpace(b.URL)
+	if b.URL == "" {
+		return errors.New("url is required")
+	}
+	normalized, err := bookmark.NormalizeURL(b.URL)
+	if err != nil {
+		return fmt.Errorf("invalid url: %w", err)
+	}
+	b.NormalizedURL = normalized
+	bookmark.NormalizeTags(b)
+	return nil
+}
+
+// writeBookmark answers with a bookmark and its ETag
+func writeBookmark(w http.ResponseWriter, status int, b *bookmark.Bookmark) {
+	w.Header().Set("ETag", bookmarkETag(b))
+	writeJSON(w, status, b)
+}
+
+// bookmarkPath returns the API path of a bookmark
+func bookmarkPath(b *bookmark.Bookmark) string {
+	return "/api/bookmarks/" + url.PathEscape(b.ID)
+}
+
+// displayTitle names a bookmark in history labels
+func displayTitle(b *bookmark.Bookmark) string {
+	if b.Title != "" {
+		return b.Title
+	}
+	return b.URL
+}
@@ -0,0 +1,240 @@
+package server
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/lelopez-io/moxli/internal/bookmark"
+)
+
+func TestServer_ListBookmarks(t *testing.T) {
+	s, _ := newTestServer(t, Options{})
+
+	tests := []struct {
+		name   string
+		target string
+		want   []string // Titles in order
+		total  int
+	}{
+		{"all", "/api/bookmarks", []string{"Go docs", "Rust book", "No ID"}, 3},
+		{"fuzzy", "/api/bookmarks?q=rust", []string{"Rust book"}, 1},
+		{"query", "/api/bookmarks?q=tag:lang", []string{"Go docs", "Rust book"}, 2},
+		{"sorted", "/api/bookmarks?sort=title", []string{"Go docs", "No ID", "Rust book"}, 3},
+		{"page", "/api/bookmarks?offset=1&limit=1", []string{"Rust book"}, 3},
+		{"past the end", "/api/bookmarks?offset=10", []string{}, 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := do(s, "GET", tt.target, "", nil)
+			if rec.Code != http.StatusOK {
+				t.Fatalf("status = %v, want 200: %s", rec.Code, rec.Body)
+			}
+			var resp listResponse
+			decode(t, rec, &resp)
+
+			var got []string
+			for _, b := range resp.Bookmarks {
+				got = append(got, b.Title)
+			}
+			if resp.Total != tt.total || len(got) != len(tt.want) {
+				t.Fatalf("titles = %v (total %v), want %v (total %v)", got, resp.Total, tt.want, tt.total)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("titles = %v, want %v", got, tt.want)
+					break
+				}
+			}
+		})
+	}
+
+	for _, target := range []string{"/api/bookmarks?sort=nope", "/api/bookmarks?limit=-1", "/api/bookmarks?q=tag:("} {
+		if rec := do(s, "GET", target, "", nil); rec.Code != http.StatusBadRequest {
+			t.Errorf("GET %s status = %v, want 400", target, rec.Code)
+		}
+	}
+}
+
+func TestServer_ListBookmarks_NotModified(t *testing.T) {
+	s, _ := newTestServer(t, Options{})
+
+	etag := do(s, "GET", "/api/bookmarks", "", nil).Header().Get("ETag")
+	if etag == "" {
+		t.Fatal("no ETag on the bookmark list")
+	}
+	rec := do(s, "GET", "/api/bookmarks", "", map[string]string{"If-None-Match": etag})
+	if rec.Code != http.StatusNotModified {
+		t.Errorf("status = %v, want 304", rec.Code)
+	}
+
+	do(s, "DELETE", "/api/bookmarks/a", "", nil)
+	rec = do(s, "GET", "/api/bookmarks", "", map[string]string{"If-None-Match": etag})
+	if rec.Code != http.StatusOK {
+		t.Errorf("status after a change = %v, want 200", rec.Code)
+	}
+}
+
+func TestServer_GetBookmark(t *testing.T) {
+	s, _ := newTestServer(t, Options{})
+
+	rec := do(s, "GET", "/api/bookmarks/a", "", nil)
+	var b bookmark.Bookmark
+	decode(t, rec, &b)
+	if rec.Code != http.StatusOK || b.Title != "Go docs" {
+		t.Errorf("GET a = %v %q, want 200 Go docs", rec.Code, b.Title)
+	}
+
+	etag := rec.Header().Get("ETag")
+	if rec := do(s, "GET", "/api/bookmarks/a", "", map[string]string{"If-None-Match": etag}); rec.Code != http.StatusNotModified {
+		t.Errorf("status with a matching If-None-Match = %v, want 304", rec.Code)
+	}
+	if rec := do(s, "GET", "/api/bookmarks/missing", "", nil); rec.Code != http.StatusNotFound {
+		t.Errorf("status of a missing bookmark = %v, want 404", rec.Code)
+	}
+}
+
+func TestServer_CreateBookmark(t *testing.T) {
+	s, _ := newTestServer(t, Options{})
+
+	rec := do(s, "POST", "/api/bookmarks", `{"url": "https://Example.com/new/", "title": "New", "tags": [["Web Dev"]]}`, nil)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("status = %v, want 201: %s", rec.Code, rec.Body)
+	}
+	var b bookmark.Bookmark
+	decode(t, rec, &b)
+	if b.ID == "" || b.DateAdded.IsZero() {
+		t.Errorf("created bookmark ID = %q, DateAdded = %v, want both set", b.ID, b.DateAdded)
+	}
+	if want := "/api/bookmarks/" + b.ID; rec.Header().Get("Location") != want {
+		t.Errorf("Location = %q, want %q", rec.Header().Get("Location"), want)
+	}
+	if !b.HasTag([]string{"web-dev"}) {
+		t.Errorf("tags = %v, want normalized [[web-dev]]", b.Tags)
+	}
+	if got := len(s.collection.Bookmarks); got != 4 {
+		t.Errorf("collection has %v bookmarks, want 4", got)
+	}
+	if s.history.Cursor != 1 || s.history.Entries[0].Label != "add New" {
+		t.Errorf("history = %+v, want one \"add New\" entry", s.history.Entries)
+	}
+
+	rec = do(s, "POST", "/api/bookmarks", `{"url": "https://go.dev/doc/"}`, nil)
+	if rec.Code != http.StatusConflict || rec.Header().Get("Location") != "/api/bookmarks/a" {
+		t.Errorf("duplicate status = %v, Location = %q, want 409 /api/bookmarks/a", rec.Code, rec.Header().Get("Location"))
+	}
+
+	for _, body := range []string{`{"title": "No URL"}`, `{"url": "https://x.example", "bogus": 1}`, `not json`} {
+		if rec := do(s, "POST", "/api/bookmarks", body, nil); rec.Code != http.StatusBadRequest {
+			t.Errorf("POST %s status = %v, want 400", body, rec.Code)
+		}
+	}
+}
+
+func TestServer_UpdateBookmark(t *testing.T) {
+	s, _ := newTestServer(t, Options{})
+	etag := do(s, "GET", "/api/bookmarks/a", "", nil).Header().Get("ETag")
+
+	rec := do(s, "PATCH", "/api/bookmarks/a", `{"title": "Go documentation"}`, map[string]string{"If-Match": etag})
+	if rec.Code != http.StatusOK {
+		t.Fatalf("PATCH status = %v, want 200: %s", rec.Code, rec.Body)
+	}
+	var b bookmark.Bookmark
+	decode(t, rec, &b)
+	if b.Title != "Go documentation" || b.URL != "https://go.dev/doc" || !b.IsStarred || b.LastModified.IsZero() {
+		t.Errorf("patched bookmark = %+v, want the new title, other fields kept and LastModified set", b)
+	}
+
+	// The old ETag no longer matches
+	rec = do(s, "PATCH", "/api/bookmarks/a", `{"title": "Lost update"}`, map[string]string{"If-Match": etag})
+	if rec.Code != http.StatusPreconditionFailed {
+		t.Errorf("stale If-Match status = %v, want 412", rec.Code)
+	}
+
+	current, _ := s.find("a")
+	current.Article = "Saved text"
+	current.Source = "anybox"
+	rec = do(s, "PUT", "/api/bookmarks/a", `{"url": "https://go.dev/ref/spec", "title": "Spec", "dateAdded": "2030-01-01T00:00:00Z", "article": ""}`, nil)
+	decode(t, rec, &b)
+	if b.ID != "a" || b.Title != "Spec" || b.IsStarred || b.DateAdded.Year() != 2024 {
+		t.Errorf("replaced bookmark = %+v, want ID and date added kept and other fields replaced", b)
+	}
+	if b.Article != "Saved text" || b.Source != "anybox" {
+		t.Errorf("replaced bookmark article = %q, source = %q, want both kept", b.Article, b.Source)
+	}
+	if _, ok := s.collection.FindByURL("https://go.dev/ref/spec"); !ok {
+		t.Errorf("collection index doesn't find the new URL")
+	}
+
+	entries := len(s.history.Entries)
+	unchanged := rec.Header().Get("ETag")
+	rec = do(s, "PATCH", "/api/bookmarks/a", `{"title": "Spec"}`, nil)
+	if rec.Header().Get("ETag") != unchanged || len(s.history.Entries) != entries {
+		t.Errorf("a patch changing nothing changed the bookmark or its history")
+	}
+
+	if rec := do(s, "PATCH", "/api/bookmarks/a", `{"nope": true}`, nil); rec.Code != http.StatusBadRequest {
+		t.Errorf("unknown field status = %v, want 400", rec.Code)
+	}
+
+	for _, method := range []string{"PATCH", "PUT"} {
+		rec = do(s, method, "/api/bookmarks/a", `{"url": "https://example.com/rust/"}`, nil)
+		if rec.Code != http.StatusConflict || rec.Header().Get("Location") != "/api/bookmarks/b" {
+			t.Errorf("%s to another bookmark's URL status = %v, Location = %q, want 409 /api/bookmarks/b", method, rec.Code, rec.Header().Get("Location"))
+		}
+	}
+	if current.URL != "https://go.dev/ref/spec" || len(s.history.Entries) != entries {
+		t.Errorf("a conflicting update changed the bookmark or its history")
+	}
+}
+
+func TestServer_DeleteBookmark(t *testing.T) {
+	s, _ := newTestServer(t, Options{})
+
+	if rec := do(s, "DELETE", "/api/bookmarks/b", "", map[string]string{"If-Match": `"stale"`}); rec.Code != http.StatusPreconditionFailed {
+		t.Errorf("stale If-Match status = %v, want 412", rec.Code)
+	}
+	if rec := do(s, "DELETE", "/api/bookmarks/b", "", nil); rec.Code != http.StatusNoContent {
+		t.Errorf("status = %v, want 204", rec.Code)
+	}
+	if _, ok := s.find("b"); ok {
+		t.Errorf("bookmark b still in the collection")
+	}
+	if rec := do(s, "DELETE", "/api/bookmarks/b", "", nil); rec.Code != http.StatusNotFound {
+		t.Errorf("second delete status = %v, want 404", rec.Code)
+	}
+
+	// Undoing the recorded entry brings the bookmark back
+	reloaded, err := s.opts.Manager.LoadHistory(s.opts.Path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := reloaded.Undo(s.collection); err != nil {
+		t.Fatalf("Undo() error = %v", err)
+	}
+	if _, ok := s.find("b"); !ok {
+		t.Errorf("undo didn't restore bookmark b")
+	}
+}
+
+func TestPrepare(t *testing.T) {
+	tests := []struct {
+		name    string
+		b       bookmark.Bookmark
+		wantErr bool
+	}{
+		{"valid", bookmark.Bookmark{URL: " https://example.com "}, false},
+		{"missing URL", bookmark.Bookmark{Title: "x"}, true},
+		{"invalid URL", bookmark.Bookmark{URL: "://"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := prepare(&tt.b)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("prepare() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && (tt.b.URL != "https://example.com" || tt.b.NormalizedURL == "") {
+				t.Errorf("prepare() URL = %q, NormalizedURL = %q", tt.b.URL, tt.b.NormalizedURL)
+			}
+		})
+	}
+}
@@ -0,0 +1,323 @@
+// Package server exposes a bookmark file over a local REST API for scripts
+// and browser extensions. Every change is written to the file at once and
+// recorded in its edit history, so it can be undone in the TUI or with
+// `moxli history`. Responses carry ETags and changes honour If-Match, so
+// clients editing the same file don't overwrite each other's work. A
+// quick-add form and bookmarklet add the page open in the browser.
+package server
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lelopez-io/moxli/internal/bookmark"
+	"github.com/lelopez-io/moxli/internal/exporter"
+	"github.com/lelopez-io/moxli/internal/fulltext"
+	"github.com/lelopez-io/moxli/internal/fuzzy"
+	"github.com/lelopez-io/moxli/internal/importer"
+	"github.com/lelopez-io/moxli/internal/session"
+)
+
+// maxBodyBytes caps the size of JSON request bodies
+const maxBodyBytes = 1 << 20
+
+// Options configures a Server
+type Options struct {
+	Path         string           // Anybox JSON file served
+	Manager      *session.Manager // Keeps the file's edit history and search index
+	AllowOrigins []string         // Browser origins allowed to call the API, such as an extension's
+}
+
+// Server serves one bookmark file. Requests are handled one at a time.
+type Server struct {
+	opts Options
+	mux  *http.ServeMux
+
+	mu         sync.Mutex
+	collection *bookmark.Collection
+	history    *bookmark.History
+	etag       string    // ETag of the collection, from the file's contents
+	modTime    time.Time // File state when loaded, to notice other programs saving it
+	size       int64
+
+	fuzzyIndex    *fuzzy.Index    // Built on first use, dropped after changes
+	fulltextIndex *fulltext.Index // Built on first use, dropped after changes
+}
+
+// New loads the file at opts.Path and creates a Server for it. Bookmarks
+// without an ID are given one, and the file saved, so every bookmark can be
+// addressed by the API.
+func New(opts Options) (*Server, error) {
+	s := &Server{opts: opts, mux: http.NewServeMux()}
+	if err := s.open(); err != nil {
+		return nil, err
+	}
+	s.routes()
+	return s, nil
+}
+
+func (s *Server) routes() {
+	s.mux.HandleFunc("GET /api/bookmarks", s.listBookmarks)
+	s.mux.HandleFunc("POST /api/bookmarks", s.createBookmark)
+	s.mux.HandleFunc("GET /api/bookmarks/{id}", s.getBookmark)
+	s.mux.HandleFunc("PUT /api/bookmarks/{id}", s.replaceBookmark)
+	s.mux.HandleFunc("PATCH /api/bookmarks/{id}", s.patchBookmark)
+	s.mux.HandleFunc("DELETE /api/bookmarks/{id}", s.deleteBookmark)
+	s.mux.HandleFunc("POST /api/bookmarks/{id}/tags", s.addBookmarkTags)
+	s.mux.HandleFunc("DELETE /api/bookmarks/{id}/tags/{tag...}", s.removeBookmarkTag)
+
+	s.mux.HandleFunc("GET /api/tags", s.listTags)
+	s.mux.HandleFunc("POST /api/tags/rename", s.renameTag)
+	s.mux.HandleFunc("DELETE /api/tags/{tag...}", s.deleteTag)
+
+	s.mux.HandleFunc("GET /api/formats", s.listFormats)
+	s.mux.HandleFunc("GET /api/export", s.export)
+	s.mux.HandleFunc("POST /api/import", s.importFile)
+
+	s.mux.HandleFunc("GET /add", s.quickAddForm)
+	s.mux.HandleFunc("POST /add", s.quickAdd)
+	s.mux.HandleFunc("GET /bookmarklet", s.bookmarkletPage)
+}
+
+// ServeHTTP answers requests addressed to localhost from programs, the
+// server's own pages and the allowed browser origins. Other origins are
+// refused, so web pages can't read or change the collection.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !isLocalHost(r.Host) {
+		// A foreign Host means a DNS rebinding attempt or a request that
+		// was not meant for this server
+		writeError(w, http.StatusForbidden, "host %s not allowed", r.Host)
+		return
+	}
+
+	if origin := r.Header.Get("Origin"); origin != "" && origin != "http://"+r.Host {
+		if !slices.Contains(s.opts.AllowOrigins, origin) {
+			writeError(w, http.StatusForbidden, "origin %s not allowed", origin)
+			return
+		}
+		w.Header().Set("Access-Control-Allow-Origin", origin)
+		w.Header().Set("Access-Control-Expose-Headers", "ETag, Location")
+		w.Header().Add("Vary", "Origin")
+		if r.Method == http.MethodOptions {
+			w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE")
+			w.Header().Set("Access-Control-Allow-Headers", "Content-Type, If-Match, If-None-Match")
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.refresh(); err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to reload %s: %v", s.opts.Path, err)
+		return
+	}
+	s.mux.ServeHTTP(w, r)
+}
+
+// isLocalHost reports whether a Host header names this machine
+func isLocalHost(hostport string) bool {
+	host, _, err := net.SplitHostPort(hostport)
+	if err != nil {
+		host = hostport
+	}
+	host = strings.Trim(host, "[]")
+	if strings.EqualFold(host, "localhost") {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// open loads the file, saving it when bookmarks had to be given IDs
+func (s *Server) open() error {
+	if err := s.load(); err != nil {
+		return err
+	}
+	if s.collection.EnsureIDs() > 0 {
+		return s.save()
+	}
+	return nil
+}
+
+// load reads the file and its edit history
+func (s *Server) load() error {
+	info, err := os.Stat(s.opts.Path)
+	if err != nil {
+		return err
+	}
+	data, err := os.ReadFile(s.opts.Path)
+	if err != nil {
+		return fmt.Errorf("failed to read file: %w", err)
+	}
+
+	imp := importer.Detect(data)
+	if imp == nil || imp.Source() != "anybox" {
+		return fmt.Errorf("%s is not Anybox JSON", s.opts.Path)
+	}
+	collection, err := imp.Parse(bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("failed to load %s: %w", s.opts.Path, err)
+	}
+	history, err := s.opts.Manager.LoadHistory(s.opts.Path)
+	if err != nil {
+		return err
+	}
+
+	s.collection = collection
+	s.history = history
+	s.etag = etagOf(data)
+	s.modTime, s.size = info.ModTime(), info.Size()
+	s.fuzzyIndex, s.fulltextIndex = nil, nil
+	return nil
+}
+
+// refresh reloads the file when another program, such as the TUI, saved it
+func (s *Server) refresh() error {
+	info, err := os.Stat(s.opts.Path)
+	if err != nil {
+		return err
+	}
+	if info.ModTime().Equal(s.modTime) && info.Size() == s.size {
+		return nil
+	}
+	return s.open()
+}
+
+// revert drops unsaved changes by loading the file again
+func (s *Server) revert() {
+	if err := s.load(); err == nil {
+		s.collection.EnsureIDs()
+	}
+}
+
+// save writes the collection and its history back to the file
+func (s *Server) save() error {
+	if _, err := exporter.WriteFile(s.opts.Path, &exporter.AnyboxExporter{Indent: true}, s.collection); err != nil {
+		return err
+	}
+	if err := s.opts.Manager.SaveHistory(s.opts.Path, s.history); err != nil {
+		return err
+	}
+
+	info, err := os.Stat(s.opts.Path)
+	if err != nil {
+		return err
+	}
+	data, err := os.ReadFile(s.opts.Path)
+	if err != nil {
+		return err
+	}
+	s.etag = etagOf(data)
+	s.modTime, s.size = info.ModTime(), info.Size()
+	s.fuzzyIndex, s.fulltextIndex = nil, nil
+	return nil
+}
+
+// commit saves the collection after ops were applied to it and records them
+// as one undoable action. A failed save reloads the file, dropping the
+// unsaved change.
+func (s *Server) commit(label string, ops []bookmark.Op) error {
+	s.history.Record(label, ops)
+	s.collection.Reindex()
+	s.collection.UpdateMetadata()
+	if err := s.save(); err != nil {
+		s.revert()
+		return fmt.Errorf("failed to save %s: %w", s.opts.Path, err)
+	}
+	return nil
+}
+
+// apply applies ops to the collection and commits them
+func (s *Server) apply(label string, ops []bookmark.Op) error {
+	applied, err := bookmark.ApplyOps(s.collection, ops)
+	if err != nil {
+		s.revert()
+		return err
+	}
+	return s.commit(label, applied)
+}
+
+// find returns the bookmark with the given ID
+func (s *Server) find(id string) (*bookmark.Bookmark, bool) {
+	for _, b := range s.collection.Bookmarks {
+		if b.ID == id {
+			return b, true
+		}
+	}
+	return nil, false
+}
+
+// etagOf returns a strong ETag for data
+func etagOf(data []byte) string {
+	sum := sha256.Sum256(data)
+	return `"` + hex.EncodeToString(sum[:12]) + `"`
+}
+
+// bookmarkETag returns the ETag of a bookmark's JSON representation
+func bookmarkETag(b *bookmark.Bookmark) string {
+	data, _ := json.Marshal(b)
+	return etagOf(data)
+}
+
+// matchesETag reports whether an If-Match or If-None-Match header lists
+// etag or is "*"
+func matchesETag(header, etag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// checkIfMatch answers 412 Precondition Failed and returns false when the
+// request's If-Match header doesn't list etag. Requests without If-Match
+// always pass.
+func checkIfMatch(w http.ResponseWriter, r *http.Request, etag string) bool {
+	header := r.Header.Get("If-Match")
+	if header == "" || matchesETag(header, etag) {
+		return true
+	}
+	w.Header().Set("ETag", etag)
+	writeError(w, http.StatusPreconditionFailed, "changed since it was read (ETag is now %s)", etag)
+	return false
+}
+
+// errorResponse is the body of every error response
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	_ = enc.Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, format string, args ...any) {
+	writeJSON(w, status, errorResponse{Error: fmt.Sprintf(format, args...)})
+}
+
+// decodeJSON reads a JSON request body into v, rejecting unknown fields
+func decodeJSON(w http.ResponseWriter, r *http.Request, v any) error {
+	dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBodyBytes))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		return fmt.Errorf("invalid JSON body: %w", err)
+	}
+	return nil
+}
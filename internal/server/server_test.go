@@ -0,0 +1,201 @@
+package server
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lelopez-io/moxli/internal/exporter"
+	"github.com/lelopez-io/moxli/internal/session"
+)
+
+const testBookmarks = `[
+  {"id": "a", "url": "https://go.dev/doc", "title": "Go docs", "tags": [["lang", "go"]], "isStarred": true, "keyword": "", "dateAdded": "2024-01-01T00:00:00Z"},
+  {"id": "b", "url": "https://example.com/rust", "title": "Rust book", "tags": [["lang", "rust"], ["books"]], "isStarred": false, "keyword": "", "dateAdded": "2024-02-01T00:00:00Z"},
+  {"url": "https://example.com/no-id", "title": "No ID", "tags": [], "isStarred": false, "keyword": "", "dateAdded": "2024-03-01T00:00:00Z"}
+]`
+
+// newTestServer serves a copy of testBookmarks, returning the server and
+// the file's path
+func newTestServer(t *testing.T, opts Options) (*Server, string) {
+	t.Helper()
+	t.Setenv("HOME", t.TempDir())
+
+	path := filepath.Join(t.TempDir(), "bookmarks.json")
+	if err := os.WriteFile(path, []byte(testBookmarks), 0644); err != nil {
+		t.Fatal(err)
+	}
+	manager, err := session.NewManager()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	opts.Path, opts.Manager = path, manager
+	s, err := New(opts)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	return s, path
+}
+
+// do sends a request to localhost and returns the response
+func do(s *Server, method, target, body string, header map[string]string) *httptest.ResponseRecorder {
+	var r io.Reader
+	if body != "" {
+		r = strings.NewReader(body)
+	}
+	req := httptest.NewRequest(method, target, r)
+	req.Host = "localhost:8899"
+	for k, v := range header {
+		req.Header.Set(k, v)
+	}
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, req)
+	return rec
+}
+
+// decode reads a JSON response body into v
+func decode(t *testing.T, rec *httptest.ResponseRecorder, v any) {
+	t.Helper()
+	if err := json.Unmarshal(rec.Body.Bytes(), v); err != nil {
+		t.Fatalf("invalid JSON response %q: %v", rec.Body.String(), err)
+	}
+}
+
+func TestNew_AssignsIDs(t *testing.T) {
+	s, path := newTestServer(t, Options{})
+
+	for _, b := range s.collection.Bookmarks {
+		if b.ID == "" {
+			t.Errorf("bookmark %s has no ID", b.URL)
+		}
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), `"id": ""`) {
+		t.Errorf("saved file still has a bookmark without an ID")
+	}
+	if _, err := os.Stat(path + exporter.BackupSuffix); err != nil {
+		t.Errorf("no backup of the original file: %v", err)
+	}
+}
+
+func TestNew_RejectsOtherFormats(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	path := filepath.Join(t.TempDir(), "bookmarks.html")
+	if err := os.WriteFile(path, []byte("<!DOCTYPE NETSCAPE-Bookmark-file-1>"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	manager, _ := session.NewManager()
+	if _, err := New(Options{Path: path, Manager: manager}); err == nil {
+		t.Errorf("New() error = nil, want an error for a file that isn't Anybox JSON")
+	}
+}
+
+func TestServer_HostAndOrigin(t *testing.T) {
+	s, _ := newTestServer(t, Options{AllowOrigins: []string{"moz-extension://moxli"}})
+
+	tests := []struct {
+		name   string
+		method string
+		host   string
+		origin string
+		want   int
+	}{
+		{"localhost", "GET", "localhost:8899", "", http.StatusOK},
+		{"loopback IPv4", "GET", "127.0.0.1:8899", "", http.StatusOK},
+		{"loopback IPv6", "GET", "[::1]:8899", "", http.StatusOK},
+		{"foreign host", "GET", "evil.example:8899", "", http.StatusForbidden},
+		{"allowed origin", "GET", "localhost", "moz-extension://moxli", http.StatusOK},
+		{"preflight", "OPTIONS", "localhost", "moz-extension://moxli", http.StatusNoContent},
+		{"other origin", "GET", "localhost", "https://evil.example", http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, "/api/tags", nil)
+			req.Host = tt.host
+			if tt.origin != "" {
+				req.Header.Set("Origin", tt.origin)
+			}
+			rec := httptest.NewRecorder()
+			s.ServeHTTP(rec, req)
+
+			if rec.Code != tt.want {
+				t.Errorf("status = %v, want %v", rec.Code, tt.want)
+			}
+			allowed := rec.Header().Get("Access-Control-Allow-Origin")
+			if tt.want != http.StatusForbidden && allowed != tt.origin {
+				t.Errorf("Access-Control-Allow-Origin = %q, want %q", allowed, tt.origin)
+			}
+		})
+	}
+}
+
+func TestServer_ReloadsChangedFile(t *testing.T) {
+	s, path := newTestServer(t, Options{})
+
+	replaced := `[{"id": "z", "url": "https://example.org/", "title": "Only", "tags": [], "isStarred": false, "keyword": "", "dateAdded": "2024-01-01T00:00:00Z"}]`
+	if err := os.WriteFile(path, []byte(replaced), 0644); err != nil {
+		t.Fatal(err)
+	}
+	// Make sure the change is seen even on filesystems with coarse mtimes
+	later := time.Now().Add(time.Minute)
+	if err := os.Chtimes(path, later, later); err != nil {
+		t.Fatal(err)
+	}
+
+	rec := do(s, "GET", "/api/bookmarks", "", nil)
+	var resp listResponse
+	decode(t, rec, &resp)
+	if resp.Total != 1 || resp.Bookmarks[0].ID != "z" {
+		t.Errorf("bookmarks after the file changed = %v, want only z", resp.Bookmarks)
+	}
+}
+
+func TestMatchesETag(t *testing.T) {
+	tests := []struct {
+		header string
+		want   bool
+	}{
+		{`"abc"`, true},
+		{`W/"abc"`, true},
+		{`"x", "abc"`, true},
+		{`*`, true},
+		{`"x"`, false},
+		{``, false},
+	}
+	for _, tt := range tests {
+		if got := matchesETag(tt.header, `"abc"`); got != tt.want {
+			t.Errorf("matchesETag(%q) = %v, want %v", tt.header, got, tt.want)
+		}
+	}
+}
+
+func TestIsLocalHost(t *testing.T) {
+	tests := []struct {
+		host string
+		want bool
+	}{
+		{"localhost", true},
+		{"LOCALHOST:80", true},
+		{"127.0.0.1:8899", true},
+		{"127.1.2.3", true},
+		{"[::1]:8899", true},
+		{"192.168.1.10:8899", false},
+		{"localhost.evil.example", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		if got := isLocalHost(tt.host); got != tt.want {
+			t.Errorf("isLocalHost(%q) = %v, want %v", tt.host, got, tt.want)
+		}
+	}
+}
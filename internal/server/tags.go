@@ -0,0 +1,187 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/lelopez-io/moxli/internal/bookmark"
+)
+
+// tagInfo is one tag of the collection's tag hierarchy
+type tagInfo struct {
+	Tag   string `json:"tag"`   // Slash-separated path, such as "security/auth"
+	Count int    `json:"count"` // Bookmarks tagged with exactly this path
+	Total int    `json:"total"` // Bookmarks tagged with this path or one nested under it
+}
+
+// tagsRequest is the body adding tags to a bookmark
+type tagsRequest struct {
+	Tags []string `json:"tags"`
+}
+
+// renameRequest is the body renaming a tag
+type renameRequest struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// changedResponse reports how many bookmarks a collection-wide change touched
+type changedResponse struct {
+	Changed int `json:"changed"`
+}
+
+// listTags lists every tag with its bookmark counts, parents first
+func (s *Server) listTags(w http.ResponseWriter, r *http.Request) {
+	tags := []tagInfo{}
+	s.collection.TagTree().Walk(func(node *bookmark.TagNode, depth int) {
+		if depth > 0 {
+			tags = append(tags, tagInfo{Tag: strings.Join(node.Path, "/"), Count: node.Count, Total: node.Total})
+		}
+	})
+	w.Header().Set("ETag", s.etag)
+	writeJSON(w, http.StatusOK, tags)
+}
+
+// addBookmarkTags adds the tags in the request body to a bookmark
+func (s *Server) addBookmarkTags(w http.ResponseWriter, r *http.Request) {
+	var req tagsRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, "%v", err)
+		return
+	}
+	var paths [][]string
+	for _, tag := range req.Tags {
+		path := parseTag(tag)
+		if path == nil {
+			writeError(w, http.StatusBadRequest, "invalid tag %q", tag)
+			return
+		}
+		paths = append(paths, path)
+	}
+
+	s.changeBookmark(w, r, "tag", func(b *bookmark.Bookmark) bool {
+		added := false
+		for _, path := range paths {
+			added = b.AddTag(path) || added
+		}
+		return added
+	})
+}
+
+// removeBookmarkTag removes a tag, and the tags nested under it, from a
+// bookmark
+func (s *Server) removeBookmarkTag(w http.ResponseWriter, r *http.Request) {
+	path := parseTag(r.PathValue("tag"))
+	if b, ok := s.find(r.PathValue("id")); ok && !b.Clone().RemoveTag(path) {
+		writeError(w, http.StatusNotFound, "bookmark %s has no tag %s", b.ID, r.PathValue("tag"))
+		return
+	}
+
+	s.changeBookmark(w, r, "untag", func(b *bookmark.Bookmark) bool {
+		return b.RemoveTag(path)
+	})
+}
+
+// changeBookmark applies change to the bookmark named in the request path
+// and, when it reports a change, saves it as one history entry labelled
+// with verb and the bookmark's title
+func (s *Server) changeBookmark(w http.ResponseWriter, r *http.Request, verb string, change func(b *bookmark.Bookmark) bool) {
+	b, ok := s.find(r.PathValue("id"))
+	if !ok {
+		writeError(w, http.StatusNotFound, "no bookmark with ID %s", r.PathValue("id"))
+		return
+	}
+	if !checkIfMatch(w, r, bookmarkETag(b)) {
+		return
+	}
+
+	before := b.Clone()
+	if change(b) {
+		b.LastModified = time.Now()
+		if err := s.commit(verb+" "+displayTitle(b), bookmark.DiffOps(before, b)); err != nil {
+			writeError(w, http.StatusInternalServerError, "%v", err)
+			return
+		}
+		b, _ = s.find(before.ID)
+	}
+	writeBookmark(w, http.StatusOK, b)
+}
+
+// renameTag renames a tag on every bookmark, along with the tags nested
+// under it
+func (s *Server) renameTag(w http.ResponseWriter, r *http.Request) {
+	var req renameRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, "%v", err)
+		return
+	}
+	from, to := parseTag(req.From), parseTag(req.To)
+	if from == nil || to == nil {
+		writeError(w, http.StatusBadRequest, "from and to must both be tags")
+		return
+	}
+
+	label := fmt.Sprintf("rename tag %s to %s", strings.Join(from, "/"), strings.Join(to, "/"))
+	s.changeTags(w, r, label, func(b *bookmark.Bookmark) bool {
+		return b.RenameTag(from, to)
+	})
+}
+
+// deleteTag removes a tag, and the tags nested under it, from every bookmark
+func (s *Server) deleteTag(w http.ResponseWriter, r *http.Request) {
+	path := parseTag(r.PathValue("tag"))
+	if path == nil {
+		writeError(w, http.StatusBadRequest, "invalid tag %q", r.PathValue("tag"))
+		return
+	}
+
+	s.changeTags(w, r, "delete tag "+strings.Join(path, "/"), func(b *bookmark.Bookmark) bool {
+		return b.RemoveTag(path)
+	})
+}
+
+// changeTags applies change to every bookmark and saves the bookmarks it
+// reports changed as one history entry. No bookmark changing means the tag
+// doesn't exist.
+func (s *Server) changeTags(w http.ResponseWriter, r *http.Request, label string, change func(b *bookmark.Bookmark) bool) {
+	if !checkIfMatch(w, r, s.etag) {
+		return
+	}
+
+	now := time.Now()
+	var ops []bookmark.Op
+	changed := 0
+	for _, b := range s.collection.Bookmarks {
+		before := b.Clone()
+		if change(b) {
+			b.LastModified = now
+			ops = append(ops, bookmark.DiffOps(before, b)...)
+			changed++
+		}
+	}
+	if changed == 0 {
+		writeError(w, http.StatusNotFound, "no bookmark has that tag")
+		return
+	}
+
+	if err := s.commit(label, ops); err != nil {
+		writeError(w, http.StatusInternalServerError, "%v", err)
+		return
+	}
+	w.Header().Set("ETag", s.etag)
+	writeJSON(w, http.StatusOK, changedResponse{Changed: changed})
+}
+
+// parseTag reads a slash-separated tag path, normalizing each level. It
+// returns nil when no level is left.
+func parseTag(s string) []string {
+	var path []string
+	for _, level := range strings.Split(s, "/") {
+		if tag := bookmark.NormalizeTag(level); tag != "" {
+			path = append(path, tag)
+		}
+	}
+	return path
+}
@@ -0,0 +1,118 @@
+package server
+
+import (
+	"net/http"
+	"slices"
+	"testing"
+
+	"github.com/lelopez-io/moxli/internal/bookmark"
+)
+
+func TestServer_ListTags(t *testing.T) {
+	s, _ := newTestServer(t, Options{})
+
+	var tags []tagInfo
+	decode(t, do(s, "GET", "/api/tags", "", nil), &tags)
+	want := []tagInfo{
+		{Tag: "books", Count: 1, Total: 1},
+		{Tag: "lang", Count: 0, Total: 2},
+		{Tag: "lang/go", Count: 1, Total: 1},
+		{Tag: "lang/rust", Count: 1, Total: 1},
+	}
+	if !slices.Equal(tags, want) {
+		t.Errorf("tags = %v, want %v", tags, want)
+	}
+}
+
+func TestServer_BookmarkTags(t *testing.T) {
+	s, _ := newTestServer(t, Options{})
+
+	rec := do(s, "POST", "/api/bookmarks/a/tags", `{"tags": ["Reference/Docs", "lang/go"]}`, nil)
+	var b bookmark.Bookmark
+	decode(t, rec, &b)
+	if rec.Code != http.StatusOK || !b.HasTag([]string{"reference", "docs"}) || len(b.Tags) != 2 {
+		t.Errorf("tags after adding = %v (%v), want lang/go and reference/docs", b.Tags, rec.Code)
+	}
+
+	rec = do(s, "DELETE", "/api/bookmarks/a/tags/lang", "", nil)
+	decode(t, rec, &b)
+	if rec.Code != http.StatusOK || b.HasTag([]string{"lang", "go"}) {
+		t.Errorf("tags after removing lang = %v (%v), want lang/go removed", b.Tags, rec.Code)
+	}
+	if rec := do(s, "DELETE", "/api/bookmarks/a/tags/lang", "", nil); rec.Code != http.StatusNotFound {
+		t.Errorf("removing a missing tag status = %v, want 404", rec.Code)
+	}
+	if got := len(s.history.Entries); got != 2 {
+		t.Errorf("history has %v entries, want 2", got)
+	}
+
+	if rec := do(s, "POST", "/api/bookmarks/a/tags", `{"tags": ["!!"]}`, nil); rec.Code != http.StatusBadRequest {
+		t.Errorf("invalid tag status = %v, want 400", rec.Code)
+	}
+}
+
+func TestServer_RenameTag(t *testing.T) {
+	s, _ := newTestServer(t, Options{})
+
+	rec := do(s, "POST", "/api/tags/rename", `{"from": "lang", "to": "Programming"}`, nil)
+	var resp changedResponse
+	decode(t, rec, &resp)
+	if rec.Code != http.StatusOK || resp.Changed != 2 {
+		t.Fatalf("rename = %v %+v, want 200 with 2 changed", rec.Code, resp)
+	}
+	b, _ := s.find("b")
+	if !b.HasTag([]string{"programming", "rust"}) || !b.HasTag([]string{"books"}) {
+		t.Errorf("b tags = %v, want programming/rust and books", b.Tags)
+	}
+	if s.history.Entries[0].Label != "rename tag lang to programming" || len(s.history.Entries) != 1 {
+		t.Errorf("history = %+v, want one rename entry", s.history.Entries)
+	}
+
+	if rec := do(s, "POST", "/api/tags/rename", `{"from": "lang", "to": "x"}`, nil); rec.Code != http.StatusNotFound {
+		t.Errorf("renaming a missing tag status = %v, want 404", rec.Code)
+	}
+	if rec := do(s, "POST", "/api/tags/rename", `{"from": "books"}`, nil); rec.Code != http.StatusBadRequest {
+		t.Errorf("rename without to status = %v, want 400", rec.Code)
+	}
+	rec = do(s, "POST", "/api/tags/rename", `{"from": "books", "to": "reading"}`, map[string]string{"If-Match": `"stale"`})
+	if rec.Code != http.StatusPreconditionFailed {
+		t.Errorf("stale If-Match status = %v, want 412", rec.Code)
+	}
+}
+
+func TestServer_DeleteTag(t *testing.T) {
+	s, _ := newTestServer(t, Options{})
+	etag := do(s, "GET", "/api/tags", "", nil).Header().Get("ETag")
+
+	rec := do(s, "DELETE", "/api/tags/lang/rust", "", map[string]string{"If-Match": etag})
+	var resp changedResponse
+	decode(t, rec, &resp)
+	if rec.Code != http.StatusOK || resp.Changed != 1 {
+		t.Fatalf("delete = %v %+v, want 200 with 1 changed", rec.Code, resp)
+	}
+	b, _ := s.find("b")
+	if b.HasTag([]string{"lang", "rust"}) || !b.HasTag([]string{"books"}) {
+		t.Errorf("b tags = %v, want only books", b.Tags)
+	}
+	if rec.Header().Get("ETag") == etag {
+		t.Errorf("collection ETag didn't change")
+	}
+}
+
+func TestParseTag(t *testing.T) {
+	tests := []struct {
+		input string
+		want  []string
+	}{
+		{"lang/go", []string{"lang", "go"}},
+		{"User Auth", []string{"user-auth"}},
+		{"/a//b/", []string{"a", "b"}},
+		{"", nil},
+		{"!!", nil},
+	}
+	for _, tt := range tests {
+		if got := parseTag(tt.input); !slices.Equal(got, tt.want) {
+			t.Errorf("parseTag(%q) = %v, want %v", tt.input, got, tt.want)
+		}
+	}
+}
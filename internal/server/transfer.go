@@ -0,0 +1,162 @@
+package server
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/lelopez-io/moxli/internal/bookmark"
+	"github.com/lelopez-io/moxli/internal/exporter"
+	"github.com/lelopez-io/moxli/internal/importer"
+)
+
+// maxImportBytes caps the size of an uploaded bookmark file
+const maxImportBytes = 32 << 20
+
+// formatInfo describes an export format
+type formatInfo struct {
+	Name      string `json:"name"`
+	Label     string `json:"label"`
+	Extension string `json:"extension"`
+}
+
+// formatsResponse lists the formats the API exports and imports
+type formatsResponse struct {
+	Export []formatInfo `json:"export"`
+	Import []string     `json:"import"` // Sources detected in uploaded files
+}
+
+// importResponse reports the result of an import
+type importResponse struct {
+	Source  string `json:"source"`
+	Added   int    `json:"added"`
+	Skipped int    `json:"skipped"` // Already in the collection or repeated in the file
+}
+
+func (s *Server) listFormats(w http.ResponseWriter, r *http.Request) {
+	resp := formatsResponse{}
+	for _, f := range exporter.Formats() {
+		resp.Export = append(resp.Export, formatInfo{Name: f.Name, Label: f.Label, Extension: f.Extension})
+	}
+	for _, imp := range importer.All() {
+		resp.Import = append(resp.Import, imp.Source())
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// export downloads the collection, or the bookmarks matching q, in the
+// format named by the format parameter
+func (s *Server) export(w http.ResponseWriter, r *http.Request) {
+	params := r.URL.Query()
+	format := exporter.Formats()[0]
+	if name := params.Get("format"); name != "" {
+		var ok bool
+		if format, ok = exporter.LookupFormat(name); !ok {
+			writeError(w, http.StatusBadRequest, "unknown export format %q", name)
+			return
+		}
+	}
+
+	c := s.collection
+	if q := params.Get("q"); q != "" {
+		results, err := s.search(q)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid query: %v", err)
+			return
+		}
+		c = bookmark.NewCollection()
+		c.Metadata.Source = s.collection.Metadata.Source
+		for _, b := range results {
+			c.Add(b)
+		}
+		c.UpdateMetadata()
+	}
+
+	// Rendered first so a failed export is still reported as an error
+	var buf bytes.Buffer
+	if err := format.New().Export(&buf, c); err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to export collection: %v", err)
+		return
+	}
+
+	contentType := mime.TypeByExtension(format.Extension)
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	filename := "moxli-export-" + time.Now().Format("2006-01-02") + format.Extension
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": filename}))
+	if q := params.Get("q"); q == "" {
+		w.Header().Set("ETag", s.etag)
+	}
+	w.WriteHeader(http.StatusOK)
+	_, _ = buf.WriteTo(w)
+}
+
+// importFile adds the bookmarks of an uploaded file, in any format moxli
+// imports, whose URLs aren't in the collection yet. They are saved as one
+// history entry.
+func (s *Server) importFile(w http.ResponseWriter, r *http.Request) {
+	data, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxImportBytes))
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			writeError(w, http.StatusRequestEntityTooLarge, "file is larger than %d bytes", tooLarge.Limit)
+			return
+		}
+		writeError(w, http.StatusBadRequest, "failed to read file: %v", err)
+		return
+	}
+
+	imp := importer.Detect(data)
+	if imp == nil {
+		writeError(w, http.StatusUnsupportedMediaType, "unknown bookmark format")
+		return
+	}
+	imported, err := imp.Parse(bytes.NewReader(data))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "failed to parse %s file: %v", imp.Source(), err)
+		return
+	}
+	if !checkIfMatch(w, r, s.etag) {
+		return
+	}
+
+	ids := make(map[string]bool, len(s.collection.Bookmarks))
+	for _, b := range s.collection.Bookmarks {
+		ids[b.ID] = true
+	}
+
+	resp := importResponse{Source: imp.Source()}
+	seen := make(map[string]bool)
+	var ops []bookmark.Op
+	for _, b := range imported.Bookmarks {
+		if _, exists := s.collection.FindByURL(b.NormalizedURL); exists || seen[b.NormalizedURL] {
+			resp.Skipped++
+			continue
+		}
+		seen[b.NormalizedURL] = true
+		if b.ID == "" || ids[b.ID] {
+			b.ID = uuid.New().String()
+		}
+		ids[b.ID] = true
+		ops = append(ops, bookmark.AddOp(len(s.collection.Bookmarks)+len(ops), b))
+	}
+	resp.Added = len(ops)
+
+	if len(ops) > 0 {
+		label := fmt.Sprintf("import %d bookmark(s) from %s", len(ops), imp.Source())
+		if err := s.apply(label, ops); err != nil {
+			writeError(w, http.StatusInternalServerError, "%v", err)
+			return
+		}
+	}
+	w.Header().Set("ETag", s.etag)
+	writeJSON(w, http.StatusOK, resp)
+}
@@ -0,0 +1,81 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/lelopez-io/moxli/internal/bookmark"
+)
+
+func TestServer_ListFormats(t *testing.T) {
+	s, _ := newTestServer(t, Options{})
+
+	var resp formatsResponse
+	decode(t, do(s, "GET", "/api/formats", "", nil), &resp)
+	if len(resp.Export) == 0 || resp.Export[0].Name != "anybox" {
+		t.Errorf("export formats = %v, want anybox first", resp.Export)
+	}
+	if !strings.Contains(strings.Join(resp.Import, ","), "safari") {
+		t.Errorf("import sources = %v, want safari among them", resp.Import)
+	}
+}
+
+func TestServer_Export(t *testing.T) {
+	s, _ := newTestServer(t, Options{})
+
+	rec := do(s, "GET", "/api/export?q=tag:lang/rust", "", nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %v, want 200: %s", rec.Code, rec.Body)
+	}
+	if got := rec.Header().Get("Content-Type"); !strings.HasPrefix(got, "application/json") {
+		t.Errorf("Content-Type = %q, want application/json", got)
+	}
+	if got := rec.Header().Get("Content-Disposition"); !strings.HasPrefix(got, "attachment; filename=moxli-export-") {
+		t.Errorf("Content-Disposition = %q, want an attachment", got)
+	}
+	var exported []bookmark.Bookmark
+	if err := json.Unmarshal(rec.Body.Bytes(), &exported); err != nil {
+		t.Fatalf("export isn't Anybox JSON: %v", err)
+	}
+	if len(exported) != 1 || exported[0].Title != "Rust book" {
+		t.Errorf("exported %v, want only the Rust book", exported)
+	}
+
+	if rec := do(s, "GET", "/api/export?format=nope", "", nil); rec.Code != http.StatusBadRequest {
+		t.Errorf("unknown format status = %v, want 400", rec.Code)
+	}
+}
+
+func TestServer_Import(t *testing.T) {
+	s, _ := newTestServer(t, Options{})
+
+	upload := `<!DOCTYPE NETSCAPE-Bookmark-file-1>
+<H1>Bookmarks</H1>
+<DL><p>
+<DT><A HREF="https://go.dev/doc/">Go docs again</A>
+<DT><A HREF="https://example.net/one">One</A>
+<DT><A HREF="https://example.net/one/">One again</A>
+<DT><A HREF="https://example.net/two">Two</A>
+</DL><p>`
+	rec := do(s, "POST", "/api/import", upload, nil)
+	var resp importResponse
+	decode(t, rec, &resp)
+	if rec.Code != http.StatusOK || resp.Added != 2 || resp.Skipped != 2 || resp.Source != "safari" {
+		t.Fatalf("import = %v %+v, want 2 safari bookmarks added and 2 skipped", rec.Code, resp)
+	}
+	if got := len(s.collection.Bookmarks); got != 5 {
+		t.Errorf("collection has %v bookmarks, want 5", got)
+	}
+	if len(s.history.Entries) != 1 || s.history.Entries[0].Label != "import 2 bookmark(s) from safari" {
+		t.Errorf("history = %+v, want one import entry", s.history.Entries)
+	}
+
+	if rec := do(s, "POST", "/api/import", "plain text", nil); rec.Code != http.StatusUnsupportedMediaType {
+		t.Errorf("unknown format status = %v, want 415", rec.Code)
+	}
+	if rec := do(s, "POST", "/api/import", upload, map[string]string{"If-Match": `"stale"`}); rec.Code != http.StatusPreconditionFailed {
+		t.Errorf("stale If-Match status = %v, want 412", rec.Code)
+	}
+}
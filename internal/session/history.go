@@ -0,0 +1,87 @@
+package session
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/lelopez-io/moxli/internal/bookmark"
+)
+
+// ConfigDir returns the directory holding moxli's session and history files
+func (m *Manager) ConfigDir() string {
+	return m.configDir
+}
+
+// historyPath returns where the edit history of a collection file is kept.
+// Histories are keyed by the file's absolute path.
+func (m *Manager) historyPath(file string) string {
+	if abs, err := filepath.Abs(file); err == nil {
+		file = abs
+	}
+	sum := sha256.Sum256([]byte(file))
+	return filepath.Join(m.configDir, "history", hex.EncodeToString(sum[:])+".json")
+}
+
+// LoadHistory loads the edit history saved for a collection file.
+// An empty history is returned when none exists or when the file changed
+// since the history was saved, because its ops may no longer apply.
+func (m *Manager) LoadHistory(file string) (*bookmark.History, error) {
+	data, err := os.ReadFile(m.historyPath(file))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return bookmark.NewHistory(), nil
+		}
+		return nil, fmt.Errorf("failed to read history file: %w", err)
+	}
+
+	var history bookmark.History
+	if err := json.Unmarshal(data, &history); err != nil {
+		return nil, fmt.Errorf("failed to parse history file: %w", err)
+	}
+
+	hash, err := fileHash(file)
+	if err != nil || hash != history.FileHash {
+		return bookmark.NewHistory(), nil
+	}
+
+	return &history, nil
+}
+
+// SaveHistory stores the edit history of a collection file.
+// Call it after the file itself was written so the stored hash matches.
+func (m *Manager) SaveHistory(file string, history *bookmark.History) error {
+	hash, err := fileHash(file)
+	if err != nil {
+		return fmt.Errorf("failed to hash %s: %w", file, err)
+	}
+	history.FileHash = hash
+
+	data, err := json.Marshal(history)
+	if err != nil {
+		return fmt.Errorf("failed to serialize history: %w", err)
+	}
+
+	path := m.historyPath(file)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create history directory: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write history file: %w", err)
+	}
+
+	return nil
+}
+
+// fileHash returns the hex SHA-256 of a file's contents
+func fileHash(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
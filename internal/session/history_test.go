@@ -0,0 +1,79 @@
+package session
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/lelopez-io/moxli/internal/bookmark"
+)
+
+func TestManager_SaveAndLoadHistory(t *testing.T) {
+	tmpDir := t.TempDir()
+	manager := &Manager{configDir: filepath.Join(tmpDir, "config")}
+
+	file := filepath.Join(tmpDir, "bookmarks.json")
+	if err := os.WriteFile(file, []byte(`{"bookmarks":[]}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	history := bookmark.NewHistory()
+	history.Record("Edit", []bookmark.Op{{Kind: bookmark.OpUpdate, ID: "a", Field: bookmark.FieldTitle,
+		Before: []byte(`"old"`), After: []byte(`"new"`)}})
+
+	if err := manager.SaveHistory(file, history); err != nil {
+		t.Fatalf("SaveHistory() error = %v", err)
+	}
+
+	loaded, err := manager.LoadHistory(file)
+	if err != nil {
+		t.Fatalf("LoadHistory() error = %v", err)
+	}
+	if len(loaded.Entries) != 1 || loaded.Cursor != 1 {
+		t.Errorf("LoadHistory() entries = %v, cursor = %v, want 1, 1", len(loaded.Entries), loaded.Cursor)
+	}
+	if loaded.Entries[0].Label != "Edit" {
+		t.Errorf("Label = %v, want Edit", loaded.Entries[0].Label)
+	}
+}
+
+func TestManager_LoadHistory_FileChanged(t *testing.T) {
+	tmpDir := t.TempDir()
+	manager := &Manager{configDir: tmpDir}
+
+	file := filepath.Join(tmpDir, "bookmarks.json")
+	if err := os.WriteFile(file, []byte(`{"bookmarks":[]}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	history := bookmark.NewHistory()
+	history.Record("Edit", []bookmark.Op{{Kind: bookmark.OpRemove, ID: "a"}})
+	if err := manager.SaveHistory(file, history); err != nil {
+		t.Fatalf("SaveHistory() error = %v", err)
+	}
+
+	// Changed outside moxli: the saved ops no longer describe the file
+	if err := os.WriteFile(file, []byte(`{"bookmarks":[{}]}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := manager.LoadHistory(file)
+	if err != nil {
+		t.Fatalf("LoadHistory() error = %v", err)
+	}
+	if len(loaded.Entries) != 0 {
+		t.Errorf("LoadHistory() entries = %v, want 0 for a changed file", len(loaded.Entries))
+	}
+}
+
+func TestManager_LoadHistory_None(t *testing.T) {
+	manager := &Manager{configDir: t.TempDir()}
+
+	loaded, err := manager.LoadHistory("/nonexistent/bookmarks.json")
+	if err != nil {
+		t.Fatalf("LoadHistory() error = %v", err)
+	}
+	if loaded == nil || len(loaded.Entries) != 0 {
+		t.Error("LoadHistory() should return an empty history when none was saved")
+	}
+}
@@ -0,0 +1,34 @@
+package site
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/lelopez-io/moxli/internal/bookmark"
+	"github.com/lelopez-io/moxli/internal/exporter"
+)
+
+// feed returns the Atom feed of the newest bookmarks. Entries link to the
+// bookmarked pages, and to their anchor on the site when BaseURL is set.
+func (g *generator) feed() ([]byte, error) {
+	e := &exporter.FeedExporter{Format: exporter.FeedAtom, Title: g.opts.Title, Limit: g.opts.FeedSize}
+	if base := g.baseURL(); base != "" {
+		e.Link = base
+		e.FeedURL = base + feedFile
+		e.Permalink = func(b *bookmark.Bookmark) string { return base + "#" + anchor(b) }
+	}
+
+	var buf bytes.Buffer
+	if err := e.Export(&buf, &bookmark.Collection{Bookmarks: g.bookmarks}); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// baseURL returns Options.BaseURL ending in a slash, or "" when unset
+func (g *generator) baseURL() string {
+	if g.opts.BaseURL == "" {
+		return ""
+	}
+	return strings.TrimSuffix(g.opts.BaseURL, "/") + "/"
+}
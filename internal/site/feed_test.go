@@ -0,0 +1,42 @@
+package site
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/lelopez-io/moxli/internal/bookmark"
+)
+
+func TestGenerator_Feed(t *testing.T) {
+	bookmarks := bookmark.Sort(testBookmarks(), bookmark.SortAddedDesc)
+	g := &generator{opts: Options{Title: "Team", BaseURL: "https://links.example", FeedSize: 2}, bookmarks: bookmarks}
+
+	data, err := g.feed()
+	if err != nil {
+		t.Fatalf("feed() error = %v", err)
+	}
+	out := string(data)
+	for _, want := range []string{
+		"<title>Team</title>",
+		`<link rel="self" type="application/atom+xml" href="https://links.example/feed.xml"></link>`,
+		`<link rel="related" type="text/html" href="https://links.example/#b-news"></link>`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("feed doesn't contain %q:\n%s", want, out)
+		}
+	}
+	if got := strings.Count(out, "<entry>"); got != 2 {
+		t.Errorf("feed has %v entries, want FeedSize 2", got)
+	}
+}
+
+func TestGenerator_Feed_NoBaseURL(t *testing.T) {
+	g := &generator{opts: Options{Title: "My links", FeedSize: 10}}
+	data, err := g.feed()
+	if err != nil {
+		t.Fatalf("feed() error = %v", err)
+	}
+	if out := string(data); !strings.HasPrefix(out, "<?xml") || strings.Contains(out, "<link") {
+		t.Errorf("feed = %s, want no links", out)
+	}
+}
@@ -0,0 +1,347 @@
+package site
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"html/template"
+	"strings"
+	"time"
+
+	"github.com/lelopez-io/moxli/internal/bookmark"
+)
+
+// Files at the root of every site
+const (
+	indexFile  = "index.html"
+	searchFile = "search.json"
+	feedFile   = "feed.xml"
+	styleFile  = "style.css"
+	scriptFile = "search.js"
+)
+
+// pageData is what a page template renders
+type pageData struct {
+	Site      string    // Site title
+	Title     string    // Page heading, empty on the index
+	Root      string    // Relative path from the page back to the site's root
+	Up        *link     // Parent page
+	Sections  []section // Lists of tags and folders
+	Bookmarks []entry
+	Search    bool // Show the search box
+}
+
+// link points at a page of the site, by its path from the root
+type link struct {
+	Label string
+	Href  string
+	Count int
+}
+
+// section is a titled list of links
+type section struct {
+	Title string
+	Links []link
+	More  *link // Page listing the rest
+}
+
+// entry is a bookmark as shown in lists
+type entry struct {
+	Anchor      string
+	Title       string
+	URL         string
+	Domain      string
+	Description string
+	Comment     string
+	Starred     bool
+	Added       time.Time
+	Tags        []link
+	Folder      *link
+}
+
+// writeIndex writes the front page, listing every bookmark
+func (g *generator) writeIndex() error {
+	return g.writePage(indexFile, pageData{
+		Sections: []section{
+			g.section("Tags", g.tags.Root.Children, &link{Label: "All tags", Href: g.tags.Root.File}),
+			g.section("Folders", g.folders.Root.Children, &link{Label: "All folders", Href: g.folders.Root.File}),
+		},
+		Bookmarks: g.entries(g.bookmarks),
+		Search:    true,
+	})
+}
+
+// writeTree writes the overview and the page of every tag or folder
+func (g *generator) writeTree(tree *pageTree) error {
+	title := "Tags"
+	if tree.Kind == "folder" {
+		title = "Folders"
+	}
+	overview := g.section(title, tree.nodes, nil)
+	for i, node := range tree.nodes {
+		overview.Links[i].Label = tree.label(node.Path)
+	}
+	if err := g.writePage(tree.Root.File, pageData{
+		Title:    title,
+		Up:       &link{Label: g.opts.Title, Href: indexFile},
+		Sections: []section{overview},
+	}); err != nil {
+		return err
+	}
+
+	for _, node := range tree.nodes {
+		var bookmarks []*bookmark.Bookmark
+		for _, b := range g.bookmarks {
+			if (tree.Kind == "tag" && b.HasTagUnder(node.Path)) || (tree.Kind == "folder" && b.InFolder(node.Path)) {
+				bookmarks = append(bookmarks, b)
+			}
+		}
+
+		up := &link{Label: title, Href: tree.Root.File}
+		if parent := tree.find(node.Path[:len(node.Path)-1]); parent != tree.Root {
+			up = &link{Label: tree.label(parent.Path), Href: parent.File}
+		}
+		data := pageData{
+			Title:     tree.label(node.Path),
+			Up:        up,
+			Bookmarks: g.entries(bookmarks),
+		}
+		if len(node.Children) > 0 {
+			data.Sections = []section{g.section("Inside "+node.Name, node.Children, nil)}
+		}
+		if err := g.writePage(node.File, data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// section lists pages with their bookmark counts
+func (g *generator) section(title string, nodes []*pageNode, more *link) section {
+	s := section{Title: title, More: more}
+	for _, n := range nodes {
+		s.Links = append(s.Links, link{Label: n.Name, Href: n.File, Count: n.Total})
+	}
+	return s
+}
+
+// entries prepares bookmarks for a page, linking their tags and folder
+func (g *generator) entries(bookmarks []*bookmark.Bookmark) []entry {
+	entries := make([]entry, len(bookmarks))
+	for i, b := range bookmarks {
+		e := entry{
+			Anchor:      anchor(b),
+			Title:       b.Title,
+			URL:         b.URL,
+			Domain:      b.Domain(),
+			Description: b.Description,
+			Comment:     b.Comment,
+			Starred:     b.IsStarred,
+			Added:       b.DateAdded,
+		}
+		if e.Title == "" {
+			e.Title = b.URL
+		}
+		for _, tag := range b.Tags {
+			if node := g.tags.find(tag); node != nil && len(tag) > 0 {
+				e.Tags = append(e.Tags, link{Label: g.tags.label(tag), Href: node.File})
+			}
+		}
+		if node := g.folders.find(b.Folder); node != nil && len(b.Folder) > 0 {
+			e.Folder = &link{Label: g.folders.label(b.Folder), Href: node.File}
+		}
+		entries[i] = e
+	}
+	return entries
+}
+
+// writePage renders a page at its site path
+func (g *generator) writePage(name string, data pageData) error {
+	data.Site = g.opts.Title
+	data.Root = strings.Repeat("../", strings.Count(name, "/"))
+
+	var buf bytes.Buffer
+	if err := pageTemplate.Execute(&buf, data); err != nil {
+		return err
+	}
+	g.pages++
+	return g.write(name, buf.Bytes())
+}
+
+// anchor returns the fragment identifying a bookmark on every page. Bookmarks
+// without an ID, or with one that can't be used in a fragment, are named by
+// their URL.
+func anchor(b *bookmark.Bookmark) string {
+	if b.ID != "" && !strings.ContainsFunc(b.ID, unsafeInFragment) {
+		return "b-" + b.ID
+	}
+	sum := sha256.Sum256([]byte(b.URL))
+	return "b-" + hex.EncodeToString(sum[:8])
+}
+
+// unsafeInFragment reports whether r is kept out of anchors
+func unsafeInFragment(r rune) bool {
+	return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_')
+}
+
+var pageTemplate = template.Must(template.New("page").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{with .Title}}{{.}} · {{end}}{{.Site}}</title>
+<link rel="stylesheet" href="{{.Root}}style.css">
+<link rel="alternate" type="application/atom+xml" title="{{.Site}}" href="{{.Root}}feed.xml">
+</head>
+<body>
+<header>
+<a class="site" href="{{.Root}}index.html">{{.Site}}</a>
+<nav><a href="{{.Root}}tags/index.html">Tags</a> <a href="{{.Root}}folders/index.html">Folders</a> <a href="{{.Root}}feed.xml">Feed</a></nav>
+</header>
+<main>
+{{- with .Up}}
+<p class="up">← <a href="{{$.Root}}{{.Href}}">{{.Label}}</a></p>
+{{- end}}
+{{- with .Title}}
+<h1>{{.}}</h1>
+{{- end}}
+{{- if .Search}}
+<input id="search" type="search" placeholder="Search {{len .Bookmarks}} bookmarks" data-index="{{.Root}}search.json" autofocus>
+<ul id="results" class="bookmarks" hidden></ul>
+{{- end}}
+{{- range .Sections}}{{if .Links}}
+<section>
+<h2>{{.Title}}</h2>
+<ul class="links">
+{{- range .Links}}
+<li><a href="{{$.Root}}{{.Href}}">{{.Label}}</a>{{if .Count}} <span class="count">{{.Count}}</span>{{end}}</li>
+{{- end}}
+{{- with .More}}
+<li class="more"><a href="{{$.Root}}{{.Href}}">{{.Label}} →</a></li>
+{{- end}}
+</ul>
+</section>
+{{- end}}{{end}}
+{{- if .Bookmarks}}
+<ul id="all" class="bookmarks">
+{{- range .Bookmarks}}
+<li id="{{.Anchor}}">
+<a class="title" href="{{.URL}}">{{.Title}}</a>{{if .Starred}} <span class="star" title="Starred">★</span>{{end}} <a class="anchor" href="#{{.Anchor}}" title="Link to this bookmark">#</a>
+<div class="meta">{{.Domain}}{{if not .Added.IsZero}} · {{.Added.Format "2006-01-02"}}{{end}}{{with .Folder}} · <a href="{{$.Root}}{{.Href}}">{{.Label}}</a>{{end}}</div>
+{{- with .Description}}
+<p class="description">{{.}}</p>
+{{- end}}
+{{- with .Comment}}
+<p class="comment">{{.}}</p>
+{{- end}}
+{{- with .Tags}}
+<p class="tags">{{range .}}<a href="{{$.Root}}{{.Href}}">{{.Label}}</a> {{end}}</p>
+{{- end}}
+</li>
+{{- end}}
+</ul>
+{{- end}}
+</main>
+{{- if .Search}}
+<script src="{{.Root}}search.js"></script>
+{{- end}}
+</body>
+</html>
+`))
+
+const styleCSS = `body { font: 16px/1.5 system-ui, sans-serif; margin: 0; color: #222; background: #fff; }
+header { display: flex; justify-content: space-between; align-items: baseline; padding: .8em 1.5em; border-bottom: 1px solid #ddd; }
+header .site { font-weight: 700; font-size: 1.2em; color: inherit; text-decoration: none; }
+header nav a { margin-left: 1em; }
+main { max-width: 48em; margin: 0 auto; padding: 1em 1.5em 3em; }
+a { color: #0757c4; }
+h2 { font-size: 1em; text-transform: uppercase; letter-spacing: .05em; color: #666; }
+.up { margin: 0; }
+#search { width: 100%; box-sizing: border-box; font: inherit; padding: .5em; margin: 1em 0; }
+.links { list-style: none; padding: 0; display: flex; flex-wrap: wrap; gap: .3em 1.2em; }
+.count { color: #888; font-size: .85em; }
+.bookmarks { list-style: none; padding: 0; }
+.bookmarks li { padding: .7em 0; border-bottom: 1px solid #eee; }
+.bookmarks .title { font-weight: 600; }
+.anchor { color: #bbb; text-decoration: none; visibility: hidden; }
+.bookmarks li:hover .anchor, .bookmarks li:target .anchor { visibility: visible; }
+.bookmarks li:target { background: #fffbe6; }
+.star { color: #e0a800; }
+.meta { color: #777; font-size: .85em; }
+.meta a { color: inherit; }
+.description, .comment { margin: .3em 0; }
+.comment { font-style: italic; }
+.tags { margin: .3em 0 0; font-size: .85em; }
+.tags a { margin-right: .5em; }
+@media (prefers-color-scheme: dark) {
+  body { color: #ddd; background: #161616; }
+  header, .bookmarks li { border-color: #333; }
+  a { color: #6aa8ff; }
+  .bookmarks li:target { background: #2a2614; }
+}
+`
+
+// searchJS filters the search index as the user types. Every word must
+// appear in a bookmark's title, URL, tags, folder or text.
+const searchJS = `(function () {
+  var input = document.getElementById("search");
+  var results = document.getElementById("results");
+  var all = document.getElementById("all");
+  if (!input || !all) return;
+  var entries = null;
+
+  function load() {
+    if (entries) return Promise.resolve(entries);
+    return fetch(input.dataset.index).then(function (r) { return r.json(); }).then(function (data) {
+      entries = data.map(function (e) {
+        e.haystack = [e.title, e.url, e.description, e.comment, e.folder].concat(e.tags).join(" ").toLowerCase();
+        return e;
+      });
+      return entries;
+    });
+  }
+
+  function render(matches) {
+    results.textContent = "";
+    matches.slice(0, 100).forEach(function (e) {
+      var li = document.createElement("li");
+      var a = document.createElement("a");
+      a.className = "title";
+      // Only web links, whatever the index holds
+      if (/^https?:/i.test(e.url)) a.href = e.url;
+      a.textContent = e.title || e.url || e.domain;
+      var anchor = document.createElement("a");
+      anchor.className = "anchor";
+      anchor.href = "#" + e.anchor;
+      anchor.textContent = "#";
+      anchor.addEventListener("click", function () {
+        // Show the full list again so the browser can scroll to the bookmark
+        input.value = "";
+        results.hidden = true;
+        all.hidden = false;
+      });
+      var meta = document.createElement("div");
+      meta.className = "meta";
+      meta.textContent = [e.domain].concat(e.tags).join(" · ");
+      li.append(a, " ", anchor, meta);
+      results.append(li);
+    });
+  }
+
+  input.addEventListener("input", function () {
+    var words = input.value.toLowerCase().split(/\s+/).filter(Boolean);
+    if (words.length === 0) {
+      results.hidden = true;
+      all.hidden = false;
+      return;
+    }
+    load().then(function (entries) {
+      render(entries.filter(function (e) {
+        return words.every(function (w) { return e.haystack.indexOf(w) >= 0; });
+      }));
+      results.hidden = false;
+      all.hidden = true;
+    });
+  });
+})();
+`
@@ -0,0 +1,57 @@
+package site
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/lelopez-io/moxli/internal/bookmark"
+)
+
+func TestAnchor(t *testing.T) {
+	tests := []struct {
+		name string
+		b    *bookmark.Bookmark
+		want string
+	}{
+		{"uuid", &bookmark.Bookmark{ID: "9b2f0c1e-1d2a-4c59-9d8e-1f2a3b4c5d6e"}, "b-9b2f0c1e-1d2a-4c59-9d8e-1f2a3b4c5d6e"},
+		{"no ID", &bookmark.Bookmark{URL: "https://example.com"}, "b-100680ad546ce6a5"},
+		{"unsafe ID", &bookmark.Bookmark{ID: "a b", URL: "https://example.com"}, "b-100680ad546ce6a5"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := anchor(tt.b); got != tt.want {
+				t.Errorf("anchor() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGenerator_Entries(t *testing.T) {
+	bookmarks := []*bookmark.Bookmark{
+		{ID: "a", URL: "https://example.com/page", Tags: [][]string{{"lang", "go"}}, Folder: []string{"Dev"}},
+	}
+	c := &bookmark.Collection{Bookmarks: bookmarks}
+	g := &generator{tags: newTagPages(c.TagTree()), folders: newFolderPages(bookmark.FolderTree(bookmarks))}
+
+	e := g.entries(bookmarks)[0]
+	if e.Title != "https://example.com/page" || e.Domain != "example.com" {
+		t.Errorf("Title, Domain = %q, %q, want the URL as title and example.com", e.Title, e.Domain)
+	}
+	if len(e.Tags) != 1 || e.Tags[0].Label != "lang/go" || e.Tags[0].Href != "tags/lang/go/index.html" {
+		t.Errorf("Tags = %+v, want a link to tags/lang/go", e.Tags)
+	}
+	if e.Folder == nil || e.Folder.Href != "folders/dev/index.html" {
+		t.Errorf("Folder = %+v, want a link to folders/dev", e.Folder)
+	}
+}
+
+func TestPageTemplate_FiltersUnsafeURLs(t *testing.T) {
+	var buf strings.Builder
+	data := pageData{Site: "x", Bookmarks: []entry{{Anchor: "b-a", Title: "Bad", URL: "javascript:alert(1)"}}}
+	if err := pageTemplate.Execute(&buf, data); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(buf.String(), "javascript:") {
+		t.Errorf("page links to a javascript: URL")
+	}
+}
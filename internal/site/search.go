@@ -0,0 +1,56 @@
+package site
+
+import (
+	"encoding/json"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/lelopez-io/moxli/internal/bookmark"
+)
+
+// searchEntry is one bookmark in the search index
+type searchEntry struct {
+	Anchor      string    `json:"anchor"` // Fragment of the bookmark on the index page
+	Title       string    `json:"title"`
+	URL         string    `json:"url"` // Empty unless http or https
+	Domain      string    `json:"domain"`
+	Description string    `json:"description,omitempty"`
+	Comment     string    `json:"comment,omitempty"`
+	Tags        []string  `json:"tags"` // Slash-separated paths
+	Folder      string    `json:"folder,omitempty"`
+	Added       time.Time `json:"added,omitzero"`
+}
+
+// searchIndex returns the JSON index searched by the site's script
+func searchIndex(bookmarks []*bookmark.Bookmark) ([]byte, error) {
+	entries := make([]searchEntry, len(bookmarks))
+	for i, b := range bookmarks {
+		tags := make([]string, 0, len(b.Tags))
+		for _, tag := range b.Tags {
+			tags = append(tags, bookmark.FormatTags([][]string{tag}))
+		}
+		entries[i] = searchEntry{
+			Anchor:      anchor(b),
+			Title:       b.Title,
+			URL:         webURL(b.URL),
+			Domain:      b.Domain(),
+			Description: b.Description,
+			Comment:     b.Comment,
+			Tags:        tags,
+			Folder:      bookmark.FormatFolder(b.Folder),
+			Added:       b.DateAdded,
+		}
+	}
+	return json.Marshal(entries)
+}
+
+// webURL returns rawURL when it is an http or https URL, and "" otherwise,
+// so the search script never links to javascript: or data: URLs
+func webURL(rawURL string) string {
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return ""
+	}
+	return rawURL
+}
@@ -0,0 +1,49 @@
+package site
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/lelopez-io/moxli/internal/bookmark"
+)
+
+func TestSearchIndex(t *testing.T) {
+	data, err := searchIndex([]*bookmark.Bookmark{
+		{ID: "a", URL: "https://www.example.com/", Title: "Example", Tags: [][]string{{"lang", "go"}, {"web"}}, Folder: []string{"Dev", "Web"}},
+		{ID: "b", URL: "https://example.org/"},
+		{ID: "c", URL: "javascript:alert(1)", Title: "Bad"},
+		{ID: "d", URL: " JavaScript:alert(1)", Title: "Worse"},
+	})
+	if err != nil {
+		t.Fatalf("searchIndex() error = %v", err)
+	}
+
+	var entries []map[string]any
+	if err := json.Unmarshal(data, &entries); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if len(entries) != 4 {
+		t.Fatalf("len(entries) = %v, want 4", len(entries))
+	}
+	first := entries[0]
+	if first["anchor"] != "b-a" || first["domain"] != "example.com" || first["folder"] != "Dev / Web" {
+		t.Errorf("entry = %v, want anchor b-a, domain example.com and folder Dev / Web", first)
+	}
+	if tags, _ := first["tags"].([]any); len(tags) != 2 || tags[0] != "lang/go" {
+		t.Errorf("tags = %v, want [lang/go web]", first["tags"])
+	}
+	if _, ok := entries[1]["added"]; ok {
+		t.Errorf("entry without a date has an added field")
+	}
+	if tags, ok := entries[1]["tags"].([]any); !ok || len(tags) != 0 {
+		t.Errorf("tags of an untagged bookmark = %v, want []", entries[1]["tags"])
+	}
+	if entries[1]["url"] != "https://example.org/" {
+		t.Errorf("url = %v, want https://example.org/", entries[1]["url"])
+	}
+	for _, e := range entries[2:] {
+		if e["url"] != "" {
+			t.Errorf("url of %v = %v, want non-web URLs left out", e["title"], e["url"])
+		}
+	}
+}
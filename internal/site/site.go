@@ -0,0 +1,234 @@
+// Package site renders a bookmark collection into a static website: an
+// index of every bookmark, a page per tag and per folder, a JSON index for
+// searching in the browser and an Atom feed of the latest additions. Every
+// bookmark has an anchor so it can be linked to.
+package site
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/lelopez-io/moxli/internal/bookmark"
+)
+
+// DefaultTitle names a site when Options.Title is empty
+const DefaultTitle = "Bookmarks"
+
+// DefaultFeedSize is the number of feed entries by default
+const DefaultFeedSize = 50
+
+// PrivateTag marks bookmarks that can be left out of a published site
+var PrivateTag = []string{"private"}
+
+// Options configures a site
+type Options struct {
+	Title    string     // Site title (empty uses DefaultTitle)
+	BaseURL  string     // Address the site is published at, for the feed's links; may be empty
+	Exclude  [][]string // Bookmarks tagged with one of these, or a tag nested under it, are left out
+	FeedSize int        // Newest bookmarks in the feed (0 uses DefaultFeedSize)
+}
+
+// Result summarizes a generated site
+type Result struct {
+	Bookmarks int // Bookmarks published
+	Excluded  int // Bookmarks left out by Options.Exclude
+	Pages     int // HTML pages written
+}
+
+// Generate writes the site for the bookmarks into dir, creating it when
+// needed. Existing files with the same names are replaced; other files are
+// left alone.
+func Generate(dir string, bookmarks []*bookmark.Bookmark, opts Options) (*Result, error) {
+	if opts.Title == "" {
+		opts.Title = DefaultTitle
+	}
+	if opts.FeedSize <= 0 {
+		opts.FeedSize = DefaultFeedSize
+	}
+
+	published := bookmark.NewCollection()
+	result := &Result{}
+	for _, b := range bookmarks {
+		if excluded(b, opts.Exclude) {
+			result.Excluded++
+			continue
+		}
+		published.Bookmarks = append(published.Bookmarks, b)
+	}
+	published.Bookmarks = bookmark.Sort(published.Bookmarks, bookmark.SortAddedDesc)
+	result.Bookmarks = len(published.Bookmarks)
+
+	g := &generator{dir: dir, opts: opts, bookmarks: published.Bookmarks}
+	g.tags = newTagPages(published.TagTree())
+	g.folders = newFolderPages(bookmark.FolderTree(published.Bookmarks))
+
+	if err := g.writeAll(); err != nil {
+		return nil, err
+	}
+	result.Pages = g.pages
+	return result, nil
+}
+
+// excluded reports whether b carries one of the excluded tags
+func excluded(b *bookmark.Bookmark, exclude [][]string) bool {
+	for _, tag := range exclude {
+		if b.HasTagUnder(tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// generator writes the files of one site
+type generator struct {
+	dir       string
+	opts      Options
+	bookmarks []*bookmark.Bookmark // Published bookmarks, newest first
+	tags      *pageTree
+	folders   *pageTree
+	pages     int // HTML pages written so far
+}
+
+func (g *generator) writeAll() error {
+	if err := g.writeIndex(); err != nil {
+		return err
+	}
+	for _, tree := range []*pageTree{g.tags, g.folders} {
+		if err := g.writeTree(tree); err != nil {
+			return err
+		}
+	}
+
+	data, err := searchIndex(g.bookmarks)
+	if err != nil {
+		return err
+	}
+	if err := g.write(searchFile, data); err != nil {
+		return err
+	}
+
+	feed, err := g.feed()
+	if err != nil {
+		return err
+	}
+	if err := g.write(feedFile, feed); err != nil {
+		return err
+	}
+
+	if err := g.write(styleFile, []byte(styleCSS)); err != nil {
+		return err
+	}
+	return g.write(scriptFile, []byte(searchJS))
+}
+
+// write saves a file of the site, named by its slash-separated path
+func (g *generator) write(name string, data []byte) error {
+	target := filepath.Join(g.dir, filepath.FromSlash(name))
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(target), err)
+	}
+	if err := os.WriteFile(target, data, 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", target, err)
+	}
+	return nil
+}
+
+// pageTree is the tag or folder hierarchy with the path of each page
+type pageTree struct {
+	Kind  string // "tag" or "folder"
+	Root  *pageNode
+	nodes []*pageNode // Every node but the root, parents first
+}
+
+// pageNode is one tag or folder page
+type pageNode struct {
+	Name     string
+	Path     []string
+	File     string // Site path of the page, such as "tags/lang/go/index.html"
+	Total    int    // Bookmarks on the page
+	Children []*pageNode
+}
+
+func newTagPages(tree *bookmark.TagNode) *pageTree {
+	var convert func(n *bookmark.TagNode) *pageNode
+	convert = func(n *bookmark.TagNode) *pageNode {
+		node := &pageNode{Name: n.Name, Path: n.Path, Total: n.Total}
+		for _, c := range n.Children {
+			node.Children = append(node.Children, convert(c))
+		}
+		return node
+	}
+	return newPageTree("tag", "tags", convert(tree))
+}
+
+func newFolderPages(tree *bookmark.FolderNode) *pageTree {
+	var convert func(n *bookmark.FolderNode) *pageNode
+	convert = func(n *bookmark.FolderNode) *pageNode {
+		node := &pageNode{Name: n.Name, Path: n.Path, Total: n.Total}
+		for _, c := range n.Children {
+			node.Children = append(node.Children, convert(c))
+		}
+		return node
+	}
+	return newPageTree("folder", "folders", convert(tree))
+}
+
+// newPageTree gives every node below root a page in dir, named after the
+// slugs of its path. Siblings whose names give the same slug are numbered.
+func newPageTree(kind, dir string, root *pageNode) *pageTree {
+	tree := &pageTree{Kind: kind, Root: root}
+	root.File = path.Join(dir, "index.html")
+
+	var assign func(n *pageNode, parent string)
+	assign = func(n *pageNode, parent string) {
+		used := map[string]bool{}
+		for _, c := range n.Children {
+			slug := bookmark.NormalizeTag(c.Name)
+			if slug == "" {
+				slug = kind
+			}
+			unique := slug
+			for i := 2; used[unique]; i++ {
+				unique = fmt.Sprintf("%s-%d", slug, i)
+			}
+			used[unique] = true
+
+			dir := path.Join(parent, unique)
+			c.File = path.Join(dir, "index.html")
+			tree.nodes = append(tree.nodes, c)
+			assign(c, dir)
+		}
+	}
+	assign(root, dir)
+	return tree
+}
+
+// find returns the node at path, or nil when there is none
+func (t *pageTree) find(p []string) *pageNode {
+	node := t.Root
+	for _, name := range p {
+		var next *pageNode
+		for _, c := range node.Children {
+			if c.Name == name {
+				next = c
+				break
+			}
+		}
+		if next == nil {
+			return nil
+		}
+		node = next
+	}
+	return node
+}
+
+// label returns how a tag or folder path is shown
+func (t *pageTree) label(p []string) string {
+	if t.Kind == "tag" {
+		return strings.Join(p, "/")
+	}
+	return bookmark.FormatFolder(p)
+}
@@ -0,0 +1,144 @@
+package site
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lelopez-io/moxli/internal/bookmark"
+)
+
+func testBookmarks() []*bookmark.Bookmark {
+	day := func(d int) time.Time { return time.Date(2024, 1, d, 0, 0, 0, 0, time.UTC) }
+	return []*bookmark.Bookmark{
+		{ID: "go", URL: "https://go.dev/", Title: "Go", Tags: [][]string{{"lang", "go"}}, Folder: []string{"Dev"}, DateAdded: day(1)},
+		{ID: "rust", URL: "https://rust-lang.org/", Title: "Rust", Tags: [][]string{{"lang", "rust"}}, Folder: []string{"Dev", "Systems"}, DateAdded: day(3)},
+		{ID: "diary", URL: "https://example.com/diary", Title: "Diary", Tags: [][]string{{"private"}}, Folder: []string{"Dev"}, DateAdded: day(2)},
+		{ID: "news", URL: "https://example.com/news", Title: "News <today>", Folder: []string{"R&D"}, DateAdded: day(4)},
+	}
+}
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("missing %s: %v", path, err)
+	}
+	return string(data)
+}
+
+func TestGenerate(t *testing.T) {
+	dir := t.TempDir()
+	result, err := Generate(dir, testBookmarks(), Options{Title: "Team links", Exclude: [][]string{PrivateTag}})
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if result.Bookmarks != 3 || result.Excluded != 1 {
+		t.Errorf("Result = %+v, want 3 bookmarks and 1 excluded", result)
+	}
+	// index, 2 overviews, lang, lang/go, lang/rust, Dev, Dev/Systems, R&D
+	if result.Pages != 9 {
+		t.Errorf("Pages = %v, want 9", result.Pages)
+	}
+
+	index := readFile(t, filepath.Join(dir, "index.html"))
+	for _, want := range []string{"<title>Team links</title>", `id="b-rust"`, "News &lt;today&gt;", `href="tags/lang/index.html"`, `src="search.js"`} {
+		if !strings.Contains(index, want) {
+			t.Errorf("index.html doesn't contain %q", want)
+		}
+	}
+	if strings.Contains(index, "Diary") {
+		t.Errorf("index.html lists the private bookmark")
+	}
+	if strings.Index(index, "Rust") > strings.Index(index, `id="b-go"`) {
+		t.Errorf("index.html doesn't list the newest bookmarks first")
+	}
+
+	lang := readFile(t, filepath.Join(dir, "tags", "lang", "index.html"))
+	if !strings.Contains(lang, `id="b-go"`) || !strings.Contains(lang, `id="b-rust"`) || !strings.Contains(lang, `href="../../style.css"`) {
+		t.Errorf("tags/lang/index.html doesn't list the nested tags' bookmarks with relative links")
+	}
+	goPage := readFile(t, filepath.Join(dir, "tags", "lang", "go", "index.html"))
+	if strings.Contains(goPage, `id="b-rust"`) || !strings.Contains(goPage, `href="../../../tags/lang/index.html">lang</a>`) {
+		t.Errorf("tags/lang/go/index.html should list only Go and link up to lang")
+	}
+
+	dev := readFile(t, filepath.Join(dir, "folders", "dev", "index.html"))
+	if !strings.Contains(dev, `id="b-rust"`) || strings.Contains(dev, "Diary") {
+		t.Errorf("folders/dev/index.html should include subfolders and leave out private bookmarks")
+	}
+	if _, err := os.Stat(filepath.Join(dir, "folders", "r-d", "index.html")); err != nil {
+		t.Errorf("folder R&D has no page: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "tags", "private")); err == nil {
+		t.Errorf("the private tag has a page")
+	}
+
+	for _, name := range []string{"search.json", "feed.xml", "style.css", "search.js"} {
+		if content := readFile(t, filepath.Join(dir, name)); strings.Contains(content, "Diary") {
+			t.Errorf("%s mentions the private bookmark", name)
+		}
+	}
+}
+
+func TestGenerate_Empty(t *testing.T) {
+	dir := t.TempDir()
+	result, err := Generate(dir, nil, Options{})
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if result.Bookmarks != 0 || result.Pages != 3 {
+		t.Errorf("Result = %+v, want no bookmarks and 3 pages", result)
+	}
+	if index := readFile(t, filepath.Join(dir, "index.html")); !strings.Contains(index, "<title>"+DefaultTitle+"</title>") {
+		t.Errorf("index.html doesn't use the default title")
+	}
+}
+
+func TestNewPageTree(t *testing.T) {
+	tree := newFolderPages(bookmark.FolderTree([]*bookmark.Bookmark{
+		{Folder: []string{"Read Later"}},
+		{Folder: []string{"read-later"}},
+		{Folder: []string{"Read Later", "Papers"}},
+		{Folder: []string{"???"}},
+	}))
+
+	want := map[string]string{
+		"???":                 "folders/folder/index.html",
+		"Read Later":          "folders/read-later/index.html",
+		"Read Later / Papers": "folders/read-later/papers/index.html",
+		"read-later":          "folders/read-later-2/index.html",
+	}
+	for _, node := range tree.nodes {
+		label := tree.label(node.Path)
+		if node.File != want[label] {
+			t.Errorf("page of %q = %q, want %q", label, node.File, want[label])
+		}
+	}
+	if len(tree.nodes) != len(want) {
+		t.Errorf("tree has %v pages, want %v", len(tree.nodes), len(want))
+	}
+	if tree.Root.File != "folders/index.html" {
+		t.Errorf("root page = %q, want folders/index.html", tree.Root.File)
+	}
+}
+
+func TestExcluded(t *testing.T) {
+	exclude := [][]string{PrivateTag}
+	tests := []struct {
+		tags [][]string
+		want bool
+	}{
+		{[][]string{{"private"}}, true},
+		{[][]string{{"go"}, {"private", "family"}}, true},
+		{[][]string{{"work", "private"}}, false},
+		{nil, false},
+	}
+	for _, tt := range tests {
+		if got := excluded(&bookmark.Bookmark{Tags: tt.tags}, exclude); got != tt.want {
+			t.Errorf("excluded(%v) = %v, want %v", tt.tags, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,264 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/lelopez-io/moxli/internal/bookmark"
+)
+
+// bulkAction identifies a bulk action that needs text input
+type bulkAction int
+
+const (
+	bulkNone bulkAction = iota
+	bulkAddTags
+	bulkRemoveTags
+	bulkSetFolder
+)
+
+// bulkPrompts are the input prompts for actions that take text
+var bulkPrompts = map[bulkAction]string{
+	bulkAddTags:    "Add tags: ",
+	bulkRemoveTags: "Remove tags: ",
+	bulkSetFolder:  "Set folder: ",
+}
+
+// toggleMark marks or unmarks the bookmark under the cursor
+func (m *Model) toggleMark() {
+	bm := m.selectedBookmark()
+	if bm == nil {
+		return
+	}
+	if m.marked[bm] {
+		delete(m.marked, bm)
+	} else {
+		m.marked[bm] = true
+	}
+}
+
+// markAllVisible marks every bookmark matching the current filter
+func (m *Model) markAllVisible() {
+	for _, bm := range m.visibleBookmarks() {
+		m.marked[bm] = true
+	}
+}
+
+// invertMarks flips the mark on every visible bookmark
+func (m *Model) invertMarks() {
+	for _, bm := range m.visibleBookmarks() {
+		if m.marked[bm] {
+			delete(m.marked, bm)
+		} else {
+			m.marked[bm] = true
+		}
+	}
+}
+
+// markedBookmarks returns the marked bookmarks in collection order
+func (m Model) markedBookmarks() []*bookmark.Bookmark {
+	var marked []*bookmark.Bookmark
+	for _, bm := range m.collection.Bookmarks {
+		if m.marked[bm] {
+			marked = append(marked, bm)
+		}
+	}
+	return marked
+}
+
+// openBulkMenu shows the bulk action menu when bookmarks are marked
+func (m *Model) openBulkMenu() {
+	if len(m.marked) == 0 {
+		m.statusMsg = "No bookmarks marked (m: mark, *: mark all, ~: invert)"
+		return
+	}
+	m.bulkMenu = true
+	m.bulkAction = bulkNone
+}
+
+// closeBulkMenu hides the bulk action menu and its input
+func (m *Model) closeBulkMenu() {
+	m.bulkMenu = false
+	m.bulkAction = bulkNone
+	m.bulkInput.Blur()
+}
+
+func (m Model) updateBulk(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.bulkAction != bulkNone {
+		switch msg.String() {
+		case "esc":
+			m.bulkAction = bulkNone
+			m.bulkInput.Blur()
+			return m, nil
+		case "enter":
+			m.applyBulkInput()
+			return m, nil
+		}
+		var cmd tea.Cmd
+		m.bulkInput, cmd = m.bulkInput.Update(msg)
+		return m, cmd
+	}
+
+	switch msg.String() {
+	case "esc":
+		m.closeBulkMenu()
+	case "t":
+		m.promptBulk(bulkAddTags, "parent/child, other")
+	case "r":
+		m.promptBulk(bulkRemoveTags, "parent/child, other")
+	case "f":
+		m.promptBulk(bulkSetFolder, "Bookmarks Bar / Research")
+	case "s", "S":
+		starred := msg.String() == "s"
+		label := "Unstarred"
+		if starred {
+			label = "Starred"
+		}
+		m.applyBulk(label, func(bm *bookmark.Bookmark) bool {
+			if bm.IsStarred == starred {
+				return false
+			}
+			bm.IsStarred = starred
+			return true
+		})
+	case "D":
+		m.deleteMarked()
+	case "w":
+		// Export only the marked bookmarks
+		selection := bookmark.NewCollection()
+		for _, bm := range m.markedBookmarks() {
+			selection.Add(bm)
+		}
+		selection.UpdateMetadata()
+		m.closeBulkMenu()
+		m.openExport(selection)
+	}
+
+	return m, nil
+}
+
+// promptBulk switches the bulk menu to text input for the given action
+func (m *Model) promptBulk(action bulkAction, placeholder string) {
+	ti := textinput.New()
+	ti.CharLimit = 256
+	ti.Width = 50
+	ti.Prompt = bulkPrompts[action]
+	ti.Placeholder = placeholder
+	ti.Focus()
+	m.bulkInput = ti
+	m.bulkAction = action
+}
+
+// applyBulkInput runs the pending text action on the marked bookmarks
+func (m *Model) applyBulkInput() {
+	value := strings.TrimSpace(m.bulkInput.Value())
+
+	switch m.bulkAction {
+	case bulkAddTags, bulkRemoveTags:
+		tags := bookmark.ParseTags(value)
+		if len(tags) == 0 {
+			m.err = fmt.Errorf("no tags given")
+			return
+		}
+		add := m.bulkAction == bulkAddTags
+		label := "Removed tags from"
+		if add {
+			label = "Tagged"
+		}
+		m.applyBulk(label, func(bm *bookmark.Bookmark) bool {
+			changed := false
+			for _, tag := range tags {
+				if add {
+					changed = bm.AddTag(tag) || changed
+				} else {
+					changed = bm.RemoveTag(tag) || changed
+				}
+			}
+			return changed
+		})
+	case bulkSetFolder:
+		folder := bookmark.ParseFolder(value)
+		m.applyBulk("Moved", func(bm *bookmark.Bookmark) bool {
+			if bookmark.FormatFolder(bm.Folder) == bookmark.FormatFolder(folder) {
+				return false
+			}
+			bm.Folder = folder
+			return true
+		})
+	}
+}
+
+// applyBulk runs change on every marked bookmark and records the result as
+// one undoable action. change reports whether it modified the bookmark.
+func (m *Model) applyBulk(label string, change func(*bookmark.Bookmark) bool) {
+	m.closeBulkMenu()
+	m.changeBookmarks(label, m.markedBookmarks(), change)
+}
+
+// changeBookmarks runs change on each of targets and records the result as
+// one undoable action, reporting the outcome in the status line
+func (m *Model) changeBookmarks(label string, targets []*bookmark.Bookmark, change func(*bookmark.Bookmark) bool) {
+	now := time.Now()
+	var ops []bookmark.Op
+	changed := 0
+	for _, bm := range targets {
+		before := bm.Clone()
+		if change(bm) {
+			bm.LastModified = now
+			ops = append(ops, bookmark.DiffOps(before, bm)...)
+			changed++
+		}
+	}
+
+	if changed == 0 {
+		m.statusMsg = "Nothing to change"
+		return
+	}
+
+	m.history.Record(fmt.Sprintf("%s %d bookmark(s)", strings.ToLower(label), changed), ops)
+	m.collection.Reindex()
+	m.collection.UpdateMetadata()
+	m.refreshAfterCollectionChange()
+	m.statusMsg = fmt.Sprintf("%s %d bookmark(s) (u: undo)", label, changed)
+}
+
+// deleteMarked removes the marked bookmarks from the collection
+func (m *Model) deleteMarked() {
+	marked := m.markedBookmarks()
+	ops := make([]bookmark.Op, 0, len(marked))
+	for _, bm := range marked {
+		ops = append(ops, bookmark.RemoveOp(bm))
+	}
+
+	m.closeBulkMenu()
+	if err := m.doAndRecord(fmt.Sprintf("delete %d bookmark(s)", len(marked)), ops); err != nil {
+		m.err = err
+		return
+	}
+	m.pruneMarks()
+	m.statusMsg = fmt.Sprintf("Deleted %d bookmark(s) (u: undo)", len(marked))
+}
+
+// bulkMenuView renders the bulk action menu shown above the browser list
+func (m Model) bulkMenuView() string {
+	var s strings.Builder
+
+	title := lipgloss.NewStyle().Bold(true).Render(fmt.Sprintf("Bulk actions on %d marked bookmark(s)", len(m.marked)))
+	s.WriteString("\n  " + title + "\n")
+
+	if m.bulkAction != bulkNone {
+		s.WriteString("  " + m.bulkInput.View() + "\n")
+		s.WriteString(renderKeybindings("enter: apply  esc: back"))
+		return s.String()
+	}
+
+	help := `t: add tags  r: remove tags  f: set folder  s: star  S: unstar
+D: delete  w: export selection  esc: close`
+	s.WriteString(renderKeybindings(help))
+	return s.String()
+}
@@ -0,0 +1,104 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/lelopez-io/moxli/internal/bookmark"
+)
+
+// candidatePageSize is the number of merge candidates listed at once
+const candidatePageSize = 8
+
+// openCandidates lists the fuzzy matches of the last merge that were not
+// applied, so they can be checked by hand
+func (m *Model) openCandidates() {
+	if m.mergeReport == nil || len(m.mergeReport.Candidates) == 0 {
+		m.statusMsg = "No merge candidates to review"
+		return
+	}
+	m.candidateSelected = 0
+	m.candidateOffset = 0
+	m.currentView = CandidatesView
+}
+
+func (m Model) updateCandidates(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	candidates := m.mergeReport.Candidates
+
+	switch msg.String() {
+	case "esc", "c":
+		m.currentView = BrowserView
+		return m, nil
+	case "up", "k":
+		if m.candidateSelected > 0 {
+			m.candidateSelected--
+		}
+	case "down", "j":
+		if m.candidateSelected < len(candidates)-1 {
+			m.candidateSelected++
+		}
+	case "g":
+		m.candidateSelected = 0
+	case "G":
+		m.candidateSelected = len(candidates) - 1
+	case "enter":
+		// Show the base bookmark the source was compared with
+		m.filterInput.SetValue("url:" + queryValue(candidates[m.candidateSelected].Base.URL))
+		m.currentView = BrowserView
+		m.applyFilter()
+		return m, nil
+	}
+
+	if m.candidateSelected < m.candidateOffset {
+		m.candidateOffset = m.candidateSelected
+	} else if m.candidateSelected >= m.candidateOffset+candidatePageSize {
+		m.candidateOffset = m.candidateSelected - candidatePageSize + 1
+	}
+	return m, nil
+}
+
+func (m Model) candidatesView() string {
+	var s strings.Builder
+	candidates := m.mergeReport.Candidates
+
+	s.WriteString("\n")
+	s.WriteString(headerStyle.Render("🔍 Merge Candidates"))
+	s.WriteString("\n\n")
+
+	stats := fmt.Sprintf("Candidates: %d │ Applied: %d │ Selected: %d/%d",
+		len(candidates), len(m.mergeReport.Applied), m.candidateSelected+1, len(candidates))
+	s.WriteString("  " + statStyle.Render(stats) + "\n\n")
+
+	end := min(m.candidateOffset+candidatePageSize, len(candidates))
+	for i := m.candidateOffset; i < end; i++ {
+		c := candidates[i]
+
+		cursor := "    "
+		source := candidateLabel(c.Source)
+		if i == m.candidateSelected {
+			cursor = "  ▶ "
+			source = selectedItemStyle.Render(source)
+		}
+		s.WriteString(cursor + statStyle.Render(fmt.Sprintf("%3.0f%%", c.Confidence*100)) + "  " + source + "\n")
+		s.WriteString("          " + urlStyle.Render(c.Source.URL) + "\n")
+		s.WriteString("        → " + candidateLabel(c.Base) + "\n")
+		s.WriteString("          " + urlStyle.Render(c.Base.URL) + "\n")
+	}
+
+	s.WriteString("\n  " + urlStyle.Render("Candidates were kept as separate bookmarks; confidence is how likely the source is the base bookmark") + "\n")
+
+	help := `j/k: move  enter: show base bookmark  esc: back`
+	s.WriteString(renderKeybindings(help))
+
+	return s.String()
+}
+
+// candidateLabel is how a bookmark of a merge candidate is named
+func candidateLabel(b *bookmark.Bookmark) string {
+	if b.Title != "" {
+		return b.Title
+	}
+	return b.URL
+}
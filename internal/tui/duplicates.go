@@ -0,0 +1,253 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/lelopez-io/moxli/internal/bookmark"
+)
+
+// openDuplicates scans the collection for duplicates and switches to the review view
+func (m *Model) openDuplicates() {
+	groups := bookmark.FindDuplicates(m.collection, bookmark.DuplicateOptions{
+		DomainTitle: true,
+		FuzzyTitle:  true,
+	})
+
+	m.dupAutoGroups = nil
+	m.dupGroups = nil
+	for _, g := range groups {
+		if g.AutoMergeable() {
+			m.dupAutoGroups = append(m.dupAutoGroups, g)
+		} else {
+			m.dupGroups = append(m.dupGroups, g)
+		}
+	}
+
+	m.dupGroupIdx = 0
+	m.dupMemberIdx = 0
+	m.dupStatus = ""
+	m.currentView = DuplicatesView
+}
+
+func (m Model) updateDuplicates(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.currentView = BrowserView
+		return m, nil
+	case "a":
+		// Merge every exact-URL group in one go
+		if len(m.dupAutoGroups) > 0 {
+			// Each group's ops depend on the positions left by the previous
+			// group, so apply them one by one and record a single action.
+			// A group that fails is left unmerged with the ones after it.
+			var ops []bookmark.Op
+			var mergeErr error
+			merged := 0
+			for _, g := range m.dupAutoGroups {
+				applied, err := bookmark.ApplyOps(m.collection, bookmark.ConsolidationOps(m.collection, g, g.Consolidate()))
+				if err != nil {
+					mergeErr = err
+					break
+				}
+				ops = append(ops, applied...)
+				merged++
+			}
+			if merged == 0 {
+				// Nothing changed, so the collection stays clean
+				m.dupStatus = mergeErr.Error()
+				return m, nil
+			}
+			m.history.Record(fmt.Sprintf("merge %d exact-URL group(s)", merged), ops)
+			m.dupStatus = fmt.Sprintf("Merged %d of %d exact-URL group(s): %v", merged, len(m.dupAutoGroups), mergeErr)
+			if mergeErr == nil {
+				m.dupStatus = fmt.Sprintf("Merged %d exact-URL group(s)", merged)
+			}
+			m.dupAutoGroups = m.dupAutoGroups[merged:]
+			m.refreshAfterCollectionChange()
+		}
+		return m, nil
+	}
+
+	if len(m.dupGroups) == 0 {
+		return m, nil
+	}
+	group := m.dupGroups[m.dupGroupIdx]
+
+	switch msg.String() {
+	case "n", "tab":
+		if m.dupGroupIdx < len(m.dupGroups)-1 {
+			m.dupGroupIdx++
+			m.dupMemberIdx = 0
+		}
+	case "p", "shift+tab":
+		if m.dupGroupIdx > 0 {
+			m.dupGroupIdx--
+			m.dupMemberIdx = 0
+		}
+	case "up", "k":
+		if m.dupMemberIdx > 0 {
+			m.dupMemberIdx--
+		}
+	case "down", "j":
+		if m.dupMemberIdx < len(group.Bookmarks)-1 {
+			m.dupMemberIdx++
+		}
+	case "s":
+		// Keep the member under the cursor as the survivor
+		group.Survivor = m.dupMemberIdx
+	case "1", "2", "3", "4", "5", "6":
+		// Keep one field from the member under the cursor
+		field := bookmark.PickableFields[int(msg.String()[0]-'1')]
+		group.Pick(field, m.dupMemberIdx)
+	case "x":
+		// Split a false positive out of the group
+		removed := group.Split(m.dupMemberIdx)
+		m.dupStatus = fmt.Sprintf("Split %q out of the group", displayTitle(removed))
+		if len(group.Bookmarks) < 2 {
+			m.removeDuplicateGroup()
+		} else if m.dupMemberIdx >= len(group.Bookmarks) {
+			m.dupMemberIdx = len(group.Bookmarks) - 1
+		}
+	case "enter":
+		// Resolve the group into a single bookmark
+		merged := group.Consolidate()
+		label := fmt.Sprintf("merge %d bookmarks into %q", len(group.Bookmarks), displayTitle(merged))
+		if err := m.doAndRecord(label, bookmark.ConsolidationOps(m.collection, group, merged)); err != nil {
+			m.dupStatus = err.Error()
+			return m, nil
+		}
+		m.dupStatus = fmt.Sprintf("Merged %d bookmarks into %q", len(group.Bookmarks), displayTitle(merged))
+		m.removeDuplicateGroup()
+	}
+
+	return m, nil
+}
+
+// removeDuplicateGroup drops the current group from the review list
+func (m *Model) removeDuplicateGroup() {
+	m.dupGroups = append(m.dupGroups[:m.dupGroupIdx], m.dupGroups[m.dupGroupIdx+1:]...)
+	if m.dupGroupIdx >= len(m.dupGroups) {
+		m.dupGroupIdx = max(0, len(m.dupGroups)-1)
+	}
+	m.dupMemberIdx = 0
+}
+
+// refreshAfterCollectionChange marks the collection as modified and re-applies
+// the active filter so the browser never points at removed bookmarks
+func (m *Model) refreshAfterCollectionChange() {
+	m.dirty = true
+	m.fuzzyIndex = nil
+	m.fulltextIndex = nil
+	m.resetFolderTree()
+	m.sortCollection()
+	if m.filteredBookmarks != nil {
+		m.applyFilter()
+		return
+	}
+	if m.browserSelected >= len(m.collection.Bookmarks) {
+		m.browserSelected = max(0, len(m.collection.Bookmarks)-1)
+		m.browserOffset = max(0, m.browserSelected-(m.pageSize()-1))
+	}
+}
+
+// displayTitle returns the bookmark title or a placeholder
+func displayTitle(b *bookmark.Bookmark) string {
+	if b.Title == "" {
+		return "(no title)"
+	}
+	return b.Title
+}
+
+func (m Model) duplicatesView() string {
+	var s strings.Builder
+
+	s.WriteString("\n")
+	s.WriteString(headerStyle.Render("🧬 Duplicate Review"))
+	s.WriteString("\n\n")
+
+	stats := fmt.Sprintf("Groups to review: %d", len(m.dupGroups))
+	if len(m.dupAutoGroups) > 0 {
+		stats += fmt.Sprintf(" │ Exact-URL groups: %d (press a to merge)", len(m.dupAutoGroups))
+	}
+	s.WriteString("  " + statStyle.Render(stats) + "\n")
+	if m.dupStatus != "" {
+		s.WriteString("  " + m.dupStatus + "\n")
+	}
+	s.WriteString("\n")
+
+	if len(m.dupGroups) == 0 {
+		s.WriteString("  No duplicate groups need review.\n")
+		s.WriteString(renderKeybindings("a: merge exact-URL groups  esc: back  " + m.quitKey() + ": quit"))
+		return s.String()
+	}
+
+	group := m.dupGroups[m.dupGroupIdx]
+
+	s.WriteString(fmt.Sprintf("  Group %d/%d  %s\n", m.dupGroupIdx+1, len(m.dupGroups),
+		labelStyle.Render(fmt.Sprintf("(%s: %s)", group.Reason, group.Key))))
+	s.WriteString("\n")
+
+	for i, b := range group.Bookmarks {
+		cursor := "    "
+		if i == m.dupMemberIdx {
+			cursor = "  ▶ "
+		}
+
+		survivor := "  "
+		if i == group.Survivor {
+			survivor = "★ "
+		}
+
+		title := displayTitle(b)
+		if i == m.dupMemberIdx {
+			title = selectedItemStyle.Render(title)
+		}
+		s.WriteString(cursor + survivor + title + pickedFields(group, i) + "\n")
+		s.WriteString("        " + urlStyle.Render(b.URL) + "\n")
+
+		details := []string{}
+		if b.Source != "" {
+			details = append(details, "source: "+b.Source)
+		}
+		if len(b.Folder) > 0 {
+			details = append(details, "folder: "+strings.Join(b.Folder, " / "))
+		}
+		if !b.DateAdded.IsZero() {
+			details = append(details, "added: "+b.DateAdded.Format("2006-01-02"))
+		}
+		if len(details) > 0 {
+			s.WriteString("        " + labelStyle.Render(strings.Join(details, "  ")) + "\n")
+		}
+		s.WriteString("\n")
+	}
+
+	// Preview of the consolidated bookmark
+	merged := group.Consolidate()
+	s.WriteString("  " + labelStyle.Render("Result:") + " " + displayTitle(merged) + "\n")
+	s.WriteString("          " + urlStyle.Render(merged.URL) + "\n")
+
+	help := `n/p: next/prev group  j/k: down/up  s: keep as survivor
+1-6: keep url/title/description/comment/keyword/folder from member
+x: split from group  enter: merge group  a: merge exact-URL groups
+esc: back  ` + m.quitKey() + ": quit"
+	s.WriteString(renderKeybindings(help))
+
+	return s.String()
+}
+
+// pickedFields lists the fields explicitly taken from member i
+func pickedFields(g *bookmark.DuplicateGroup, i int) string {
+	var fields []string
+	for _, field := range bookmark.PickableFields {
+		if member, picked := g.Picks[field]; picked && member == i {
+			fields = append(fields, string(field))
+		}
+	}
+	if len(fields) == 0 {
+		return ""
+	}
+	return statStyle.Render("  [" + strings.Join(fields, ", ") + "]")
+}
@@ -0,0 +1,405 @@
+package tui
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textarea"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/lelopez-io/moxli/internal/bookmark"
+	"github.com/lelopez-io/moxli/internal/exporter"
+)
+
+// editField identifies an input in the edit form
+type editField int
+
+const (
+	editTitle editField = iota
+	editURL
+	editDescription
+	editComment
+	editTags
+	editKeyword
+	editFolder
+	editStarred // Toggle row, not a text input
+)
+
+// editLabels are the form labels in field order
+var editLabels = []string{"Title", "URL", "Description", "Comment", "Tags", "Keyword", "Folder", "Starred"}
+
+// editLabelWidth is the width of the label column of the form
+const editLabelWidth = 12
+
+// editInput is a text field of the edit form: a single-line input, or a text
+// area for fields that can span several lines
+type editInput struct {
+	multiline bool
+	line      textinput.Model
+	area      textarea.Model
+	initial   string // Value as loaded, to tell whether the field was edited
+}
+
+func newEditInput(value string, multiline bool) editInput {
+	in := editInput{multiline: multiline}
+	if multiline {
+		in.area = textarea.New()
+		in.area.CharLimit = 0
+		in.area.ShowLineNumbers = false
+		in.area.Prompt = ""
+		in.area.SetWidth(60)
+		in.area.SetHeight(3)
+		// enter applies the form, so new lines take ctrl+j
+		in.area.KeyMap.InsertNewline = key.NewBinding(key.WithKeys("ctrl+j"))
+		in.area.SetValue(value)
+		in.area.Blur()
+	} else {
+		in.line = textinput.New()
+		in.line.CharLimit = 0
+		in.line.Width = 60
+		in.line.SetValue(value)
+	}
+	// Inputs may clean up the value, so compare against what they hold
+	in.initial = in.Value()
+	return in
+}
+
+func (in *editInput) Value() string {
+	if in.multiline {
+		return in.area.Value()
+	}
+	return in.line.Value()
+}
+
+// Changed reports whether the field was edited
+func (in *editInput) Changed() bool {
+	return in.Value() != in.initial
+}
+
+func (in *editInput) Focus() {
+	if in.multiline {
+		in.area.Focus()
+	} else {
+		in.line.Focus()
+	}
+}
+
+func (in *editInput) Blur() {
+	if in.multiline {
+		in.area.Blur()
+	} else {
+		in.line.Blur()
+	}
+}
+
+func (in *editInput) Update(msg tea.Msg) tea.Cmd {
+	var cmd tea.Cmd
+	if in.multiline {
+		in.area, cmd = in.area.Update(msg)
+	} else {
+		in.line, cmd = in.line.Update(msg)
+	}
+	return cmd
+}
+
+// View renders the field, indenting the lines of a text area under the first
+func (in *editInput) View(indent int) string {
+	if in.multiline {
+		return strings.ReplaceAll(in.area.View(), "\n", "\n"+strings.Repeat(" ", indent))
+	}
+	return in.line.View()
+}
+
+// movesWithin reports whether up or down moves the cursor inside a text
+// area rather than to another field
+func (in *editInput) movesWithin(direction string) bool {
+	if !in.multiline {
+		return false
+	}
+	if direction == "up" {
+		return in.area.Line() > 0
+	}
+	return in.area.Line() < in.area.LineCount()-1
+}
+
+// visibleBookmarks returns the bookmarks currently listed in the browser
+func (m Model) visibleBookmarks() []*bookmark.Bookmark {
+	if m.filteredBookmarks != nil {
+		return m.filteredBookmarks
+	}
+	if m.sortedBookmarks != nil {
+		return m.sortedBookmarks
+	}
+	return m.collection.Bookmarks
+}
+
+// selectedBookmark returns the bookmark under the browser cursor, or nil
+func (m Model) selectedBookmark() *bookmark.Bookmark {
+	if m.collection == nil {
+		return nil
+	}
+	bookmarks := m.visibleBookmarks()
+	if m.browserSelected < 0 || m.browserSelected >= len(bookmarks) {
+		return nil
+	}
+	return bookmarks[m.browserSelected]
+}
+
+// startEditing fills the edit form from the selected bookmark
+func (m *Model) startEditing() {
+	bm := m.selectedBookmark()
+	if bm == nil {
+		return
+	}
+
+	values := []string{
+		bm.Title,
+		bm.URL,
+		bm.Description,
+		bm.Comment,
+		bookmark.FormatTags(bm.Tags),
+		bm.Keyword,
+		bookmark.FormatFolder(bm.Folder),
+	}
+
+	m.editInputs = make([]editInput, len(values))
+	for i, value := range values {
+		field := editField(i)
+		m.editInputs[i] = newEditInput(value, field == editDescription || field == editComment)
+	}
+	m.editInputs[editTags].line.Placeholder = "parent/child, other"
+	m.editInputs[editFolder].line.Placeholder = "Bookmarks Bar / Research"
+
+	m.editStarred = bm.IsStarred
+	m.editFocus = editTitle
+	m.editInputs[editTitle].Focus()
+	m.editing = true
+	m.err = nil
+}
+
+// stopEditing closes the edit form without applying changes
+func (m *Model) stopEditing() {
+	m.editing = false
+	m.editInputs = nil
+}
+
+// focusEditField moves focus to the given field, blurring the previous one
+func (m *Model) focusEditField(field editField) {
+	if m.editFocus < editStarred {
+		m.editInputs[m.editFocus].Blur()
+	}
+	m.editFocus = field
+	if field < editStarred {
+		m.editInputs[field].Focus()
+	}
+}
+
+func (m Model) updateEdit(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.stopEditing()
+		return m, nil
+	case "tab", "down":
+		if msg.String() == "tab" || m.editFocus == editStarred || !m.editInputs[m.editFocus].movesWithin("down") {
+			m.focusEditField((m.editFocus + 1) % (editStarred + 1))
+			return m, nil
+		}
+	case "shift+tab", "up":
+		if msg.String() == "shift+tab" || m.editFocus == editStarred || !m.editInputs[m.editFocus].movesWithin("up") {
+			m.focusEditField((m.editFocus + editStarred) % (editStarred + 1))
+			return m, nil
+		}
+	case "enter":
+		if err := m.applyEdit(); err != nil {
+			m.err = err
+			return m, nil
+		}
+		m.stopEditing()
+		return m, nil
+	case "ctrl+s":
+		if err := m.applyEdit(); err != nil {
+			m.err = err
+			return m, nil
+		}
+		m.stopEditing()
+		m.saveCollection()
+		return m, nil
+	}
+
+	if m.editFocus == editStarred {
+		if msg.String() == " " {
+			m.editStarred = !m.editStarred
+		}
+		return m, nil
+	}
+
+	return m, m.editInputs[m.editFocus].Update(msg)
+}
+
+// applyEdit writes the edited form values back to the selected bookmark.
+// Fields left alone keep their stored value, so nothing the form can't show,
+// such as line breaks in a title, is lost.
+func (m *Model) applyEdit() error {
+	bm := m.selectedBookmark()
+	if bm == nil {
+		return fmt.Errorf("no bookmark selected")
+	}
+
+	value := func(field editField) string {
+		return strings.TrimSpace(m.editInputs[field].Value())
+	}
+	changed := func(field editField) bool {
+		return m.editInputs[field].Changed()
+	}
+
+	edited := bm.Clone()
+	if changed(editURL) {
+		rawURL := value(editURL)
+		if rawURL == "" {
+			return fmt.Errorf("URL is required")
+		}
+		normalized, err := bookmark.NormalizeURL(rawURL)
+		if err != nil {
+			return fmt.Errorf("invalid URL: %w", err)
+		}
+		edited.URL = rawURL
+		edited.NormalizedURL = normalized
+	}
+	if changed(editTitle) {
+		edited.Title = value(editTitle)
+	}
+	if changed(editDescription) {
+		edited.Description = value(editDescription)
+	}
+	if changed(editComment) {
+		edited.Comment = value(editComment)
+	}
+	if changed(editTags) {
+		edited.Tags = bookmark.ParseTags(value(editTags))
+	}
+	if changed(editKeyword) {
+		edited.Keyword = value(editKeyword)
+	}
+	if changed(editFolder) {
+		edited.Folder = bookmark.ParseFolder(value(editFolder))
+	}
+	edited.IsStarred = m.editStarred
+
+	if len(bookmark.DiffOps(bm, edited)) == 0 {
+		// Nothing changed, so the collection stays clean
+		m.err = nil
+		return nil
+	}
+	edited.LastModified = time.Now()
+	m.history.Record("edit "+displayTitle(edited), bookmark.DiffOps(bm, edited))
+	*bm = *edited
+
+	m.collection.Reindex()
+	m.collection.UpdateMetadata()
+	m.fuzzyIndex = nil
+	m.fulltextIndex = nil
+	m.resetFolderTree()
+	m.resort()
+	m.dirty = true
+	m.err = nil
+	return nil
+}
+
+// saveCollection writes the collection back to the session's current file,
+// keeping the previous version as a backup
+func (m *Model) saveCollection() {
+	if m.currentSession == nil || m.currentSession.CurrentFile == "" {
+		m.err = fmt.Errorf("no current file in session")
+		return
+	}
+
+	path := m.currentSession.CurrentFile
+	if !strings.EqualFold(filepath.Ext(path), ".json") {
+		m.err = fmt.Errorf("%s is not an Anybox JSON file; export the collection instead", filepath.Base(path))
+		return
+	}
+
+	backup, err := exporter.WriteFile(path, &exporter.AnyboxExporter{Indent: true}, m.collection)
+	if err != nil {
+		m.err = fmt.Errorf("save failed: %w", err)
+		return
+	}
+
+	if err := m.sessionMgr.Save(m.currentSession); err != nil {
+		m.err = fmt.Errorf("saved collection but failed to update session: %w", err)
+	}
+	m.saveHistory(path)
+
+	m.dirty = false
+	m.statusMsg = "Saved " + filepath.Base(path)
+	if backup != "" {
+		m.statusMsg += " (backup: " + filepath.Base(backup) + ")"
+	}
+}
+
+// dirtyMarker returns the unsaved-changes indicator for view headers
+func (m Model) dirtyMarker() string {
+	if m.dirty {
+		return " ●"
+	}
+	return ""
+}
+
+// statusLine renders the latest error or status message, if any
+func (m Model) statusLine() string {
+	switch {
+	case m.err != nil:
+		return "  ⚠️  " + m.err.Error() + "\n"
+	case m.statusMsg != "":
+		return "  " + statStyle.Render(m.statusMsg) + "\n"
+	}
+	return ""
+}
+
+func (m Model) editView() string {
+	var s strings.Builder
+
+	s.WriteString("\n")
+	s.WriteString(headerStyle.Render("✏️  Edit Bookmark" + m.dirtyMarker()))
+	s.WriteString("\n\n")
+	s.WriteString(m.statusLine())
+
+	fieldStyle := labelStyle.Width(editLabelWidth)
+
+	for i, label := range editLabels {
+		field := editField(i)
+		cursor := "  "
+		if field == m.editFocus {
+			cursor = "▶ "
+		}
+
+		if field == editStarred {
+			star := "[ ]"
+			if m.editStarred {
+				star = "[⭐]"
+			}
+			s.WriteString("  " + cursor + fieldStyle.Render(label) + star + "\n")
+			continue
+		}
+
+		s.WriteString("  " + cursor + fieldStyle.Render(label) + m.editInputs[field].View(4+editLabelWidth) + "\n")
+
+		// Show how the tag input will be stored
+		if field == editTags {
+			for _, path := range bookmark.ParseTags(m.editInputs[editTags].Value()) {
+				s.WriteString("                  • " +
+					tagStyle.Render(strings.Join(path, " → ")) + "\n")
+			}
+		}
+	}
+
+	help := `tab/↓: next field  shift+tab/↑: previous field  ctrl+j: new line  space: toggle star
+enter: apply  ctrl+s: apply and save  esc: cancel`
+	s.WriteString(renderKeybindings(help))
+
+	return s.String()
+}
@@ -0,0 +1,220 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/lelopez-io/moxli/internal/bookmark"
+	"github.com/lelopez-io/moxli/internal/exporter"
+)
+
+// maxInlineErrors limits how many validation errors the export dialog lists
+const maxInlineErrors = 8
+
+// exportFocus tracks which part of the export dialog receives keys
+type exportFocus int
+
+const (
+	exportFocusFormat exportFocus = iota
+	exportFocusPath
+)
+
+// openExport shows the export dialog for target (the whole collection or a
+// selection of it) with a default output path
+func (m *Model) openExport(target *bookmark.Collection) {
+	m.exportTarget = target
+	m.exportFormats = exporter.Formats()
+	m.exportFormatIdx = 0
+	m.exportFocus = exportFocusFormat
+	m.exportErrors = nil
+	m.exportConfirm = false
+
+	dir := "."
+	if m.currentSession != nil && m.currentSession.WorkingDir != "" {
+		dir = m.currentSession.WorkingDir
+	}
+	name := "moxli-export-" + time.Now().Format("2006-01-02") + m.exportFormats[0].Extension
+
+	ti := textinput.New()
+	ti.CharLimit = 256
+	ti.Width = 60
+	ti.SetValue(filepath.Join(dir, name))
+	m.exportPathInput = ti
+
+	m.previousView = m.currentView
+	m.currentView = ExportView
+}
+
+func (m Model) updateExport(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.exportPathInput.Blur()
+		m.currentView = m.previousView
+		return m, nil
+	case "tab", "shift+tab":
+		if m.exportFocus == exportFocusFormat {
+			m.exportFocus = exportFocusPath
+			m.exportPathInput.Focus()
+		} else {
+			m.exportFocus = exportFocusFormat
+			m.exportPathInput.Blur()
+		}
+		return m, nil
+	case "enter":
+		m.runExport()
+		return m, nil
+	}
+
+	if m.exportFocus == exportFocusFormat {
+		switch msg.String() {
+		case "up", "k":
+			if m.exportFormatIdx > 0 {
+				m.selectExportFormat(m.exportFormatIdx - 1)
+			}
+		case "down", "j":
+			if m.exportFormatIdx < len(m.exportFormats)-1 {
+				m.selectExportFormat(m.exportFormatIdx + 1)
+			}
+		}
+		return m, nil
+	}
+
+	// Any edit to the path invalidates a pending overwrite confirmation
+	m.exportConfirm = false
+	var cmd tea.Cmd
+	m.exportPathInput, cmd = m.exportPathInput.Update(msg)
+	return m, cmd
+}
+
+// selectExportFormat switches format and swaps the output path's extension to match
+func (m *Model) selectExportFormat(idx int) {
+	previous := m.exportFormats[m.exportFormatIdx]
+	m.exportFormatIdx = idx
+	m.exportConfirm = false
+
+	path := m.exportPathInput.Value()
+	if strings.EqualFold(filepath.Ext(path), previous.Extension) {
+		path = strings.TrimSuffix(path, filepath.Ext(path)) + m.exportFormats[idx].Extension
+		m.exportPathInput.SetValue(path)
+	}
+}
+
+// exportPath returns the output path with "~/" expanded
+func (m Model) exportPath() string {
+	path := strings.TrimSpace(m.exportPathInput.Value())
+	if strings.HasPrefix(path, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			path = filepath.Join(home, path[2:])
+		}
+	}
+	return path
+}
+
+// runExport validates the export target and writes it in the selected format
+func (m *Model) runExport() {
+	path := m.exportPath()
+	if path == "" {
+		m.err = fmt.Errorf("output path is required")
+		return
+	}
+
+	result := exporter.ValidateCollection(m.exportTarget)
+	m.exportErrors = result.Errors
+	if !result.Valid {
+		return
+	}
+
+	if pathExists(path) && !m.exportConfirm {
+		m.exportConfirm = true
+		return
+	}
+
+	format := m.exportFormats[m.exportFormatIdx]
+	backup, err := exporter.WriteFile(path, format.New(), m.exportTarget)
+	if err != nil {
+		m.err = fmt.Errorf("export failed: %w", err)
+		return
+	}
+
+	m.statusMsg = fmt.Sprintf("Exported %d bookmarks to %s", len(m.exportTarget.Bookmarks), path)
+	if backup != "" {
+		m.statusMsg += " (backup: " + filepath.Base(backup) + ")"
+	}
+
+	// Anybox JSON of the whole collection can be reopened, so it becomes the
+	// file the session works on
+	if format.Name == "anybox" && m.exportTarget == m.collection && m.currentSession != nil {
+		m.currentSession.CurrentFile = path
+		m.currentSession.WorkingDir = filepath.Dir(path)
+		if err := m.sessionMgr.Save(m.currentSession); err != nil {
+			m.err = fmt.Errorf("exported but failed to update session: %w", err)
+		}
+		m.saveHistory(path)
+		m.dirty = false
+	}
+
+	m.exportPathInput.Blur()
+	m.currentView = m.previousView
+}
+
+func (m Model) exportView() string {
+	var s strings.Builder
+
+	s.WriteString("\n")
+	s.WriteString(headerStyle.Render("💾 Export Collection"))
+	s.WriteString("\n\n")
+
+	if m.err != nil {
+		s.WriteString(fmt.Sprintf("  ⚠️  Error: %v\n\n", m.err))
+	}
+
+	count := fmt.Sprintf("%d bookmarks", len(m.exportTarget.Bookmarks))
+	if m.exportTarget != m.collection {
+		count = fmt.Sprintf("%d marked of %d bookmarks", len(m.exportTarget.Bookmarks), len(m.collection.Bookmarks))
+	}
+	s.WriteString("  " + statStyle.Render(count) + "\n\n")
+
+	s.WriteString("  " + labelStyle.Render("Format:") + "\n")
+	for i, f := range m.exportFormats {
+		cursor := "    "
+		if i == m.exportFormatIdx {
+			cursor = "  ▶ "
+		}
+		label := fmt.Sprintf("%s (%s)", f.Label, f.Extension)
+		if i == m.exportFormatIdx && m.exportFocus == exportFocusFormat {
+			label = selectedItemStyle.Render(label)
+		}
+		s.WriteString(cursor + label + "\n")
+	}
+	s.WriteString("\n")
+
+	s.WriteString("  " + labelStyle.Render("Output path:") + "\n")
+	s.WriteString("  " + m.exportPathInput.View() + "\n")
+
+	if m.exportConfirm {
+		s.WriteString("\n  ⚠️  " + m.exportPath() + " already exists. Press enter again to overwrite (a .bak copy is kept).\n")
+	}
+
+	if len(m.exportErrors) > 0 {
+		s.WriteString(fmt.Sprintf("\n  ⚠️  Validation failed with %d error(s):\n", len(m.exportErrors)))
+		for i, e := range m.exportErrors {
+			if i == maxInlineErrors {
+				s.WriteString(fmt.Sprintf("    … and %d more\n", len(m.exportErrors)-maxInlineErrors))
+				break
+			}
+			s.WriteString("    • " + e.Error() + "\n")
+		}
+	}
+
+	help := `tab: switch between format and path  j/k: choose format
+enter: export  esc: cancel`
+	s.WriteString(renderKeybindings(help))
+
+	return s.String()
+}
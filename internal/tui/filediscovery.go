@@ -1,7 +1,6 @@
 package tui
 
 import (
-	"bytes"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -129,53 +128,18 @@ func (fd *FileDiscovery) Clear() {
 	fd.files = make([]*DiscoveredFile, 0)
 }
 
-// detectFileFormat detects the format of a bookmark file
+// detectFileFormat detects the format of a bookmark file.
+// Format names match the importer source identifiers.
 func detectFileFormat(path string) (FileFormat, error) {
 	content, err := os.ReadFile(path)
 	if err != nil {
 		return FormatUnknown, fmt.Errorf("failed to read file: %w", err)
 	}
 
-	reader := bytes.NewReader(content)
-
-	// Try Anybox JSON first
-	anyboxImporter := &importer.AnyboxImporter{}
-	if anyboxImporter.Detect(reader) {
-		return FormatAnybox, nil
-	}
-
-	// Reset reader
-	if _, err := reader.Seek(0, 0); err != nil {
-		return FormatUnknown, fmt.Errorf("failed to reset reader: %w", err)
-	}
-
-	// Try Anybox HTML (has TAGS attribute - check before Firefox)
-	anyboxHTMLImporter := &importer.AnyboxHTMLImporter{}
-	if anyboxHTMLImporter.Detect(reader) {
-		return FormatAnyboxHTML, nil
-	}
-
-	// Reset reader
-	if _, err := reader.Seek(0, 0); err != nil {
-		return FormatUnknown, fmt.Errorf("failed to reset reader: %w", err)
-	}
-
-	// Try Firefox (HTML with timestamps but no TAGS attribute)
-	firefoxImporter := &importer.FirefoxImporter{}
-	if firefoxImporter.Detect(reader) {
-		return FormatFirefox, nil
-	}
-
-	// Reset reader
-	if _, err := reader.Seek(0, 0); err != nil {
-		return FormatUnknown, fmt.Errorf("failed to reset reader: %w", err)
-	}
-
-	// Try Safari (HTML without timestamps)
-	safariImporter := &importer.SafariImporter{}
-	if safariImporter.Detect(reader) {
-		return FormatSafari, nil
+	imp := importer.Detect(content)
+	if imp == nil {
+		return FormatUnknown, nil
 	}
 
-	return FormatUnknown, nil
+	return FileFormat(imp.Source()), nil
 }
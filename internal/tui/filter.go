@@ -0,0 +1,191 @@
+package tui
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/lelopez-io/moxli/internal/bookmark"
+	"github.com/lelopez-io/moxli/internal/fulltext"
+	"github.com/lelopez-io/moxli/internal/fuzzy"
+	"github.com/lelopez-io/moxli/internal/query"
+)
+
+// applyFilter filters the browser list with the filter input, reporting an
+// invalid query as an error
+func (m *Model) applyFilter() {
+	if err := m.runFilter(); err != nil {
+		m.err = fmt.Errorf("invalid filter: %w", err)
+	}
+}
+
+// liveFilter refreshes results while the query is typed. Incomplete queries
+// that don't parse yet keep the previous results.
+func (m *Model) liveFilter() {
+	_ = m.runFilter()
+}
+
+// snippetWidth is the length in bytes of full-text snippets in the list
+const snippetWidth = 100
+
+// runFilter filters the collection. Plain words are fuzzy-matched and ranked
+// by score; input starting with "?" is a full-text search ranked by
+// relevance; anything using the query language (see package query) filters
+// in collection order. A sort mode other than file order then reorders the
+// results.
+func (m *Model) runFilter() error {
+	input := strings.TrimSpace(m.filterInput.Value())
+	m.fulltextTerms = nil
+
+	switch {
+	case input == "":
+		m.filteredBookmarks = nil
+		m.filterHighlights = nil
+
+	case strings.HasPrefix(input, "?"):
+		m.runFulltext(strings.TrimPrefix(input, "?"))
+
+	case query.IsPlain(input):
+		if m.fuzzyIndex == nil {
+			m.fuzzyIndex = fuzzy.NewIndex(m.collection.Bookmarks)
+		}
+		results := m.fuzzyIndex.Search(input)
+
+		m.filteredBookmarks = make([]*bookmark.Bookmark, len(results))
+		m.filterHighlights = make(map[*bookmark.Bookmark][]int, len(results))
+		for i, r := range results {
+			m.filteredBookmarks[i] = r.Bookmark
+			if len(r.TitlePositions) > 0 {
+				m.filterHighlights[r.Bookmark] = r.TitlePositions
+			}
+		}
+
+	default:
+		q, err := query.Parse(input)
+		if err != nil {
+			return err
+		}
+		m.filteredBookmarks = q.Filter(m.collection.Bookmarks)
+		m.filterHighlights = nil
+	}
+
+	if m.folderFilter != nil {
+		if m.filteredBookmarks == nil {
+			m.filteredBookmarks = m.filterFolder(m.collection.Bookmarks)
+		} else {
+			m.filteredBookmarks = m.filterFolder(m.filteredBookmarks)
+		}
+	}
+	if m.filteredBookmarks != nil && m.sortMode != bookmark.SortFileOrder {
+		m.filteredBookmarks = bookmark.Sort(m.filteredBookmarks, m.sortMode)
+	}
+
+	m.browserSelected = 0
+	m.browserOffset = 0
+	return nil
+}
+
+// runFulltext ranks the collection against a full-text query, highlighting
+// matching title words
+func (m *Model) runFulltext(input string) {
+	if m.fulltextIndex == nil {
+		m.fulltextIndex = m.buildFulltextIndex()
+	}
+	terms := fulltext.QueryTerms(input)
+	hits := m.fulltextIndex.Search(input)
+
+	want := make(map[string]bool, len(terms))
+	for _, t := range terms {
+		want[t] = true
+	}
+
+	m.fulltextTerms = terms
+	m.filteredBookmarks = make([]*bookmark.Bookmark, len(hits))
+	m.filterHighlights = make(map[*bookmark.Bookmark][]int, len(hits))
+	for i, hit := range hits {
+		bm := m.collection.Bookmarks[hit.Doc]
+		m.filteredBookmarks[i] = bm
+
+		var positions []int
+		for _, tok := range fulltext.Tokenize(bm.Title) {
+			if want[tok.Term] {
+				positions = appendRuneRange(positions, bm.Title, tok.Start, tok.End)
+			}
+		}
+		if len(positions) > 0 {
+			m.filterHighlights[bm] = positions
+		}
+	}
+}
+
+// buildFulltextIndex indexes the collection. An unmodified collection loaded
+// from a file reuses the index cached for that file's contents.
+func (m *Model) buildFulltextIndex() *fulltext.Index {
+	if m.dirty || m.mergeReport != nil || m.currentSession == nil || m.currentSession.CurrentFile == "" {
+		return fulltext.Build(m.collection.Bookmarks)
+	}
+	// Caching is best-effort; a failure still returns a usable index
+	ix, _ := fulltext.LoadOrBuild(filepath.Join(m.sessionMgr.ConfigDir(), "index"),
+		m.currentSession.CurrentFile, m.collection.Bookmarks)
+	return ix
+}
+
+// snippetPositions returns the rune positions of a snippet's highlights
+func snippetPositions(s fulltext.Snippet) []int {
+	var positions []int
+	for _, h := range s.Highlights {
+		positions = appendRuneRange(positions, s.Text, h[0], h[1])
+	}
+	return positions
+}
+
+// appendRuneRange appends the rune positions of text[start:end]
+func appendRuneRange(positions []int, text string, start, end int) []int {
+	first := utf8.RuneCountInString(text[:start])
+	for i := range utf8.RuneCountInString(text[start:end]) {
+		positions = append(positions, first+i)
+	}
+	return positions
+}
+
+// highlightTitle renders title with base, emphasising the runes at positions
+func highlightTitle(title string, positions []int, base lipgloss.Style) string {
+	if len(positions) == 0 {
+		return base.Render(title)
+	}
+
+	highlighted := make(map[int]bool, len(positions))
+	for _, p := range positions {
+		highlighted[p] = true
+	}
+
+	// Render runs of equally styled runes together
+	var s strings.Builder
+	var run []rune
+	runHighlighted := false
+	flush := func() {
+		if len(run) == 0 {
+			return
+		}
+		if runHighlighted {
+			s.WriteString(matchStyle.Inherit(base).Render(string(run)))
+		} else {
+			s.WriteString(base.Render(string(run)))
+		}
+		run = run[:0]
+	}
+
+	for i, r := range []rune(title) {
+		if highlighted[i] != runHighlighted {
+			flush()
+			runHighlighted = highlighted[i]
+		}
+		run = append(run, r)
+	}
+	flush()
+
+	return s.String()
+}
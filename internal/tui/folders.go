@@ -0,0 +1,245 @@
+package tui
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+
+	"github.com/lelopez-io/moxli/internal/bookmark"
+)
+
+// folderPaneWidth is the width of the folder pane in columns
+const folderPaneWidth = 34
+
+// folderRow is a node of the folder tree as listed in the pane
+type folderRow struct {
+	node  *bookmark.FolderNode
+	depth int
+}
+
+// pathKey identifies a folder or tag path in an expanded set
+func pathKey(path []string) string {
+	return strings.Join(path, "\x00")
+}
+
+// toggleFolderPane opens the folder pane with focus, or closes it
+func (m *Model) toggleFolderPane() {
+	if m.folderPane {
+		m.folderPane = false
+		m.folderFocus = false
+		m.folderTree = nil
+		return
+	}
+	m.folderPane = true
+	m.folderFocus = true
+	m.folderTree = bookmark.FolderTree(m.collection.Bookmarks)
+	m.folderExpanded[pathKey(nil)] = true
+}
+
+// resetFolderTree rebuilds the folder tree after the collection changed
+func (m *Model) resetFolderTree() {
+	m.folderTree = nil
+	if !m.folderPane || m.collection == nil {
+		return
+	}
+	m.folderTree = bookmark.FolderTree(m.collection.Bookmarks)
+	if rows := m.folderRows(); m.folderSelected >= len(rows) {
+		m.folderSelected = max(0, len(rows)-1)
+	}
+}
+
+// folderRows flattens the expanded part of the folder tree
+func (m Model) folderRows() []folderRow {
+	if m.folderTree == nil {
+		return nil
+	}
+	var rows []folderRow
+	var walk func(node *bookmark.FolderNode, depth int)
+	walk = func(node *bookmark.FolderNode, depth int) {
+		rows = append(rows, folderRow{node: node, depth: depth})
+		if m.folderExpanded[pathKey(node.Path)] {
+			for _, c := range node.Children {
+				walk(c, depth+1)
+			}
+		}
+	}
+	walk(m.folderTree, 0)
+	return rows
+}
+
+func (m Model) updateFolderPane(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	rows := m.folderRows()
+	if len(rows) == 0 {
+		return m, nil
+	}
+	m.folderSelected = min(m.folderSelected, len(rows)-1)
+	node := rows[m.folderSelected].node
+
+	switch msg.String() {
+	case "up", "k":
+		if m.folderSelected > 0 {
+			m.folderSelected--
+		}
+	case "down", "j":
+		if m.folderSelected < len(rows)-1 {
+			m.folderSelected++
+		}
+	case "right", "l":
+		if len(node.Children) > 0 {
+			m.folderExpanded[pathKey(node.Path)] = true
+		}
+	case "left", "h":
+		// Collapse, or jump to the parent of a collapsed folder
+		if m.folderExpanded[pathKey(node.Path)] && len(node.Children) > 0 && len(node.Path) > 0 {
+			delete(m.folderExpanded, pathKey(node.Path))
+		} else if len(node.Path) > 0 {
+			parent := node.Path[:len(node.Path)-1]
+			for i, row := range rows {
+				if slices.Equal(row.node.Path, parent) {
+					m.folderSelected = i
+					break
+				}
+			}
+		}
+	case "enter":
+		// Show the folder's subtree in the list
+		m.setFolderFilter(node.Path)
+		m.folderFocus = false
+	case "m":
+		m.moveToFolder(node.Path)
+	case "tab":
+		m.folderFocus = false
+	case "esc", "f":
+		m.toggleFolderPane()
+	}
+
+	// Keep the selection inside the visible window
+	shown := m.folderPaneRows(m.pageSize() * m.itemHeight())
+	if m.folderSelected < m.folderOffset {
+		m.folderOffset = m.folderSelected
+	} else if m.folderSelected >= m.folderOffset+shown {
+		m.folderOffset = m.folderSelected - shown + 1
+	}
+	return m, nil
+}
+
+// setFolderFilter limits the browser list to a folder and its subfolders.
+// The empty path shows every folder again.
+func (m *Model) setFolderFilter(path []string) {
+	m.folderFilter = nil
+	if len(path) > 0 {
+		m.folderFilter = slices.Clone(path)
+	}
+	m.applyFilter()
+}
+
+// filterFolder returns the bookmarks inside the folder filter
+func (m Model) filterFolder(bookmarks []*bookmark.Bookmark) []*bookmark.Bookmark {
+	filtered := make([]*bookmark.Bookmark, 0, len(bookmarks))
+	for _, bm := range bookmarks {
+		if bm.InFolder(m.folderFilter) {
+			filtered = append(filtered, bm)
+		}
+	}
+	return filtered
+}
+
+// moveToFolder moves the marked bookmarks, or the one under the cursor when
+// none are marked, into the folder at path
+func (m *Model) moveToFolder(path []string) {
+	targets := m.markedBookmarks()
+	if len(targets) == 0 {
+		if bm := m.selectedBookmark(); bm != nil {
+			targets = []*bookmark.Bookmark{bm}
+		}
+	}
+
+	label := "Moved to " + folderName(path)
+	m.changeBookmarks(label, targets, func(bm *bookmark.Bookmark) bool {
+		if slices.Equal(bm.Folder, path) {
+			return false
+		}
+		bm.Folder = slices.Clone(path)
+		return true
+	})
+}
+
+// folderName returns a folder path for display
+func folderName(path []string) string {
+	if len(path) == 0 {
+		return "top level"
+	}
+	return bookmark.FormatFolder(path)
+}
+
+// folderPaneRows returns how many folders fit in a pane of the given height,
+// below the heading and above the pane's key help
+func (m Model) folderPaneRows(height int) int {
+	rows := height - 2
+	if m.folderFocus {
+		rows -= 4
+	}
+	return max(1, rows)
+}
+
+// folderPaneView renders the folder tree shown beside the browser list
+func (m Model) folderPaneView(height int) string {
+	var s strings.Builder
+
+	heading := "Folders"
+	if m.folderFocus {
+		heading = selectedItemStyle.Render(heading)
+	}
+	s.WriteString(" " + heading + "\n\n")
+
+	rows := m.folderRows()
+	end := min(m.folderOffset+m.folderPaneRows(height), len(rows))
+	for i := m.folderOffset; i < end; i++ {
+		node, depth := rows[i].node, rows[i].depth
+
+		arrow := "  "
+		if len(node.Children) > 0 {
+			arrow = "▸ "
+			if m.folderExpanded[pathKey(node.Path)] {
+				arrow = "▾ "
+			}
+		}
+		name := node.Name
+		if len(node.Path) == 0 {
+			name = "All bookmarks"
+		}
+		count := fmt.Sprintf(" (%d)", node.Total)
+		prefix := strings.Repeat("  ", depth) + arrow
+		room := folderPaneWidth - 3 - lipgloss.Width(prefix+count)
+		label := prefix + ansi.Truncate(name, max(room, 1), "…")
+
+		var line string
+		switch {
+		case i == m.folderSelected && m.folderFocus:
+			line = "▶ " + selectedItemStyle.Render(label)
+		case slices.Equal(node.Path, m.folderFilter):
+			// The folder the list is showing
+			line = markStyle.Render("• ") + label
+		default:
+			line = "  " + label
+		}
+		line += urlStyle.Render(count)
+		s.WriteString(line + "\n")
+	}
+
+	if m.folderFocus {
+		s.WriteString(renderKeybindings("l/h: expand/collapse\nenter: show  m: move here\ntab: list  esc: close"))
+	}
+
+	return lipgloss.NewStyle().
+		Width(folderPaneWidth).
+		Height(height).
+		BorderStyle(lipgloss.NormalBorder()).
+		BorderRight(true).
+		BorderForeground(theme.Accent).
+		Render(s.String())
+}
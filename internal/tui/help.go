@@ -0,0 +1,69 @@
+package tui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/lelopez-io/moxli/internal/config"
+)
+
+// helpScope returns the key scope of the current view, or "" when the view
+// has no rebindable keys to list
+func (m Model) helpScope() string {
+	switch m.currentView {
+	case BrowserView:
+		if m.collection != nil {
+			return "browser"
+		}
+	case DetailView:
+		return "detail"
+	}
+	return ""
+}
+
+// quitKey returns the key that quits, for help text
+func (m Model) quitKey() string {
+	if m.keys.Global.Quit.Enabled() {
+		return m.keys.Global.Quit.Help().Key
+	}
+	return "ctrl+c"
+}
+
+// openHelp shows every key of the current view
+func (m *Model) openHelp() {
+	m.previousView = m.currentView
+	m.currentView = HelpView
+}
+
+func (m Model) updateHelp(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if msg.String() == "esc" || key.Matches(msg, m.keys.Global.Help) {
+		m.currentView = m.previousView
+	}
+	return m, nil
+}
+
+func (m Model) helpView() string {
+	var s strings.Builder
+
+	title := "⌨️  Browser Keys"
+	scope := "browser"
+	if m.previousView == DetailView {
+		title = "⌨️  Detail Keys"
+		scope = "detail"
+	}
+
+	s.WriteString("\n")
+	s.WriteString(headerStyle.Render(title))
+	s.WriteString("\n")
+	s.WriteString(renderKeybindings(renderHelp(m.keys.helpGroups(scope), m.width)))
+	s.WriteString("\n")
+	closeKeys := "esc"
+	if m.keys.Global.Help.Enabled() {
+		closeKeys += "/" + m.keys.Global.Help.Help().Key
+	}
+	s.WriteString(renderKeybindings(closeKeys + ": close  (rebind keys in ~/.moxli/" + config.FileName + ")"))
+
+	return s.String()
+}
@@ -0,0 +1,77 @@
+package tui
+
+import (
+	"fmt"
+
+	"github.com/lelopez-io/moxli/internal/bookmark"
+)
+
+// loadHistory restores the edit history saved for file, starting fresh when
+// there is none or the file changed since it was saved
+func (m *Model) loadHistory(file string) {
+	m.collection.EnsureIDs()
+	m.history = bookmark.NewHistory()
+
+	history, err := m.sessionMgr.LoadHistory(file)
+	if err != nil {
+		m.err = fmt.Errorf("failed to load edit history: %w", err)
+		return
+	}
+	m.history = history
+}
+
+// saveHistory persists the edit history next to a freshly written file
+func (m *Model) saveHistory(file string) {
+	if err := m.sessionMgr.SaveHistory(file, m.history); err != nil {
+		m.err = fmt.Errorf("saved but failed to store edit history: %w", err)
+	}
+}
+
+// doAndRecord applies ops to the collection as one undoable action
+func (m *Model) doAndRecord(label string, ops []bookmark.Op) error {
+	if err := m.history.Do(m.collection, label, ops); err != nil {
+		return err
+	}
+	m.refreshAfterCollectionChange()
+	return nil
+}
+
+// undo reverts the most recent recorded action
+func (m *Model) undo() {
+	entry, err := m.history.Undo(m.collection)
+	if err != nil {
+		m.statusMsg = err.Error()
+		return
+	}
+	m.pruneMarks()
+	m.refreshAfterCollectionChange()
+	m.statusMsg = "Undid: " + entry.Label
+}
+
+// redo re-applies the most recently undone action
+func (m *Model) redo() {
+	entry, err := m.history.Redo(m.collection)
+	if err != nil {
+		m.statusMsg = err.Error()
+		return
+	}
+	m.pruneMarks()
+	m.refreshAfterCollectionChange()
+	m.statusMsg = "Redid: " + entry.Label
+}
+
+// pruneMarks drops marks on bookmarks that are no longer in the collection
+func (m *Model) pruneMarks() {
+	if len(m.marked) == 0 {
+		return
+	}
+	present := make(map[*bookmark.Bookmark]bool, len(m.collection.Bookmarks))
+	for _, bm := range m.collection.Bookmarks {
+		present[bm] = true
+	}
+	for bm := range m.marked {
+		if !present[bm] {
+			delete(m.marked, bm)
+		}
+	}
+}
@@ -0,0 +1,419 @@
+package tui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+
+	"github.com/lelopez-io/moxli/internal/config"
+)
+
+// keyMap holds the rebindable keys of each scope. Global keys work in every
+// view except while typing; ctrl+c always quits.
+type keyMap struct {
+	Global  globalKeys
+	Browser browserKeys
+	Detail  detailKeys
+}
+
+type globalKeys struct {
+	Quit key.Binding
+	Help key.Binding
+}
+
+type browserKeys struct {
+	Down         key.Binding
+	Up           key.Binding
+	HalfPageDown key.Binding
+	HalfPageUp   key.Binding
+	PageDown     key.Binding
+	PageUp       key.Binding
+	Bottom       key.Binding
+	Top          key.Binding
+
+	Mark       key.Binding
+	MarkAll    key.Binding
+	Invert     key.Binding
+	ClearMarks key.Binding
+	Bulk       key.Binding
+
+	Open       key.Binding
+	OpenMarked key.Binding
+	CopyURL    key.Binding
+	CopyTitle  key.Binding
+	CopyLink   key.Binding
+
+	Preview      key.Binding
+	Filter       key.Binding
+	Sort         key.Binding
+	Folders      key.Binding
+	FocusFolders key.Binding
+	Tags         key.Binding
+	Duplicates   key.Binding
+	Candidates   key.Binding
+	Undo         key.Binding
+	Redo         key.Binding
+	Save         key.Binding
+	Export       key.Binding
+}
+
+type detailKeys struct {
+	Close     key.Binding
+	Open      key.Binding
+	CopyURL   key.Binding
+	CopyTitle key.Binding
+	CopyLink  key.Binding
+	Edit      key.Binding
+	Save      key.Binding
+}
+
+// binding creates a key binding whose help shows helpKey
+func binding(helpKey, desc string, keys ...string) key.Binding {
+	return key.NewBinding(key.WithKeys(keys...), key.WithHelp(helpKey, desc))
+}
+
+// defaultKeyMap returns the built-in bindings
+func defaultKeyMap() *keyMap {
+	return &keyMap{
+		Global: globalKeys{
+			Quit: binding("q", "quit", "q"),
+			Help: binding("?", "all keys", "?"),
+		},
+		Browser: browserKeys{
+			Down:         binding("j", "down", "down", "j"),
+			Up:           binding("k", "up", "up", "k"),
+			HalfPageDown: binding("J", "half-page down", "J"),
+			HalfPageUp:   binding("K", "half-page up", "K"),
+			PageDown:     binding("H", "page down", "H"),
+			PageUp:       binding("h", "page up", "h"),
+			Bottom:       binding("G", "bottom", "G"),
+			Top:          binding("g", "top", "g"),
+
+			Mark:       binding("m", "mark/unmark", "m"),
+			MarkAll:    binding("*", "mark all shown", "*"),
+			Invert:     binding("~", "invert", "~"),
+			ClearMarks: binding("M", "clear", "M"),
+			Bulk:       binding("b", "bulk actions", "b"),
+
+			Open:       binding("o", "open", "o"),
+			OpenMarked: binding("O", "open marked", "O"),
+			CopyURL:    binding("y", "copy URL", "y"),
+			CopyTitle:  binding("Y", "copy title", "Y"),
+			CopyLink:   binding("L", "copy Markdown link", "L"),
+
+			Preview:      binding("space", "preview", " "),
+			Filter:       binding("/", "filter", "/"),
+			Sort:         binding("s", "sort", "s"),
+			Folders:      binding("f", "folders", "f"),
+			FocusFolders: binding("tab", "folder pane", "tab"),
+			Tags:         binding("t", "tags", "t"),
+			Duplicates:   binding("d", "duplicates", "d"),
+			Candidates:   binding("c", "merge candidates", "c"),
+			Undo:         binding("u", "undo", "u"),
+			Redo:         binding("ctrl+r", "redo", "ctrl+r"),
+			Save:         binding("ctrl+s", "save", "ctrl+s"),
+			Export:       binding("w", "export", "w"),
+		},
+		Detail: detailKeys{
+			Close:     binding("space", "close preview", " "),
+			Open:      binding("enter/o", "open in browser", "enter", "o"),
+			CopyURL:   binding("y", "copy URL", "y"),
+			CopyTitle: binding("Y", "copy title", "Y"),
+			CopyLink:  binding("L", "copy Markdown link", "L"),
+			Edit:      binding("e", "edit", "e"),
+			Save:      binding("ctrl+s", "save", "ctrl+s"),
+		},
+	}
+}
+
+// keyAction is a binding with its name in the config file
+type keyAction struct {
+	name    string
+	binding *key.Binding
+}
+
+// keyGroup is a section of the key help
+type keyGroup struct {
+	title   string // Empty for the summary lines that close the help
+	columns int    // Most entries per help line
+	actions []keyAction
+}
+
+// keyScope is the set of bindings active in one view
+type keyScope struct {
+	name   string
+	groups []keyGroup
+}
+
+// scopes lists every binding by scope, in help order
+func (k *keyMap) scopes() []keyScope {
+	return []keyScope{
+		{name: "global", groups: []keyGroup{
+			{actions: []keyAction{
+				{"help", &k.Global.Help},
+				{"quit", &k.Global.Quit},
+			}},
+		}},
+		{name: "browser", groups: []keyGroup{
+			{title: "Navigation", columns: 2, actions: []keyAction{
+				{"down", &k.Browser.Down},
+				{"up", &k.Browser.Up},
+				{"half-page-down", &k.Browser.HalfPageDown},
+				{"half-page-up", &k.Browser.HalfPageUp},
+				{"page-down", &k.Browser.PageDown},
+				{"page-up", &k.Browser.PageUp},
+				{"bottom", &k.Browser.Bottom},
+				{"top", &k.Browser.Top},
+			}},
+			{title: "Selection", columns: 4, actions: []keyAction{
+				{"mark", &k.Browser.Mark},
+				{"mark-all", &k.Browser.MarkAll},
+				{"invert", &k.Browser.Invert},
+				{"clear-marks", &k.Browser.ClearMarks},
+				{"bulk", &k.Browser.Bulk},
+			}},
+			{title: "Actions", columns: 3, actions: []keyAction{
+				{"open", &k.Browser.Open},
+				{"open-marked", &k.Browser.OpenMarked},
+				{"copy-url", &k.Browser.CopyURL},
+				{"copy-title", &k.Browser.CopyTitle},
+				{"copy-link", &k.Browser.CopyLink},
+			}},
+			{actions: []keyAction{
+				{"preview", &k.Browser.Preview},
+				{"filter", &k.Browser.Filter},
+				{"sort", &k.Browser.Sort},
+				{"folders", &k.Browser.Folders},
+				{"focus-folders", &k.Browser.FocusFolders},
+				{"tags", &k.Browser.Tags},
+				{"duplicates", &k.Browser.Duplicates},
+				{"candidates", &k.Browser.Candidates},
+				{"undo", &k.Browser.Undo},
+				{"redo", &k.Browser.Redo},
+				{"save", &k.Browser.Save},
+				{"export", &k.Browser.Export},
+			}},
+		}},
+		{name: "detail", groups: []keyGroup{
+			{actions: []keyAction{
+				{"close", &k.Detail.Close},
+				{"open", &k.Detail.Open},
+				{"copy-url", &k.Detail.CopyURL},
+				{"copy-title", &k.Detail.CopyTitle},
+				{"copy-link", &k.Detail.CopyLink},
+				{"edit", &k.Detail.Edit},
+				{"save", &k.Detail.Save},
+			}},
+		}},
+	}
+}
+
+// scope returns the scope with the given name
+func (k *keyMap) scope(name string) (keyScope, bool) {
+	for _, s := range k.scopes() {
+		if s.name == name {
+			return s, true
+		}
+	}
+	return keyScope{}, false
+}
+
+// newKeyMap returns the default bindings with the config file's overrides
+// applied, rejecting unknown actions and keys bound twice in one view
+func newKeyMap(overrides map[string]map[string]config.KeyList) (*keyMap, error) {
+	k := defaultKeyMap()
+
+	for scopeName, actions := range overrides {
+		scope, ok := k.scope(scopeName)
+		if !ok {
+			return nil, fmt.Errorf("unknown key scope %q (want global, browser or detail)", scopeName)
+		}
+		for name, keys := range actions {
+			action, ok := scope.action(name)
+			if !ok {
+				return nil, fmt.Errorf("unknown action %q in key scope %s (want one of %s)",
+					name, scopeName, strings.Join(keyNames(scope), ", "))
+			}
+			rebind(action.binding, keys)
+		}
+	}
+
+	if err := k.checkConflicts(); err != nil {
+		return nil, err
+	}
+	return k, nil
+}
+
+// action returns the scope's action with the given name
+func (s keyScope) action(name string) (keyAction, bool) {
+	for _, g := range s.groups {
+		for _, a := range g.actions {
+			if a.name == name {
+				return a, true
+			}
+		}
+	}
+	return keyAction{}, false
+}
+
+// rebind replaces a binding's keys, unbinding it when keys is empty
+func rebind(b *key.Binding, keys []string) {
+	if len(keys) == 0 {
+		b.Unbind()
+		return
+	}
+
+	bound := make([]string, len(keys))
+	shown := make([]string, len(keys))
+	for i, k := range keys {
+		bound[i] = k
+		if k == "space" {
+			bound[i] = " "
+		}
+		shown[i] = keyName(bound[i])
+	}
+	b.SetKeys(bound...)
+	b.SetHelp(strings.Join(shown, "/"), b.Help().Desc)
+}
+
+// keyName returns how a key is written in help and error messages
+func keyName(k string) string {
+	if k == " " {
+		return "space"
+	}
+	return k
+}
+
+// checkConflicts reports a key bound to two actions of one scope, or to an
+// action of a view and a global action, since global keys are checked first
+func (k *keyMap) checkConflicts() error {
+	scopes := k.scopes()
+	global := scopes[0]
+
+	for _, scope := range scopes {
+		owners := make(map[string]string)
+		check := func(s keyScope) error {
+			for _, g := range s.groups {
+				for _, a := range g.actions {
+					for _, key := range a.binding.Keys() {
+						name := s.name + "." + a.name
+						if owner, ok := owners[key]; ok && owner != name {
+							return fmt.Errorf("key %q is bound to both %s and %s", keyName(key), owner, name)
+						}
+						owners[key] = name
+					}
+				}
+			}
+			return nil
+		}
+
+		if scope.name != global.name {
+			if err := check(global); err != nil {
+				return err
+			}
+		}
+		if err := check(scope); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// renderHelp lays out the help for key groups within width columns (0 when
+// unknown). Titled groups get a heading and aligned columns; untitled ones
+// flow together into the closing summary lines.
+func renderHelp(groups []keyGroup, width int) string {
+	if width <= 0 {
+		width = 80
+	}
+	width -= 2 // Help indent
+
+	var lines []string
+	var summary []string
+	for _, g := range groups {
+		entries := helpEntries(g.actions)
+		if len(entries) == 0 {
+			continue
+		}
+		if g.title == "" {
+			summary = append(summary, entries...)
+			continue
+		}
+
+		columnWidth := 0
+		for _, e := range entries {
+			columnWidth = max(columnWidth, len([]rune(e))+2)
+		}
+		columns := max(1, min(g.columns, (width-2)/columnWidth))
+
+		lines = append(lines, g.title+":")
+		for i := 0; i < len(entries); i += columns {
+			row := entries[i:min(i+columns, len(entries))]
+			var line strings.Builder
+			for j, e := range row {
+				if j < len(row)-1 {
+					e += strings.Repeat(" ", columnWidth-len([]rune(e)))
+				}
+				line.WriteString(e)
+			}
+			lines = append(lines, "  "+line.String())
+		}
+		lines = append(lines, "")
+	}
+
+	// Flow the summary entries onto as few lines as fit
+	line := ""
+	for _, e := range summary {
+		switch {
+		case line == "":
+			line = e
+		case len([]rune(line))+2+len([]rune(e)) <= width:
+			line += "  " + e
+		default:
+			lines = append(lines, line)
+			line = e
+		}
+	}
+	if line != "" {
+		lines = append(lines, line)
+	}
+
+	return strings.TrimRight(strings.Join(lines, "\n"), "\n")
+}
+
+// helpEntries formats the enabled actions as "key: description"
+func helpEntries(actions []keyAction) []string {
+	entries := make([]string, 0, len(actions))
+	for _, a := range actions {
+		if a.binding.Enabled() {
+			help := a.binding.Help()
+			entries = append(entries, help.Key+": "+help.Desc)
+		}
+	}
+	return entries
+}
+
+// helpGroups returns the help for a view: its scope's groups followed by
+// the global keys
+func (k *keyMap) helpGroups(scopeName string) []keyGroup {
+	var groups []keyGroup
+	if s, ok := k.scope(scopeName); ok {
+		groups = append(groups, s.groups...)
+	}
+	global, _ := k.scope("global")
+	return append(groups, global.groups...)
+}
+
+// keyNames returns the names of a scope's actions, sorted, for error messages
+func keyNames(s keyScope) []string {
+	var names []string
+	for _, g := range s.groups {
+		for _, a := range g.actions {
+			names = append(names, a.name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,196 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+
+	"github.com/lelopez-io/moxli/internal/bookmark"
+)
+
+const (
+	// defaultPageSize is used until the terminal reports its size
+	defaultPageSize = 10
+	// minFullHelpItems is the fewest list items worth keeping the full key
+	// help on screen for; shorter terminals get a one-line summary
+	minFullHelpItems = 5
+	// splitMinWidth is the terminal width from which the browser shows a
+	// detail pane beside the list
+	splitMinWidth = 120
+)
+
+// browserLayout describes how the browser fills the terminal
+type browserLayout struct {
+	top         string // Header, stats and any open input above the list
+	help        string // Key help below the list
+	pageSize    int    // Bookmarks listed at once
+	listWidth   int    // Columns for the list, 0 when the width is unknown
+	detailWidth int    // Columns for the detail pane, 0 when not split
+}
+
+// layout sizes the browser to the terminal
+func (m Model) layout() browserLayout {
+	l := browserLayout{top: m.browserTop(), help: m.browserHelp(false), pageSize: defaultPageSize}
+
+	if m.width > 0 {
+		l.listWidth = m.width
+		if m.folderPane {
+			l.listWidth -= folderPaneWidth + 2 // Border and gap
+		}
+		if m.width >= splitMinWidth {
+			l.detailWidth = l.listWidth * 2 / 5
+			l.listWidth -= l.detailWidth
+		}
+	}
+
+	if m.height > 0 {
+		available := func(help string) int {
+			return m.height - strings.Count(l.top, "\n") - lipgloss.Height(help)
+		}
+		if available(l.help)/m.itemHeight() < minFullHelpItems {
+			l.help = m.browserHelp(true)
+		}
+		l.pageSize = max(1, available(l.help)/m.itemHeight())
+	}
+
+	return l
+}
+
+// pageSize returns how many bookmarks the browser lists at once
+func (m Model) pageSize() int {
+	return m.layout().pageSize
+}
+
+// itemHeight returns the lines taken by one bookmark in the list
+func (m Model) itemHeight() int {
+	if m.fulltextTerms != nil {
+		return 4 // Title, URL, snippet and a blank line
+	}
+	return 3
+}
+
+// browserTop renders everything above the bookmark list
+func (m Model) browserTop() string {
+	var s strings.Builder
+
+	// Header
+	s.WriteString("\n")
+	s.WriteString(headerStyle.Render("📖 Bookmark Browser" + m.dirtyMarker()))
+	s.WriteString("\n\n")
+
+	// Stats
+	bookmarks := m.visibleBookmarks()
+	stats := fmt.Sprintf("Total: %d bookmarks │ Selected: %d/%d",
+		len(bookmarks), m.browserSelected+1, len(bookmarks))
+	if m.filteredBookmarks != nil {
+		stats = fmt.Sprintf("Filtered: %d/%d bookmarks │ Selected: %d/%d",
+			len(m.filteredBookmarks), len(m.collection.Bookmarks), m.browserSelected+1, len(bookmarks))
+	}
+	if len(m.marked) > 0 {
+		stats += fmt.Sprintf(" │ Marked: %d", len(m.marked))
+	}
+	if m.sortMode != bookmark.SortFileOrder {
+		stats += " │ Sort: " + m.sortMode.String()
+	}
+	if m.folderFilter != nil {
+		stats += " │ Folder: " + bookmark.FormatFolder(m.folderFilter)
+	}
+	s.WriteString("  " + statStyle.Render(stats) + "\n")
+	if m.mergeReport != nil {
+		mergeStats := fmt.Sprintf("Merge: %d enhanced │ %d unwrapped │ %d fuzzy applied │ %d candidates to review",
+			m.mergeReport.Enhanced, m.mergeReport.Unwrapped, len(m.mergeReport.Applied), len(m.mergeReport.Candidates))
+		if len(m.mergeReport.Candidates) > 0 {
+			mergeStats += " (" + m.keys.Browser.Candidates.Help().Key + ")"
+		}
+		s.WriteString("  " + urlStyle.Render(mergeStats) + "\n")
+	}
+	s.WriteString(m.statusLine())
+
+	// Filter input (if active)
+	if m.filterMode {
+		s.WriteString("\n  " + m.filterInput.View() + "\n")
+		s.WriteString(renderKeybindings("enter: apply  esc: cancel"))
+		s.WriteString("\n")
+	}
+	if m.bulkMenu {
+		s.WriteString(m.bulkMenuView())
+		s.WriteString("\n")
+	}
+	s.WriteString("\n")
+
+	return s.String()
+}
+
+// browserHelp renders the key help below the list, or a one-line summary
+// when compact
+func (m Model) browserHelp(compact bool) string {
+	if !compact {
+		return renderKeybindings(renderHelp(m.keys.helpGroups("browser"), m.width))
+	}
+
+	keys := m.keys.Browser
+	var summary []string
+	if keys.Down.Enabled() && keys.Up.Enabled() {
+		summary = append(summary, keys.Down.Help().Key+"/"+keys.Up.Help().Key+": move")
+	}
+	summary = append(summary, helpEntries([]keyAction{
+		{binding: &keys.Preview},
+		{binding: &keys.Filter},
+		{binding: &keys.Mark},
+		{binding: &keys.Open},
+		{binding: &m.keys.Global.Help},
+		{binding: &m.keys.Global.Quit},
+	})...)
+	// Help is indented by two columns
+	return renderKeybindings(truncate(strings.Join(summary, "  "), m.width-2))
+}
+
+// detailPane renders the selected bookmark's details beside the list
+func (m Model) detailPane(width, height int) string {
+	content := "No bookmark selected"
+	if bm := m.selectedBookmark(); bm != nil {
+		content = bookmarkDetails(bm)
+	}
+
+	// Wrap to the pane, then drop whatever does not fit
+	content = lipgloss.NewStyle().Width(width - 3).Render(content)
+	if lines := strings.Split(content, "\n"); len(lines) > height {
+		content = strings.Join(lines[:height], "\n")
+	}
+
+	return lipgloss.NewStyle().
+		Width(width - 1).
+		PaddingLeft(1).
+		BorderStyle(lipgloss.NormalBorder()).
+		BorderLeft(true).
+		BorderForeground(theme.Accent).
+		Render(content)
+}
+
+// truncate shortens s to width terminal columns, ending it with "…".
+// A width of 0 or less means the width is unknown and s is kept whole.
+func truncate(s string, width int) string {
+	if width <= 0 {
+		return s
+	}
+	return ansi.Truncate(s, width, "…")
+}
+
+// truncateHighlighted truncates s like truncate, dropping highlight
+// positions that were cut off or replaced by the ellipsis
+func truncateHighlighted(s string, positions []int, width int) (string, []int) {
+	t := truncate(s, width)
+	if t == s {
+		return s, positions
+	}
+	limit := len([]rune(t)) - 1
+	kept := make([]int, 0, len(positions))
+	for _, p := range positions {
+		if p < limit {
+			kept = append(kept, p)
+		}
+	}
+	return t, kept
+}
@@ -3,46 +3,44 @@ package tui
 import (
 	"fmt"
 	"os"
-	"os/exec"
+	"strconv"
 	"strings"
 
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/lelopez-io/moxli/internal/bookmark"
+	"github.com/lelopez-io/moxli/internal/config"
+	"github.com/lelopez-io/moxli/internal/exporter"
+	"github.com/lelopez-io/moxli/internal/fulltext"
+	"github.com/lelopez-io/moxli/internal/fuzzy"
 	"github.com/lelopez-io/moxli/internal/importer"
 	"github.com/lelopez-io/moxli/internal/merge"
+	"github.com/lelopez-io/moxli/internal/opener"
 	"github.com/lelopez-io/moxli/internal/session"
 )
 
-// Lipgloss styles
+// Lipgloss styles, built from the theme's palette by applyTheme
 var (
-	titleStyle = lipgloss.NewStyle().
-			Bold(true).
-			Foreground(lipgloss.Color("205")).
-			Padding(0, 1)
-
-	headerStyle = lipgloss.NewStyle().
-			BorderStyle(lipgloss.RoundedBorder()).
-			BorderForeground(lipgloss.Color("63")).
-			Padding(0, 1)
-
-	selectedItemStyle = lipgloss.NewStyle().
-				Foreground(lipgloss.Color("170")).
-				Bold(true)
-
-	urlStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("241")).
-			Italic(true)
-
-	helpStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("241")).
-			Padding(1, 0, 0, 2)
-
-	statStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("63"))
+	titleStyle        lipgloss.Style
+	headerStyle       lipgloss.Style
+	selectedItemStyle lipgloss.Style
+	urlStyle          lipgloss.Style
+	helpStyle         lipgloss.Style
+	statStyle         lipgloss.Style
+	matchStyle        lipgloss.Style // Search matches
+	markStyle         lipgloss.Style // Marked bookmarks
+	snippetStyle      lipgloss.Style // Full-text snippets
+	labelStyle        lipgloss.Style // Field labels
+	tagStyle          lipgloss.Style // Tags in details and forms
+	orphanStyle       lipgloss.Style // Orphan and single-use tags
 )
 
+func init() {
+	applyTheme(theme)
+}
+
 // View represents the different screens in the TUI
 type View int
 
@@ -51,6 +49,11 @@ const (
 	FileSelectionView
 	BrowserView
 	DetailView
+	DuplicatesView
+	ExportView
+	TagsView
+	CandidatesView
+	HelpView
 )
 
 // welcomeChoice represents the user's selection on the welcome screen
@@ -72,7 +75,8 @@ const (
 // Model is the main application state
 type Model struct {
 	// Current view
-	currentView View
+	currentView  View
+	previousView View // View to return to when closing a dialog
 
 	// Session management
 	sessionMgr     *session.Manager
@@ -87,18 +91,82 @@ type Model struct {
 	pathInput         textinput.Model
 	fileDiscovery     *FileDiscovery
 	fileSelectedIdx   int
+	fuzzyMerge        bool // Run the fuzzy matching pass when merging
 
 	// Browser state
-	collection       *bookmark.Collection
-	browserOffset    int // Scroll offset for browser list
-	browserSelected  int // Currently selected bookmark index
-	filterMode       bool
-	filterInput      textinput.Model
+	collection        *bookmark.Collection
+	browserOffset     int // Scroll offset for browser list
+	browserSelected   int // Currently selected bookmark index
+	filterMode        bool
+	filterInput       textinput.Model
 	filteredBookmarks []*bookmark.Bookmark
+	sortMode          bookmark.SortMode
+	sortedBookmarks   []*bookmark.Bookmark         // Collection in sortMode order, nil for file order
+	filterHighlights  map[*bookmark.Bookmark][]int // Fuzzy-matched title positions
+	fuzzyIndex        *fuzzy.Index                 // Built on first fuzzy search, reset on changes
+	fulltextIndex     *fulltext.Index              // Built or loaded on first "?" search, reset on changes
+	fulltextTerms     []string                     // Terms of the active full-text search, for snippets
+	mergeReport       *merge.Report                // Summary of the merge that produced the collection
+
+	// Folder pane state
+	folderPane     bool                 // Pane is shown beside the list
+	folderFocus    bool                 // Keys go to the pane instead of the list
+	folderTree     *bookmark.FolderNode // Built when the pane opens, rebuilt on changes
+	folderExpanded map[string]bool      // Expanded nodes by pathKey
+	folderSelected int
+	folderOffset   int
+	folderFilter   []string // Folder the list is limited to, nil for all
+
+	// Duplicate review state
+	dupGroups     []*bookmark.DuplicateGroup // Groups that need manual review
+	dupAutoGroups []*bookmark.DuplicateGroup // Exact-URL groups that can be merged directly
+	dupGroupIdx   int
+	dupMemberIdx  int
+	dupStatus     string
+
+	// Edit state (form shown on top of the detail view)
+	editing     bool
+	editInputs  []editInput
+	editFocus   editField
+	editStarred bool
+	dirty       bool   // Collection has unsaved changes
+	statusMsg   string // Transient feedback shown below the stats line
+	quitPending bool   // Quit was pressed once with unsaved changes
+
+	// Edit history for undo/redo, persisted per collection file on save
+	history *bookmark.History
+
+	// Multi-select state
+	marked     map[*bookmark.Bookmark]bool
+	bulkMenu   bool       // Bulk action menu is open
+	bulkAction bulkAction // Action waiting for text input
+	bulkInput  textinput.Model
+
+	// Tags view state
+	tagTree     *bookmark.TagNode
+	tagExpanded map[string]bool // Expanded tags by pathKey
+	tagFlat     bool            // Frequency-sorted list instead of the tree
+	tagSelected int
+	tagOffset   int
+
+	// Merge candidates view state
+	candidateSelected int
+	candidateOffset   int
+
+	// Export dialog state
+	exportTarget    *bookmark.Collection // Whole collection or the marked selection
+	exportFormats   []exporter.Format
+	exportFormatIdx int
+	exportFocus     exportFocus
+	exportPathInput textinput.Model
+	exportErrors    []exporter.ValidationError
+	exportConfirm   bool // Output exists and the next enter overwrites it
 
 	// Application state
 	width  int
 	height int
+	opener *opener.Opener // Opens URLs in the browser
+	keys   *keyMap        // Key bindings, defaults with the config file's overrides
 
 	// Error state
 	err error
@@ -111,6 +179,20 @@ func NewModel() (*Model, error) {
 		return nil, err
 	}
 
+	cfg, err := config.Load(sessionMgr.ConfigDir())
+	if err != nil {
+		return nil, err
+	}
+	keys, err := newKeyMap(cfg.Keys)
+	if err != nil {
+		return nil, fmt.Errorf("invalid keys in %s: %w", config.FileName, err)
+	}
+	palette, err := newPalette(cfg.Theme)
+	if err != nil {
+		return nil, fmt.Errorf("invalid theme in %s: %w", config.FileName, err)
+	}
+	applyTheme(palette)
+
 	// Check if a previous session exists
 	hasSession := sessionMgr.Exists()
 	var currentSession *session.Session
@@ -138,8 +220,8 @@ func NewModel() (*Model, error) {
 
 	// Initialize filter input
 	filterTI := textinput.New()
-	filterTI.Placeholder = "Search bookmarks..."
-	filterTI.CharLimit = 100
+	filterTI.Placeholder = "Search bookmarks... (tag:, folder:, domain:, is:starred, -term, OR, ?full text)"
+	filterTI.CharLimit = 256
 	filterTI.Width = 60
 
 	model := &Model{
@@ -150,6 +232,12 @@ func NewModel() (*Model, error) {
 		fileSelectionMode: inputMode,
 		pathInput:         ti,
 		filterInput:       filterTI,
+		marked:            make(map[*bookmark.Bookmark]bool),
+		history:           bookmark.NewHistory(),
+		folderExpanded:    make(map[string]bool),
+		tagExpanded:       make(map[string]bool),
+		opener:            opener.New(cfg.Opener),
+		keys:              keys,
 		fileDiscovery:     NewFileDiscovery(),
 		fileSelectedIdx:   0,
 	}
@@ -181,15 +269,35 @@ func (m Model) Init() tea.Cmd {
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
-		// Global quit only with Ctrl+C
-		// 'q' is handled per-view to allow different behaviors
-		switch msg.String() {
-		case "ctrl+c":
-			return m, tea.Quit
-		case "q":
-			// q always quits from any view
+		// ctrl+c always quits; the other global keys are ignored while
+		// typing so they can be entered as text
+		if msg.String() == "ctrl+c" {
 			return m, tea.Quit
 		}
+		if !m.typing() {
+			switch {
+			case key.Matches(msg, m.keys.Global.Quit):
+				// Unsaved changes take a second press to throw away
+				if m.dirty && !m.quitPending {
+					m.quitPending = true
+					return m, nil
+				}
+				return m, tea.Quit
+			case key.Matches(msg, m.keys.Global.Help) && m.helpScope() != "":
+				m.openHelp()
+				return m, nil
+			}
+		}
+
+		// Any other key keeps the app open
+		m.quitPending = false
+
+		// Feedback from the previous action is cleared on the next key press
+		// (every view from the browser onward works on a loaded collection)
+		if m.currentView >= BrowserView {
+			m.statusMsg = ""
+			m.err = nil
+		}
 
 		// Handle view-specific key presses
 		switch m.currentView {
@@ -201,16 +309,39 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m.updateBrowser(msg)
 		case DetailView:
 			return m.updateDetail(msg)
+		case DuplicatesView:
+			return m.updateDuplicates(msg)
+		case ExportView:
+			return m.updateExport(msg)
+		case TagsView:
+			return m.updateTags(msg)
+		case CandidatesView:
+			return m.updateCandidates(msg)
+		case HelpView:
+			return m.updateHelp(msg)
 		}
 
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Height
+		// Indent, prompt and cursor take the other columns
+		m.filterInput.Width = max(10, min(60, msg.Width-6))
+
+	case openedMsg:
+		m.handleOpened(msg)
 	}
 
 	return m, nil
 }
 
+// typing reports whether a text input has focus, so single-letter keys are input
+func (m Model) typing() bool {
+	return m.editing ||
+		(m.currentView == FileSelectionView && m.fileSelectionMode == inputMode) ||
+		(m.currentView == BrowserView && (m.filterMode || m.bulkAction != bulkNone)) ||
+		(m.currentView == ExportView && m.exportFocus == exportFocusPath)
+}
+
 func (m Model) updateWelcome(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	switch msg.String() {
 	case "up", "k":
@@ -321,6 +452,9 @@ func (m Model) updateFileSelectionList(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		}
 		files[m.fileSelectedIdx].IsBase = true
 		files[m.fileSelectedIdx].Selected = true
+	case "f":
+		// Toggle the fuzzy matching pass for URLs the base doesn't know
+		m.fuzzyMerge = !m.fuzzyMerge
 	case "enter":
 		// Confirm selection and proceed
 		// Validate that we have at least one base and one source
@@ -372,6 +506,10 @@ func (m Model) updateBrowser(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m, nil
 	}
 
+	if m.bulkMenu {
+		return m.updateBulk(msg)
+	}
+
 	// Handle filter mode
 	if m.filterMode {
 		switch msg.String() {
@@ -380,9 +518,7 @@ func (m Model) updateBrowser(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			m.filterMode = false
 			m.filterInput.Blur()
 			m.filterInput.SetValue("")
-			m.filteredBookmarks = nil
-			m.browserSelected = 0
-			m.browserOffset = 0
+			m.liveFilter()
 			return m, nil
 		case "enter":
 			// Apply filter
@@ -391,30 +527,35 @@ func (m Model) updateBrowser(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			m.applyFilter()
 			return m, nil
 		default:
-			// Update filter input
+			// Update filter input, refreshing results as the query changes
+			previous := m.filterInput.Value()
 			var cmd tea.Cmd
 			m.filterInput, cmd = m.filterInput.Update(msg)
+			if m.filterInput.Value() != previous {
+				m.liveFilter()
+			}
 			return m, cmd
 		}
 	}
 
-	// Get current bookmark list (filtered or full)
-	bookmarks := m.collection.Bookmarks
-	if m.filteredBookmarks != nil {
-		bookmarks = m.filteredBookmarks
+	if m.folderPane && m.folderFocus {
+		return m.updateFolderPane(msg)
 	}
-	bookmarkCount := len(bookmarks)
 
-	pageSize := 10
-	halfPage := pageSize / 2
+	// Get current bookmark list (filtered or full)
+	bookmarkCount := len(m.visibleBookmarks())
 
-	switch msg.String() {
-	case "/":
+	pageSize := m.pageSize()
+	halfPage := max(1, pageSize/2)
+
+	keys := m.keys.Browser
+	switch {
+	case key.Matches(msg, keys.Filter):
 		// Enter filter mode
 		m.filterMode = true
 		m.filterInput.Focus()
 		return m, nil
-	case "up", "k":
+	case key.Matches(msg, keys.Up):
 		if m.browserSelected > 0 {
 			m.browserSelected--
 			// Scroll up if needed
@@ -422,73 +563,138 @@ func (m Model) updateBrowser(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 				m.browserOffset = m.browserSelected
 			}
 		}
-	case "down", "j":
+	case key.Matches(msg, keys.Down):
 		if m.browserSelected < bookmarkCount-1 {
 			m.browserSelected++
-			// Scroll down if needed (show 10 items at a time)
+			// Scroll down if needed
 			if m.browserSelected >= m.browserOffset+pageSize {
 				m.browserOffset = m.browserSelected - (pageSize - 1)
 			}
 		}
-	case "g":
-		// Go to top
+	case key.Matches(msg, keys.Top):
 		m.browserSelected = 0
 		m.browserOffset = 0
-	case "G":
-		// Go to bottom
+	case key.Matches(msg, keys.Bottom):
 		m.browserSelected = bookmarkCount - 1
 		m.browserOffset = max(0, m.browserSelected-(pageSize-1))
-	case "J":
-		// Scroll down half page (Shift-J for bigger jump)
+	case key.Matches(msg, keys.HalfPageDown):
 		m.browserSelected = min(m.browserSelected+halfPage, bookmarkCount-1)
 		// Center the selection in the window
 		m.browserOffset = max(0, min(m.browserSelected-(pageSize/2), bookmarkCount-pageSize))
-	case "K":
-		// Scroll up half page (Shift-K for bigger jump)
+	case key.Matches(msg, keys.HalfPageUp):
 		m.browserSelected = max(0, m.browserSelected-halfPage)
 		// Center the selection in the window
 		m.browserOffset = max(0, min(m.browserSelected-(pageSize/2), bookmarkCount-pageSize))
-	case "h":
-		// Scroll up full page - land at top of new page
+	case key.Matches(msg, keys.PageUp):
+		// Land at top of new page
 		m.browserOffset = max(0, m.browserOffset-pageSize)
 		m.browserSelected = m.browserOffset
-	case "H":
-		// Scroll down full page - land at top of new page
+	case key.Matches(msg, keys.PageDown):
+		// Land at top of new page
 		m.browserOffset = min(m.browserOffset+pageSize, bookmarkCount-pageSize)
 		if m.browserOffset < 0 {
 			m.browserOffset = 0
 		}
 		m.browserSelected = m.browserOffset
-	case " ":
+	case key.Matches(msg, keys.Preview):
 		// Toggle detail preview overlay
 		m.currentView = DetailView
 		return m, nil
+	case key.Matches(msg, keys.Duplicates):
+		// Review duplicate groups
+		m.openDuplicates()
+		return m, nil
+	case key.Matches(msg, keys.Save):
+		m.saveCollection()
+		return m, nil
+	case key.Matches(msg, keys.Export):
+		// Export the collection to a new file
+		m.openExport(m.collection)
+		return m, nil
+	case key.Matches(msg, keys.Mark):
+		// Mark the current bookmark and move on to the next
+		m.toggleMark()
+		if m.browserSelected < bookmarkCount-1 {
+			m.browserSelected++
+			if m.browserSelected >= m.browserOffset+pageSize {
+				m.browserOffset = m.browserSelected - (pageSize - 1)
+			}
+		}
+	case key.Matches(msg, keys.MarkAll):
+		m.markAllVisible()
+	case key.Matches(msg, keys.Invert):
+		m.invertMarks()
+	case key.Matches(msg, keys.ClearMarks):
+		m.marked = make(map[*bookmark.Bookmark]bool)
+	case key.Matches(msg, keys.Bulk):
+		m.openBulkMenu()
+	case key.Matches(msg, keys.Sort):
+		m.cycleSort()
+	case key.Matches(msg, keys.Folders):
+		m.toggleFolderPane()
+	case key.Matches(msg, keys.Tags):
+		m.openTags()
+		return m, nil
+	case key.Matches(msg, keys.Candidates):
+		// Review fuzzy matches the merge didn't apply
+		m.openCandidates()
+		return m, nil
+	case key.Matches(msg, keys.Open):
+		if bm := m.selectedBookmark(); bm != nil {
+			return m, m.openBookmarks([]*bookmark.Bookmark{bm})
+		}
+	case key.Matches(msg, keys.OpenMarked):
+		// Open every marked bookmark
+		if len(m.marked) == 0 {
+			m.statusMsg = "No bookmarks marked (m: mark, *: mark all, ~: invert)"
+			return m, nil
+		}
+		return m, m.openBookmarks(m.markedBookmarks())
+	case key.Matches(msg, keys.CopyURL):
+		m.copyURLs(m.actionTargets())
+	case key.Matches(msg, keys.CopyTitle):
+		m.copyTitles(m.actionTargets())
+	case key.Matches(msg, keys.CopyLink):
+		m.copyLinks(m.actionTargets())
+	case key.Matches(msg, keys.FocusFolders):
+		if m.folderPane {
+			m.folderFocus = true
+		}
+	case key.Matches(msg, keys.Undo):
+		m.undo()
+	case key.Matches(msg, keys.Redo):
+		m.redo()
 	}
 
 	return m, nil
 }
 
 func (m Model) updateDetail(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
-	switch msg.String() {
-	case " ":
+	if m.editing {
+		return m.updateEdit(msg)
+	}
+
+	keys := m.keys.Detail
+	switch {
+	case key.Matches(msg, keys.Close):
 		// Close preview overlay and return to browser
 		m.currentView = BrowserView
-		return m, nil
-	case "enter":
-		// Open URL in default browser
-		bookmarks := m.collection.Bookmarks
-		if m.filteredBookmarks != nil {
-			bookmarks = m.filteredBookmarks
-		}
-		if m.browserSelected < len(bookmarks) {
-			bm := bookmarks[m.browserSelected]
-			if bm.URL != "" {
-				// Open URL in default browser (macOS uses 'open')
-				cmd := exec.Command("open", bm.URL)
-				return m, tea.ExecProcess(cmd, nil)
-			}
+	case key.Matches(msg, keys.Edit):
+		// Edit the bookmark in place
+		m.startEditing()
+	case key.Matches(msg, keys.Save):
+		m.saveCollection()
+	case key.Matches(msg, keys.Open):
+		// Open URL in the browser
+		if bm := m.selectedBookmark(); bm != nil {
+			return m, m.openBookmarks([]*bookmark.Bookmark{bm})
 		}
-		return m, nil
+	case key.Matches(msg, keys.CopyURL):
+		m.copyURLs(m.selectedBookmarks())
+	case key.Matches(msg, keys.CopyTitle):
+		m.copyTitles(m.selectedBookmarks())
+	case key.Matches(msg, keys.CopyLink):
+		m.copyLinks(m.selectedBookmarks())
 	}
 
 	return m, nil
@@ -515,6 +721,15 @@ func renderKeybindings(bindings string) string {
 
 // View renders the current view
 func (m Model) View() string {
+	view := m.currentViewContent()
+	if m.quitPending {
+		view += "\n  ⚠️  Unsaved changes will be lost; press " + m.quitKey() + " again to quit\n"
+	}
+	return view
+}
+
+// currentViewContent renders the view selected by currentView
+func (m Model) currentViewContent() string {
 	switch m.currentView {
 	case WelcomeView:
 		return m.welcomeView()
@@ -524,6 +739,16 @@ func (m Model) View() string {
 		return m.browserView()
 	case DetailView:
 		return m.detailView()
+	case DuplicatesView:
+		return m.duplicatesView()
+	case ExportView:
+		return m.exportView()
+	case TagsView:
+		return m.tagsView()
+	case CandidatesView:
+		return m.candidatesView()
+	case HelpView:
+		return m.helpView()
 	default:
 		return "Unknown view"
 	}
@@ -531,7 +756,7 @@ func (m Model) View() string {
 
 func (m Model) welcomeView() string {
 	if m.err != nil {
-		return fmt.Sprintf("Error: %v\n\nPress q to quit", m.err)
+		return fmt.Sprintf("Error: %v\n\nPress %s to quit", m.err, m.quitKey())
 	}
 
 	s := "\n"
@@ -568,7 +793,7 @@ func (m Model) welcomeView() string {
 	}
 
 	s += "\n"
-	help := `j/k: down/up  enter: select  q: quit`
+	help := "j/k: down/up  enter: select  " + m.quitKey() + ": quit"
 	s += renderKeybindings(help)
 	s += "\n"
 
@@ -591,7 +816,7 @@ func (m Model) fileSelectionView() string {
 		s += "  Enter a directory path or individual file path to scan for bookmarks.\n"
 		s += "  Supported: Anybox JSON, Anybox HTML, Firefox HTML, Safari HTML\n\n"
 		s += "  Path: " + m.pathInput.View() + "\n\n"
-		s += "  Press enter to scan  |  ctrl+r to reset  |  q to quit\n"
+		s += "  Press enter to scan  |  ctrl+r to reset  |  ctrl+c to quit\n"
 
 	case selectionMode:
 		files := m.fileDiscovery.Files()
@@ -614,9 +839,12 @@ func (m Model) fileSelectionView() string {
 			s += fmt.Sprintf("%s%s %-12s  %s\n", cursor, marker, formatStr, file.Path)
 		}
 
-		s += "\n"
-		help := `j/k: down/up  space: toggle  b: mark as base
-enter: continue  ctrl+r: reset  q: quit`
+		fuzzy := "off"
+		if m.fuzzyMerge {
+			fuzzy = "on"
+		}
+		s += "\n  Fuzzy matching of unknown URLs: " + fuzzy + "\n\n"
+		help := "j/k: down/up  space: toggle  b: mark as base  f: fuzzy matching\nenter: continue  ctrl+r: reset  " + m.quitKey() + ": quit"
 		s += renderKeybindings(help)
 		s += "\n"
 	}
@@ -656,14 +884,25 @@ func (m *Model) loadAndMergeFiles() error {
 		sourceCollections = append(sourceCollections, coll)
 	}
 
-	// Perform merge
+	// Perform merge, falling back to fuzzy matching for URLs the base doesn't
+	// know when it was turned on
 	merger := merge.New(baseCollection, sourceCollections...)
+	if m.fuzzyMerge {
+		merger.EnableFuzzy(merge.FuzzyOptions{})
+	}
 	merged, err := merger.Merge()
 	if err != nil {
 		return fmt.Errorf("merge operation failed: %w", err)
 	}
 
 	m.collection = merged
+	m.fuzzyIndex, m.fulltextIndex = nil, nil
+	m.folderFilter = nil
+	m.resetFolderTree()
+	m.sortCollection()
+	m.loadHistory(baseFile.Path)
+	report := merger.Report()
+	m.mergeReport = &report
 
 	// Save session for future continuation
 	if err := m.saveSession(); err != nil {
@@ -696,6 +935,11 @@ func (m *Model) loadCollectionFromSession() error {
 	}
 
 	m.collection = collection
+	m.fuzzyIndex, m.fulltextIndex = nil, nil
+	m.folderFilter = nil
+	m.resetFolderTree()
+	m.sortCollection()
+	m.loadHistory(m.currentSession.CurrentFile)
 	return nil
 }
 
@@ -737,7 +981,11 @@ func (m *Model) saveSession() error {
 		sourcePaths[i] = sf.Path
 	}
 
-	sess.AddMergeRecord(baseFile.Path, sourcePaths, len(m.collection.Bookmarks))
+	enhanced := len(m.collection.Bookmarks)
+	if m.mergeReport != nil {
+		enhanced = m.mergeReport.Enhanced
+	}
+	sess.AddMergeRecord(baseFile.Path, sourcePaths, enhanced)
 
 	m.currentSession = sess
 	return m.sessionMgr.Save(sess)
@@ -751,17 +999,8 @@ func (m *Model) loadFile(f *DiscoveredFile) (*bookmark.Collection, error) {
 	}
 	defer file.Close()
 
-	var imp importer.Importer
-	switch f.Format {
-	case FormatAnybox:
-		imp = &importer.AnyboxImporter{}
-	case FormatAnyboxHTML:
-		imp = &importer.AnyboxHTMLImporter{}
-	case FormatFirefox:
-		imp = &importer.FirefoxImporter{}
-	case FormatSafari:
-		imp = &importer.SafariImporter{}
-	default:
+	imp, ok := importer.ForSource(string(f.Format))
+	if !ok {
 		return nil, fmt.Errorf("unknown format: %s", f.Format)
 	}
 
@@ -770,44 +1009,33 @@ func (m *Model) loadFile(f *DiscoveredFile) (*bookmark.Collection, error) {
 
 func (m Model) browserView() string {
 	if m.collection == nil {
-		return "\n" + titleStyle.Render("📖 Bookmark Browser") + "\n\n  No collection loaded.\n\n  Press q to quit\n"
+		return "\n" + titleStyle.Render("📖 Bookmark Browser") + "\n\n  No collection loaded.\n\n  Press " + m.quitKey() + " to quit\n"
 	}
 
-	var s strings.Builder
-
-	// Header
-	s.WriteString("\n")
-	s.WriteString(headerStyle.Render("📖 Bookmark Browser"))
-	s.WriteString("\n\n")
+	l := m.layout()
+	bookmarks := m.visibleBookmarks()
 
-	// Determine which bookmarks to show
-	bookmarks := m.collection.Bookmarks
-	if m.filteredBookmarks != nil {
-		bookmarks = m.filteredBookmarks
+	// Keep the selection on screen even if the page size changed since the
+	// last key press (a status line appeared, the terminal was resized, ...)
+	start := m.browserOffset
+	if m.browserSelected >= start+l.pageSize {
+		start = m.browserSelected - l.pageSize + 1
 	}
+	start = max(0, min(start, m.browserSelected))
+	end := min(start+l.pageSize, len(bookmarks))
 
-	// Stats
-	stats := fmt.Sprintf("Total: %d bookmarks │ Selected: %d/%d",
-		len(bookmarks), m.browserSelected+1, len(bookmarks))
-	if m.filteredBookmarks != nil {
-		stats = fmt.Sprintf("Filtered: %d/%d bookmarks │ Selected: %d/%d",
-			len(m.filteredBookmarks), len(m.collection.Bookmarks), m.browserSelected+1, len(bookmarks))
+	// Mark column only appears once something is marked
+	markWidth := 0
+	if len(m.marked) > 0 {
+		markWidth = 2
 	}
-	s.WriteString("  " + statStyle.Render(stats) + "\n")
-
-	// Filter input (if active)
-	if m.filterMode {
-		s.WriteString("\n  " + m.filterInput.View() + "\n")
-		s.WriteString(renderKeybindings("enter: apply  esc: cancel"))
-		s.WriteString("\n")
+	titleWidth, textWidth := 0, 0
+	if l.listWidth > 0 {
+		titleWidth = max(1, l.listWidth-4-markWidth)
+		textWidth = max(1, l.listWidth-6)
 	}
-	s.WriteString("\n")
-
-	// Show window of bookmarks (10 at a time)
-	pageSize := 10
-	start := m.browserOffset
-	end := min(start+pageSize, len(bookmarks))
 
+	var list strings.Builder
 	for i := start; i < end; i++ {
 		bm := bookmarks[i]
 
@@ -815,93 +1043,95 @@ func (m Model) browserView() string {
 		if title == "" {
 			title = "(no title)"
 		}
+		title, positions := truncateHighlighted(title, m.filterHighlights[bm], titleWidth)
 
-		// Render item
+		mark := ""
+		if markWidth > 0 {
+			mark = "  "
+			if m.marked[bm] {
+				mark = markStyle.Render("✓ ")
+			}
+		}
+
+		// Render item, highlighting fuzzy-matched characters
 		if i == m.browserSelected {
-			s.WriteString("  ▶ " + selectedItemStyle.Render(title) + "\n")
+			list.WriteString("  ▶ " + mark + highlightTitle(title, positions, selectedItemStyle) + "\n")
 		} else {
-			s.WriteString("    " + title + "\n")
+			list.WriteString("    " + mark + highlightTitle(title, positions, lipgloss.NewStyle()) + "\n")
 		}
 
 		if bm.URL != "" {
-			s.WriteString("      " + urlStyle.Render(bm.URL) + "\n")
+			list.WriteString("      " + urlStyle.Render(truncate(bm.URL, textWidth)) + "\n")
 		}
-		s.WriteString("\n")
+		if m.fulltextTerms != nil {
+			if snippet := fulltext.BookmarkSnippet(bm, m.fulltextTerms, snippetWidth); snippet.Text != "" {
+				text, positions := truncateHighlighted(snippet.Text, snippetPositions(snippet), textWidth)
+				list.WriteString("      " + highlightTitle(text, positions, snippetStyle) + "\n")
+			}
+		}
+		list.WriteString("\n")
 	}
 
-	// Help text
-	help := `Navigation:
-  j: down           k: up
-  J: half-page down K: half-page up
-  H: page down      h: page up
-  G: bottom         g: top
+	// The folder pane sits left of the list and the detail pane right of it
+	listHeight := l.pageSize * m.itemHeight()
+	columns := []string{}
+	if m.folderPane {
+		columns = append(columns, m.folderPaneView(listHeight), " ")
+	}
+	listColumn := strings.TrimSuffix(list.String(), "\n")
+	if l.listWidth > 0 {
+		listColumn = lipgloss.NewStyle().Width(l.listWidth).Render(listColumn)
+	}
+	columns = append(columns, listColumn)
+	if l.detailWidth > 0 {
+		columns = append(columns, m.detailPane(l.detailWidth, listHeight))
+	}
 
-space: preview  /: filter  q: quit`
-	s.WriteString(renderKeybindings(help))
+	var s strings.Builder
+	s.WriteString(l.top)
+	s.WriteString(lipgloss.JoinHorizontal(lipgloss.Top, columns...) + "\n")
+	s.WriteString(l.help)
 
 	return s.String()
 }
 
-// applyFilter filters bookmarks based on search query
-func (m *Model) applyFilter() {
-	query := strings.ToLower(strings.TrimSpace(m.filterInput.Value()))
-	if query == "" {
-		m.filteredBookmarks = nil
-		return
+func (m Model) detailView() string {
+	if m.editing {
+		return m.editView()
 	}
 
-	var filtered []*bookmark.Bookmark
-	for _, bm := range m.collection.Bookmarks {
-		// Search in title, URL, description, tags
-		if strings.Contains(strings.ToLower(bm.Title), query) ||
-			strings.Contains(strings.ToLower(bm.URL), query) ||
-			strings.Contains(strings.ToLower(bm.Description), query) ||
-			strings.Contains(strings.ToLower(bm.Comment), query) ||
-			m.matchesTag(bm, query) {
-			filtered = append(filtered, bm)
-		}
+	bm := m.selectedBookmark()
+	if bm == nil {
+		return "\nNo bookmark selected\n\nPress space to return"
 	}
 
-	m.filteredBookmarks = filtered
-	m.browserSelected = 0
-	m.browserOffset = 0
-}
+	var s strings.Builder
 
-// matchesTag checks if any tag contains the query
-func (m *Model) matchesTag(bm *bookmark.Bookmark, query string) bool {
-	for _, tagHierarchy := range bm.Tags {
-		for _, tag := range tagHierarchy {
-			if strings.Contains(strings.ToLower(tag), query) {
-				return true
-			}
-		}
+	// Header
+	s.WriteString("\n")
+	s.WriteString(headerStyle.Render("📑 Bookmark Details" + m.dirtyMarker()))
+	s.WriteString("\n\n")
+	if status := m.statusLine(); status != "" {
+		s.WriteString(status + "\n")
 	}
-	return false
-}
 
-func (m Model) detailView() string {
-	if m.collection == nil || len(m.collection.Bookmarks) == 0 {
-		return "\nNo bookmark selected\n\nPress esc to return"
+	details := bookmarkDetails(bm)
+	if m.width > 0 {
+		details = lipgloss.NewStyle().Width(m.width - 2).Render(details)
 	}
+	s.WriteString(details + "\n\n")
 
-	// Get bookmark from filtered list if active
-	bookmarks := m.collection.Bookmarks
-	if m.filteredBookmarks != nil {
-		bookmarks = m.filteredBookmarks
-	}
+	// Help
+	s.WriteString(renderKeybindings(renderHelp(m.keys.helpGroups("detail"), m.width)))
 
-	if m.browserSelected >= len(bookmarks) {
-		return "\nNo bookmark selected\n\nPress esc to return"
-	}
+	return s.String()
+}
 
-	bm := bookmarks[m.browserSelected]
+// bookmarkDetails renders every field of a bookmark for the detail view and
+// the browser's detail pane
+func bookmarkDetails(bm *bookmark.Bookmark) string {
 	var s strings.Builder
 
-	// Header
-	s.WriteString("\n")
-	s.WriteString(headerStyle.Render("📑 Bookmark Details"))
-	s.WriteString("\n\n")
-
 	// Title
 	title := bm.Title
 	if title == "" {
@@ -911,47 +1141,53 @@ func (m Model) detailView() string {
 
 	// URL
 	if bm.URL != "" {
-		s.WriteString("  " + lipgloss.NewStyle().Foreground(lipgloss.Color("241")).Render("URL:") + "\n")
+		s.WriteString("  " + labelStyle.Render("URL:") + "\n")
 		s.WriteString("  " + urlStyle.Render(bm.URL) + "\n\n")
 	}
 
+	// Canonical URL, when the page names another one
+	if bm.Canonical != "" && bm.Canonical != bm.URL {
+		s.WriteString("  " + labelStyle.Render("Canonical URL:") + "\n")
+		s.WriteString("  " + urlStyle.Render(bm.Canonical) + "\n\n")
+	}
+
 	// Description
 	if bm.Description != "" {
-		s.WriteString("  " + lipgloss.NewStyle().Foreground(lipgloss.Color("241")).Render("Description:") + "\n")
+		s.WriteString("  " + labelStyle.Render("Description:") + "\n")
 		s.WriteString("  " + bm.Description + "\n\n")
 	}
 
 	// Comment
 	if bm.Comment != "" {
-		s.WriteString("  " + lipgloss.NewStyle().Foreground(lipgloss.Color("241")).Render("Comment:") + "\n")
+		s.WriteString("  " + labelStyle.Render("Comment:") + "\n")
 		s.WriteString("  " + bm.Comment + "\n\n")
 	}
 
 	// Tags
 	if len(bm.Tags) > 0 {
-		s.WriteString("  " + lipgloss.NewStyle().Foreground(lipgloss.Color("241")).Render("Tags:") + "\n")
+		s.WriteString("  " + labelStyle.Render("Tags:") + "\n")
 		for _, tagHierarchy := range bm.Tags {
 			tagStr := strings.Join(tagHierarchy, " → ")
-			s.WriteString("    • " + lipgloss.NewStyle().Foreground(lipgloss.Color("170")).Render(tagStr) + "\n")
+			s.WriteString("    • " + tagStyle.Render(tagStr) + "\n")
 		}
 		s.WriteString("\n")
 	}
 
 	// Folder
 	if len(bm.Folder) > 0 {
-		s.WriteString("  " + lipgloss.NewStyle().Foreground(lipgloss.Color("241")).Render("Folder:") + "\n")
+		s.WriteString("  " + labelStyle.Render("Folder:") + "\n")
 		folderStr := strings.Join(bm.Folder, " / ")
 		s.WriteString("    📁 " + folderStr + "\n\n")
 	}
 
 	// Dates
 	if !bm.DateAdded.IsZero() {
-		s.WriteString("  " + lipgloss.NewStyle().Foreground(lipgloss.Color("241")).Render("Date Added:") + "\n")
+		s.WriteString("  " + labelStyle.Render("Date Added:") + "\n")
 		s.WriteString("    " + bm.DateAdded.Format("2006-01-02 15:04:05") + "\n\n")
 	}
 
 	if !bm.LastModified.IsZero() {
-		s.WriteString("  " + lipgloss.NewStyle().Foreground(lipgloss.Color("241")).Render("Last Modified:") + "\n")
+		s.WriteString("  " + labelStyle.Render("Last Modified:") + "\n")
 		s.WriteString("    " + bm.LastModified.Format("2006-01-02 15:04:05") + "\n\n")
 	}
 
@@ -961,15 +1197,49 @@ func (m Model) detailView() string {
 	}
 
 	if bm.Keyword != "" {
-		s.WriteString("  " + lipgloss.NewStyle().Foreground(lipgloss.Color("241")).Render("Keyword:") + " " + bm.Keyword + "\n\n")
+		s.WriteString("  " + labelStyle.Render("Keyword:") + " " + bm.Keyword + "\n\n")
 	}
 
 	if bm.Source != "" {
-		s.WriteString("  " + lipgloss.NewStyle().Foreground(lipgloss.Color("241")).Render("Source:") + " " + bm.Source + "\n\n")
+		s.WriteString("  " + labelStyle.Render("Source:") + " " + bm.Source + "\n\n")
 	}
 
-	// Help
-	s.WriteString(renderKeybindings("space: close preview  enter: open in browser  q: quit"))
+	if status := bm.LinkStatus; status != nil {
+		s.WriteString("  " + labelStyle.Render("Link Check:") + "\n")
+		result := "✅ " + strconv.Itoa(status.StatusCode)
+		switch {
+		case status.StatusCode == 0:
+			result = "💀 " + status.Error
+		case status.Dead():
+			result = "💀 " + strconv.Itoa(status.StatusCode)
+		}
+		s.WriteString("    " + result + " on " + status.CheckedAt.Format("2006-01-02") + "\n")
+		for i, r := range status.Redirects {
+			target := status.FinalURL
+			if i+1 < len(status.Redirects) {
+				target = status.Redirects[i+1].URL
+			}
+			if target != "" {
+				s.WriteString("    ↪️  " + strconv.Itoa(r.StatusCode) + " " + urlStyle.Render(target) + "\n")
+			}
+		}
+		s.WriteString("\n")
+	}
 
-	return s.String()
+	if len(bm.Aliases) > 0 {
+		s.WriteString("  " + labelStyle.Render("Former URLs:") + "\n")
+		for _, alias := range bm.Aliases {
+			s.WriteString("    " + urlStyle.Render(alias) + "\n")
+		}
+		s.WriteString("\n")
+	}
+
+	if snapshot := bm.Snapshot; snapshot != nil {
+		s.WriteString("  " + labelStyle.Render("Snapshot:") + "\n")
+		size := strconv.FormatInt((snapshot.Size+1023)/1024, 10) + " KB"
+		s.WriteString("    📦 " + snapshot.Format + ", " + size + " on " + snapshot.ArchivedAt.Format("2006-01-02") + "\n")
+		s.WriteString("    " + snapshot.Path + "\n\n")
+	}
+
+	return strings.TrimRight(s.String(), "\n")
 }
@@ -0,0 +1,122 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/lelopez-io/moxli/internal/bookmark"
+	"github.com/lelopez-io/moxli/internal/clipboard"
+)
+
+// openedMsg reports the outcome of opening bookmarks in the browser
+type openedMsg struct {
+	count int
+	err   error
+}
+
+// actionTargets returns the marked bookmarks, or the one under the cursor
+// when none are marked
+func (m Model) actionTargets() []*bookmark.Bookmark {
+	if marked := m.markedBookmarks(); len(marked) > 0 {
+		return marked
+	}
+	return m.selectedBookmarks()
+}
+
+// openBookmarks opens each bookmark's URL in the browser
+func (m Model) openBookmarks(bookmarks []*bookmark.Bookmark) tea.Cmd {
+	var urls []string
+	for _, bm := range bookmarks {
+		if bm.URL != "" {
+			urls = append(urls, bm.URL)
+		}
+	}
+	if len(urls) == 0 {
+		return nil
+	}
+
+	opener := m.opener
+	return func() tea.Msg {
+		for i, url := range urls {
+			if err := opener.Open(url); err != nil {
+				return openedMsg{count: i, err: err}
+			}
+		}
+		return openedMsg{count: len(urls)}
+	}
+}
+
+// handleOpened shows the outcome of openBookmarks
+func (m *Model) handleOpened(msg openedMsg) {
+	if msg.err != nil {
+		m.err = fmt.Errorf("failed to open in browser: %w", msg.err)
+		return
+	}
+	m.statusMsg = fmt.Sprintf("Opened %d bookmark(s) in the browser", msg.count)
+}
+
+// copyBookmarks copies one line per bookmark, built by format, to the
+// clipboard
+func (m *Model) copyBookmarks(what string, bookmarks []*bookmark.Bookmark, format func(*bookmark.Bookmark) string) {
+	if len(bookmarks) == 0 {
+		return
+	}
+	lines := make([]string, len(bookmarks))
+	for i, bm := range bookmarks {
+		lines[i] = format(bm)
+	}
+
+	method, err := clipboard.Write(strings.Join(lines, "\n"))
+	if err != nil {
+		m.err = err
+		return
+	}
+	if len(bookmarks) > 1 {
+		what = fmt.Sprintf("%d %ss", len(bookmarks), what)
+	}
+	m.statusMsg = fmt.Sprintf("Copied %s to the %s", what, method)
+}
+
+// selectedBookmarks returns the bookmark under the cursor as a list
+func (m Model) selectedBookmarks() []*bookmark.Bookmark {
+	if bm := m.selectedBookmark(); bm != nil {
+		return []*bookmark.Bookmark{bm}
+	}
+	return nil
+}
+
+// copyURLs copies the bookmarks' URLs
+func (m *Model) copyURLs(bookmarks []*bookmark.Bookmark) {
+	m.copyBookmarks("URL", bookmarks, func(bm *bookmark.Bookmark) string { return bm.URL })
+}
+
+// copyTitles copies the bookmarks' titles
+func (m *Model) copyTitles(bookmarks []*bookmark.Bookmark) {
+	m.copyBookmarks("title", bookmarks, func(bm *bookmark.Bookmark) string { return bm.Title })
+}
+
+// copyLinks copies the bookmarks as Markdown links, as a list when there
+// are several
+func (m *Model) copyLinks(bookmarks []*bookmark.Bookmark) {
+	format := markdownLink
+	if len(bookmarks) > 1 {
+		format = func(bm *bookmark.Bookmark) string { return "- " + markdownLink(bm) }
+	}
+	m.copyBookmarks("Markdown link", bookmarks, format)
+}
+
+var (
+	markdownTextEscaper = strings.NewReplacer(`\`, `\\`, "[", `\[`, "]", `\]`)
+	markdownURLEscaper  = strings.NewReplacer(" ", "%20", "(", "%28", ")", "%29")
+)
+
+// markdownLink formats a bookmark as a Markdown link
+func markdownLink(bm *bookmark.Bookmark) string {
+	title := bm.Title
+	if title == "" {
+		title = bm.URL
+	}
+	return "[" + markdownTextEscaper.Replace(title) + "](" + markdownURLEscaper.Replace(bm.URL) + ")"
+}
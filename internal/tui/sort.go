@@ -0,0 +1,55 @@
+package tui
+
+import (
+	"slices"
+
+	"github.com/lelopez-io/moxli/internal/bookmark"
+)
+
+// cycleSort switches to the next sort mode, keeping the cursor on the
+// selected bookmark
+func (m *Model) cycleSort() {
+	selected := m.selectedBookmark()
+	m.sortMode = m.sortMode.Next()
+	m.sortCollection()
+	if m.filteredBookmarks != nil {
+		// Re-run the filter so file order restores the relevance ranking
+		m.liveFilter()
+	}
+	m.selectBookmark(selected)
+	m.statusMsg = "Sorted by " + m.sortMode.String()
+}
+
+// sortCollection recomputes the sorted view of the whole collection
+func (m *Model) sortCollection() {
+	m.sortedBookmarks = nil
+	if m.collection != nil && m.sortMode != bookmark.SortFileOrder {
+		m.sortedBookmarks = bookmark.Sort(m.collection.Bookmarks, m.sortMode)
+	}
+}
+
+// resort re-applies the sort mode after a bookmark was edited, keeping the
+// cursor on the selected bookmark
+func (m *Model) resort() {
+	if m.sortMode == bookmark.SortFileOrder {
+		return
+	}
+	selected := m.selectedBookmark()
+	m.sortCollection()
+	if m.filteredBookmarks != nil {
+		m.filteredBookmarks = bookmark.Sort(m.filteredBookmarks, m.sortMode)
+	}
+	m.selectBookmark(selected)
+}
+
+// selectBookmark moves the browser cursor to bm when it is listed
+func (m *Model) selectBookmark(bm *bookmark.Bookmark) {
+	i := slices.Index(m.visibleBookmarks(), bm)
+	if bm == nil || i < 0 {
+		return
+	}
+	m.browserSelected = i
+	if pageSize := m.pageSize(); i < m.browserOffset || i >= m.browserOffset+pageSize {
+		m.browserOffset = max(0, i-(pageSize-1))
+	}
+}
@@ -0,0 +1,228 @@
+package tui
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/lelopez-io/moxli/internal/bookmark"
+)
+
+// tagPageSize is the number of tags listed at once
+const tagPageSize = 20
+
+// tagRow is a tag as listed in the tags view
+type tagRow struct {
+	node  *bookmark.TagNode
+	depth int
+}
+
+// openTags shows the tag hierarchy of the collection
+func (m *Model) openTags() {
+	m.tagTree = m.collection.TagTree()
+	m.tagSelected = 0
+	m.tagOffset = 0
+	m.currentView = TagsView
+}
+
+// tagRows lists the expanded part of the tag tree, or every tag by
+// frequency in flat mode
+func (m Model) tagRows() []tagRow {
+	if m.tagTree == nil {
+		return nil
+	}
+
+	var rows []tagRow
+	if m.tagFlat {
+		m.tagTree.Walk(func(n *bookmark.TagNode, depth int) {
+			if depth > 0 {
+				rows = append(rows, tagRow{node: n})
+			}
+		})
+		slices.SortStableFunc(rows, func(a, b tagRow) int {
+			return cmp.Compare(b.node.Total, a.node.Total)
+		})
+		return rows
+	}
+
+	var walk func(n *bookmark.TagNode, depth int)
+	walk = func(n *bookmark.TagNode, depth int) {
+		rows = append(rows, tagRow{node: n, depth: depth})
+		if m.tagExpanded[pathKey(n.Path)] {
+			for _, c := range n.Children {
+				walk(c, depth+1)
+			}
+		}
+	}
+	for _, n := range m.tagTree.Children {
+		walk(n, 0)
+	}
+	return rows
+}
+
+func (m Model) updateTags(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc", "t":
+		m.currentView = BrowserView
+		return m, nil
+	case "v":
+		// Switch between the tree and the frequency list
+		m.tagFlat = !m.tagFlat
+		m.tagSelected = 0
+		m.tagOffset = 0
+		return m, nil
+	}
+
+	rows := m.tagRows()
+	if len(rows) == 0 {
+		return m, nil
+	}
+	m.tagSelected = min(m.tagSelected, len(rows)-1)
+	node := rows[m.tagSelected].node
+
+	switch msg.String() {
+	case "up", "k":
+		if m.tagSelected > 0 {
+			m.tagSelected--
+		}
+	case "down", "j":
+		if m.tagSelected < len(rows)-1 {
+			m.tagSelected++
+		}
+	case "g":
+		m.tagSelected = 0
+	case "G":
+		m.tagSelected = len(rows) - 1
+	case "right", "l":
+		if !m.tagFlat && len(node.Children) > 0 {
+			m.tagExpanded[pathKey(node.Path)] = true
+		}
+	case "left", "h":
+		if m.tagFlat {
+			break
+		}
+		// Collapse, or jump to the parent of a collapsed tag
+		if m.tagExpanded[pathKey(node.Path)] {
+			delete(m.tagExpanded, pathKey(node.Path))
+		} else if len(node.Path) > 1 {
+			parent := node.Path[:len(node.Path)-1]
+			for i, row := range rows {
+				if slices.Equal(row.node.Path, parent) {
+					m.tagSelected = i
+					break
+				}
+			}
+		}
+	case "enter":
+		m.filterByTag(node.Path)
+		return m, nil
+	}
+
+	if m.tagSelected < m.tagOffset {
+		m.tagOffset = m.tagSelected
+	} else if m.tagSelected >= m.tagOffset+tagPageSize {
+		m.tagOffset = m.tagSelected - tagPageSize + 1
+	}
+	return m, nil
+}
+
+// filterByTag returns to the browser filtered to a tag and the tags nested
+// under it
+func (m *Model) filterByTag(path []string) {
+	m.filterInput.SetValue("tag:" + queryValue(strings.Join(path, "/")))
+	m.currentView = BrowserView
+	m.applyFilter()
+}
+
+// queryValue quotes a filter value when it would otherwise be split
+func queryValue(value string) string {
+	if strings.ContainsAny(value, " \"()") {
+		value = `"` + strings.ReplaceAll(value, `"`, "") + `"`
+	}
+	return value
+}
+
+func (m Model) tagsView() string {
+	var s strings.Builder
+
+	s.WriteString("\n")
+	s.WriteString(headerStyle.Render("🏷️  Tags"))
+	s.WriteString("\n\n")
+
+	// Stats over every tag, whatever is expanded
+	tags, orphans, singles := 0, 0, 0
+	if m.tagTree != nil {
+		m.tagTree.Walk(func(n *bookmark.TagNode, depth int) {
+			if depth == 0 {
+				return
+			}
+			tags++
+			if n.Orphan {
+				orphans++
+			}
+			if n.SingleUse() {
+				singles++
+			}
+		})
+	}
+	mode := "tree"
+	if m.tagFlat {
+		mode = "by frequency"
+	}
+	stats := fmt.Sprintf("Tags: %d │ Orphans: %d │ Single use: %d │ View: %s", tags, orphans, singles, mode)
+	s.WriteString("  " + statStyle.Render(stats) + "\n\n")
+
+	rows := m.tagRows()
+	if len(rows) == 0 {
+		s.WriteString("  No tagged bookmarks\n")
+	}
+
+	end := min(m.tagOffset+tagPageSize, len(rows))
+	for i := m.tagOffset; i < end; i++ {
+		node, depth := rows[i].node, rows[i].depth
+
+		arrow := ""
+		name := strings.Join(node.Path, "/")
+		if !m.tagFlat {
+			arrow = "  "
+			if len(node.Children) > 0 {
+				arrow = "▸ "
+				if m.tagExpanded[pathKey(node.Path)] {
+					arrow = "▾ "
+				}
+			}
+			name = node.Name
+		}
+
+		var note string
+		switch {
+		case node.Orphan:
+			name = orphanStyle.Render(name)
+			note = orphanStyle.Render("  orphan")
+		case node.SingleUse():
+			name = urlStyle.Render(name)
+			note = urlStyle.Render("  single use")
+		}
+		if i == m.tagSelected {
+			name = selectedItemStyle.Render(name)
+		}
+
+		cursor := "    "
+		if i == m.tagSelected {
+			cursor = "  ▶ "
+		}
+		s.WriteString(cursor + strings.Repeat("  ", depth) + arrow + name +
+			urlStyle.Render(fmt.Sprintf(" (%d)", node.Total)) + note + "\n")
+	}
+
+	s.WriteString("\n  " + urlStyle.Render("orphan: top-level tag also nested under another tag") + "\n")
+
+	help := `j/k: move  l/h: expand/collapse  v: tree/frequency
+enter: filter bookmarks by tag  esc: back`
+	s.WriteString(renderKeybindings(help))
+
+	return s.String()
+}
@@ -0,0 +1,165 @@
+package tui
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/lelopez-io/moxli/internal/config"
+)
+
+// palette holds the colours every style is built from
+type palette struct {
+	Title     lipgloss.Color // View titles
+	Accent    lipgloss.Color // Borders and stats
+	Selected  lipgloss.Color // Selected items and tags
+	Muted     lipgloss.Color // URLs, labels and help
+	Highlight lipgloss.Color // Search matches and orphan tags
+	Mark      lipgloss.Color // Marked bookmarks
+	Snippet   lipgloss.Color // Full-text snippets
+}
+
+// palettes are the themes that can be picked by name in the config file
+var palettes = map[string]palette{
+	"dark": {
+		Title:     "205",
+		Accent:    "63",
+		Selected:  "170",
+		Muted:     "241",
+		Highlight: "214",
+		Mark:      "42",
+		Snippet:   "246",
+	},
+	"light": {
+		Title:     "161",
+		Accent:    "25",
+		Selected:  "127",
+		Muted:     "243",
+		Highlight: "166",
+		Mark:      "28",
+		Snippet:   "240",
+	},
+	"high-contrast": {
+		Title:     "13",
+		Accent:    "14",
+		Selected:  "11",
+		Muted:     "15",
+		Highlight: "9",
+		Mark:      "10",
+		Snippet:   "7",
+	},
+}
+
+// theme is the palette in use, set by applyTheme
+var theme = palettes["dark"]
+
+// colorPattern matches #rgb and #rrggbb colours
+var colorPattern = regexp.MustCompile(`^#([0-9a-fA-F]{3}|[0-9a-fA-F]{6})$`)
+
+// newPalette resolves the configured theme: the named palette with any
+// colour overrides applied
+func newPalette(cfg config.Theme) (palette, error) {
+	name := cfg.Name
+	if name == "" {
+		name = "dark"
+	}
+	p, ok := palettes[name]
+	if !ok {
+		return palette{}, fmt.Errorf("unknown theme %q (want %s)", name, strings.Join(paletteNames(), ", "))
+	}
+
+	roles := map[string]*lipgloss.Color{
+		"title":     &p.Title,
+		"accent":    &p.Accent,
+		"selected":  &p.Selected,
+		"muted":     &p.Muted,
+		"highlight": &p.Highlight,
+		"mark":      &p.Mark,
+		"snippet":   &p.Snippet,
+	}
+	for role, value := range cfg.Colors {
+		color, ok := roles[role]
+		if !ok {
+			return palette{}, fmt.Errorf("unknown theme colour %q", role)
+		}
+		if !validColor(value) {
+			return palette{}, fmt.Errorf("invalid %s colour %q: want an ANSI number (0-255) or #rrggbb", role, value)
+		}
+		*color = lipgloss.Color(value)
+	}
+
+	return p, nil
+}
+
+// paletteNames returns the theme names in sorted order
+func paletteNames() []string {
+	names := make([]string, 0, len(palettes))
+	for name := range palettes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// validColor reports whether value is an ANSI colour number or a hex colour
+func validColor(value string) bool {
+	if n, err := strconv.Atoi(value); err == nil {
+		return n >= 0 && n <= 255
+	}
+	return colorPattern.MatchString(value)
+}
+
+// applyTheme rebuilds the shared styles from p
+func applyTheme(p palette) {
+	theme = p
+
+	titleStyle = lipgloss.NewStyle().
+		Bold(true).
+		Foreground(p.Title).
+		Padding(0, 1)
+
+	headerStyle = lipgloss.NewStyle().
+		BorderStyle(lipgloss.RoundedBorder()).
+		BorderForeground(p.Accent).
+		Padding(0, 1)
+
+	selectedItemStyle = lipgloss.NewStyle().
+		Foreground(p.Selected).
+		Bold(true)
+
+	urlStyle = lipgloss.NewStyle().
+		Foreground(p.Muted).
+		Italic(true)
+
+	helpStyle = lipgloss.NewStyle().
+		Foreground(p.Muted).
+		Padding(1, 0, 0, 2)
+
+	statStyle = lipgloss.NewStyle().
+		Foreground(p.Accent)
+
+	matchStyle = lipgloss.NewStyle().
+		Foreground(p.Highlight).
+		Bold(true).
+		Underline(true)
+
+	markStyle = lipgloss.NewStyle().
+		Foreground(p.Mark).
+		Bold(true)
+
+	snippetStyle = lipgloss.NewStyle().
+		Foreground(p.Snippet)
+
+	labelStyle = lipgloss.NewStyle().
+		Foreground(p.Muted)
+
+	tagStyle = lipgloss.NewStyle().
+		Foreground(p.Selected)
+
+	orphanStyle = lipgloss.NewStyle().
+		Foreground(p.Highlight)
+}